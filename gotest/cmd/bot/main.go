@@ -1,32 +1,31 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Встроенная база часовых поясов (для расписаний на серверах без tzdata)
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"telegram-bot/internal/config"
+	"telegram-bot/internal/domain"
 	"telegram-bot/internal/handler"
 	"telegram-bot/internal/keyboard"
 	"telegram-bot/internal/middleware"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
 )
 
-// userNotifications хранит состояние уведомлений для каждого пользователя
-// Ключ - chatID, значение - включены ли уведомления
-var (
-	userNotifications = make(map[int64]bool)
-	notificationsMu   sync.RWMutex // Мьютекс для безопасного доступа к map
-)
-
-// userLanguages хранит выбранный язык для каждого пользователя
-// Ключ - chatID, значение - код языка (ru, en, zh)
-var (
-	userLanguages = make(map[int64]string)
-	languagesMu   sync.RWMutex // Мьютекс для безопасного доступа к map
-)
+// settingsRepo хранит настройки пользователей (уведомления и язык)
+// Ключ - chatID; если настройки не сохранены, используются значения по умолчанию
+var settingsRepo *repository.SettingsRepository
 
 // NavigationState хранит состояние навигации для возврата назад
 type NavigationState struct {
@@ -42,19 +41,16 @@ var (
 	navigationMu          sync.RWMutex // Мьютекс для безопасного доступа к map
 )
 
-// coursesList содержит список всех курсов
-var coursesList = []keyboard.Course{
-	{ID: 1, Title: "Go для начинающих", Description: "Изучите основы языка Go"},
-	{ID: 2, Title: "Продвинутый Go", Description: "Углублённое изучение Go"},
-	{ID: 3, Title: "Telegram Bot API", Description: "Создание ботов на Go"},
-	{ID: 4, Title: "Базы данных в Go", Description: "Работа с PostgreSQL и MySQL"},
-	{ID: 5, Title: "Микросервисы на Go", Description: "Архитектура микросервисов"},
-	{ID: 6, Title: "Тестирование в Go", Description: "Unit и интеграционные тесты"},
-	{ID: 7, Title: "Конкурентность в Go", Description: "Goroutines и Channels"},
-	{ID: 8, Title: "REST API на Go", Description: "Создание RESTful API"},
-	{ID: 9, Title: "Docker и Go", Description: "Контейнеризация приложений"},
-	{ID: 10, Title: "Deployment Go приложений", Description: "Развёртывание на сервере"},
-}
+// courseService предоставляет каталог курсов для экранов просмотра
+var courseService *service.CourseService
+
+// Сервисы экрана курса: кнопка записи видна только при включённом флаге функции,
+// платный курс без доступа предлагается купить
+var (
+	enrollmentService *service.EnrollmentService
+	featureService    *service.FeatureService
+	paymentService    *service.PaymentService
+)
 
 // userCoursesPage хранит текущую страницу курсов для каждого пользователя
 // Ключ - chatID, значение - номер страницы (начинается с 0)
@@ -70,6 +66,190 @@ func main() {
 		log.Fatal("Ошибка загрузки конфигурации:", err)
 	}
 
+	// Открываем файловое хранилище и репозитории
+	store, err := repository.NewJSONStore(cfg.Storage.Dir)
+	if err != nil {
+		log.Fatal("Ошибка открытия хранилища:", err)
+	}
+
+	auditRepo, err := repository.NewAuditRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки журнала аудита:", err)
+	}
+
+	// Журнал аудита: сервисы записывают в него действия администраторов
+	audit := service.NewAuditService(auditRepo)
+
+	roleRepo, err := repository.NewRoleRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки ролей:", err)
+	}
+
+	// Сервис ролей: владельцы берутся из ADMIN_IDS, остальные роли — из хранилища
+	access := service.NewAccessService(cfg.Bot.AdminIDs, roleRepo, audit)
+
+	userRepo, err := repository.NewUserRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки пользователей:", err)
+	}
+
+	settingsRepo, err = repository.NewSettingsRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки настроек пользователей:", err)
+	}
+
+	eventRepo, err := repository.NewEventRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки статистики событий:", err)
+	}
+
+	banRepo, err := repository.NewBanRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки блокировок:", err)
+	}
+
+	// Сервис блокировок пользователей
+	bans := service.NewBanService(banRepo, access, audit)
+
+	maintenanceRepo, err := repository.NewMaintenanceRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки режима обслуживания:", err)
+	}
+
+	// Режим обслуживания: включается командой /maintenance или MAINTENANCE_MODE
+	maintenance := service.NewMaintenanceService(maintenanceRepo, settingsRepo, access, audit, cfg.Maintenance.Message)
+	if cfg.Maintenance.Enabled && !maintenance.Enabled() {
+		if err := maintenance.SetEnabled(0, true); err != nil {
+			log.Fatal("Ошибка включения режима обслуживания:", err)
+		}
+	}
+
+	featureRepo, err := repository.NewFeatureRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки флагов функций:", err)
+	}
+
+	// Флаги функций: постепенное включение новых экранов для части пользователей
+	featureService = service.NewFeatureService(featureRepo, settingsRepo, audit)
+
+	// Каталог курсов: хранилище выбирается через COURSE_STORAGE
+	courseRepo, err := newCourseRepository(cfg, store)
+	if err != nil {
+		log.Fatal("Ошибка открытия хранилища курсов:", err)
+	}
+	courseService = service.NewCourseService(courseRepo, audit)
+	if err := courseService.SeedIfEmpty(repository.DefaultCourses()); err != nil {
+		log.Fatal("Ошибка заполнения каталога курсов:", err)
+	}
+
+	enrollmentRepo, err := repository.NewEnrollmentRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки записей на курсы:", err)
+	}
+
+	lessonRepo, err := repository.NewLessonRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки уроков:", err)
+	}
+
+	// Уроки курсов: выдаются записанным пользователям по расписанию курса
+	lessons := service.NewLessonService(lessonRepo, courseService, audit)
+	if err := lessons.SeedIfEmpty(repository.DefaultLessons()); err != nil {
+		log.Fatal("Ошибка заполнения уроков:", err)
+	}
+
+	// Запись на курсы и прогресс пользователей
+	enrollmentService = service.NewEnrollmentService(enrollmentRepo, courseService)
+	enrollmentService.SetLessonSource(lessons)
+
+	subscriptionRepo, err := repository.NewSubscriptionRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки подписок:", err)
+	}
+
+	// Подписки: платные курсы доступны пользователям с действующей подпиской
+	subscriptions := service.NewSubscriptionService(subscriptionRepo, audit, cfg.Subscription.RemindDays)
+	if err := subscriptions.SeedIfEmpty(repository.DefaultPlans()); err != nil {
+		log.Fatal("Ошибка заполнения тарифных планов:", err)
+	}
+
+	orderRepo, err := repository.NewOrderRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки заказов:", err)
+	}
+
+	// Платежи: купленный курс доступен так же, как курс по подписке
+	paymentService = service.NewPaymentService(orderRepo, courseService, enrollmentService, subscriptions, audit)
+	enrollmentService.SetCourseAccess(paymentService)
+
+	// Курс с записями или оплаченными заказами нельзя удалить, только снять с публикации
+	courseService.AddUsage(enrollmentService)
+	courseService.AddUsage(paymentService)
+
+	quizRepo, err := repository.NewQuizRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки тестов:", err)
+	}
+
+	// Тесты в конце уроков: результаты хранятся в записи на курс
+	quizzes := service.NewQuizService(quizRepo, lessons, enrollmentService, audit)
+	if err := quizzes.SeedIfEmpty(repository.DefaultQuizzes()); err != nil {
+		log.Fatal("Ошибка заполнения тестов:", err)
+	}
+
+	certificateRepo, err := repository.NewCertificateRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки сертификатов:", err)
+	}
+
+	// Сертификаты о прохождении курсов
+	certificates, err := service.NewCertificateService(certificateRepo, enrollmentService, courseService, cfg.Certificate.Format)
+	if err != nil {
+		log.Fatal("Ошибка настройки сертификатов:", err)
+	}
+
+	dialogRepo, err := repository.NewDialogRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки диалогов:", err)
+	}
+
+	// Многошаговые диалоги: состояние сохраняется и переживает перезапуск бота
+	dialogs := service.NewDialogService(dialogRepo, cfg.Dialog.Timeout)
+
+	ticketRepo, err := repository.NewTicketRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки обращений:", err)
+	}
+
+	// Обращения в поддержку
+	support := service.NewSupportService(ticketRepo, audit)
+
+	faqRepo, err := repository.NewFAQRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки автоответов:", err)
+	}
+
+	// Автоответы на текстовые сообщения
+	faq := service.NewFAQService(faqRepo, audit)
+	if err := faq.SeedIfEmpty(repository.DefaultFAQRules()); err != nil {
+		log.Fatal("Ошибка заполнения автоответов:", err)
+	}
+	if cfg.FAQ.Fallback != handler.FallbackEcho && cfg.FAQ.Fallback != handler.FallbackSupport {
+		log.Fatal("Некорректный FAQ_FALLBACK: ", cfg.FAQ.Fallback)
+	}
+
+	// Сервис статистики для админ-панели
+	// Счётчики событий сохраняются раз в минуту и при остановке бота
+	stats := service.NewStatsService(userRepo, settingsRepo, eventRepo)
+	stats.StartFlush()
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		stats.Flush()
+		os.Exit(0)
+	}()
+
 	// Создаём экземпляр бота
 	bot, err := tgbotapi.NewBotAPI(cfg.Bot.Token)
 	if err != nil {
@@ -79,17 +259,156 @@ func main() {
 	bot.Debug = cfg.Bot.Debug
 	log.Printf("Авторизован как %s", bot.Self.UserName)
 
+	broadcastRepo, err := repository.NewBroadcastRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки рассылок:", err)
+	}
+
+	// Сервис рассылок: продолжаем рассылки, прерванные перезапуском
+	broadcasts := service.NewBroadcastService(bot, broadcastRepo, userRepo, settingsRepo, audit, cfg.Bot.BroadcastRate)
+	broadcasts.SetEnrollmentSource(enrollmentService)
+	broadcasts.Resume()
+
+	location, err := time.LoadLocation(cfg.Bot.TimeZone)
+	if err != nil {
+		log.Fatal("Ошибка загрузки часового пояса:", err)
+	}
+
+	scheduleRepo, err := repository.NewScheduleRepository(store)
+	if err != nil {
+		log.Fatal("Ошибка загрузки запланированных рассылок:", err)
+	}
+
+	// Планировщик запускает запланированные и повторяющиеся рассылки
+	scheduler := service.NewSchedulerService(scheduleRepo, broadcasts, audit, location)
+	scheduler.Start()
+
 	// Создаём диспетчер обработчиков
-	dispatcher := handler.NewDispatcher()
+	dispatcher := handler.NewDispatcher(access)
 
 	// Регистрируем обработчики команд
 	dispatcher.Register(handler.NewStartHandler())
 	dispatcher.Register(handler.NewHelpHandler())
 	dispatcher.Register(handler.NewInfoHandler())
-	dispatcher.Register(handler.NewAdminHandler(cfg.Bot.AdminIDs))
+
+	// Регистрируем диалоги (команда /cancel, кнопки dlg_* и ответы на шаги сценариев)
+	fsm := handler.NewFSM(dialogs)
+	dispatcher.Register(fsm)
+	dispatcher.RegisterCallback(fsm)
+
+	// Регистрируем поддержку (команды /support, /tickets, кнопки sup_* и tkt_*)
+	// Ответы реплаем на сообщения обращений обрабатываются раньше шагов диалогов
+	supportRelay := handler.NewSupportRelay(support, access, cfg.Support.ChatID, cfg.Support.TopicID)
+	supportAdminHandler := handler.NewSupportAdminHandler(support, supportRelay, access, fsm)
+	dispatcher.Register(supportAdminHandler)
+	dispatcher.RegisterCallback(supportAdminHandler)
+	dispatcher.RegisterInput(supportAdminHandler)
+	supportHandler := handler.NewSupportHandler(support, supportRelay, fsm)
+	dispatcher.Register(supportHandler)
+	dispatcher.RegisterCallback(supportHandler)
+	dispatcher.RegisterInput(supportHandler)
+
+	dispatcher.RegisterInput(fsm)
+	fsm.StartTimeouts(bot)
+
+	// Регистрируем форму отзыва (команда /feedback)
+	dispatcher.Register(handler.NewFeedbackHandler(fsm, access, settingsRepo))
+
+	// Регистрируем админ-панель (команда /admin и кнопки admin_*)
+	adminHandler := handler.NewAdminHandler(stats)
+	dispatcher.Register(adminHandler)
+	dispatcher.RegisterCallback(adminHandler)
+
+	// Регистрируем рассылки (команда /broadcast, кнопки bc_* и шаги составления)
+	broadcastHandler := handler.NewBroadcastHandler(broadcasts, scheduler)
+	dispatcher.Register(broadcastHandler)
+	dispatcher.RegisterCallback(broadcastHandler)
+	dispatcher.RegisterInput(broadcastHandler)
+	fsm.AddDrafts(broadcastHandler)
+
+	// Регистрируем управление запланированными рассылками (команда /schedules и кнопки sch_*)
+	scheduleHandler := handler.NewScheduleHandler(scheduler)
+	dispatcher.Register(scheduleHandler)
+	dispatcher.RegisterCallback(scheduleHandler)
+
+	// Регистрируем управление пользователями (команда /user, кнопки usr_* и поиск)
+	userAdminHandler := handler.NewUserAdminHandler(userRepo, settingsRepo, access, bans, audit)
+	dispatcher.Register(userAdminHandler)
+	dispatcher.RegisterCallback(userAdminHandler)
+	dispatcher.RegisterInput(userAdminHandler)
+	fsm.AddDrafts(userAdminHandler)
+
+	// Регистрируем управление курсами (команда /managecourses, кнопки crs_* и формы курса)
+	courseAdminHandler := handler.NewCourseAdminHandler(courseService, fsm)
+	dispatcher.Register(courseAdminHandler)
+	dispatcher.RegisterCallback(courseAdminHandler)
+
+	// Регистрируем поиск по каталогу (команда /courses, кнопки srch_* и ввод запроса)
+	courseSearchHandler := handler.NewCourseSearchHandler(courseService, settingsRepo, fsm)
+	dispatcher.Register(courseSearchHandler)
+	dispatcher.RegisterCallback(courseSearchHandler)
+
+	// Регистрируем запись на курсы (команда /mycourses и кнопки enr_*)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService, certificates, featureService, settingsRepo)
+	dispatcher.Register(enrollmentHandler)
+	dispatcher.RegisterCallback(enrollmentHandler)
+
+	// Регистрируем выдачу уроков (кнопки les_*) и управление уроками (кнопки lsa_* и шаги ввода)
+	dispatcher.RegisterCallback(handler.NewLessonHandler(enrollmentService, quizzes, certificates, featureService, settingsRepo))
+	lessonAdminHandler := handler.NewLessonAdminHandler(lessons, courseService)
+	dispatcher.RegisterCallback(lessonAdminHandler)
+	dispatcher.RegisterInput(lessonAdminHandler)
+	fsm.AddDrafts(lessonAdminHandler)
+
+	// Регистрируем тесты (кнопки qz_* и ответы в опросах) и управление ими (команда /quiz)
+	quizHandler := handler.NewQuizHandler(quizzes, certificates, featureService, settingsRepo)
+	dispatcher.RegisterCallback(quizHandler)
+	dispatcher.RegisterPollAnswer(quizHandler)
+	dispatcher.Register(handler.NewQuizAdminHandler(quizzes, lessons, userRepo))
+
+	// Регистрируем проверку сертификатов (команда /verify)
+	dispatcher.Register(handler.NewVerifyHandler(certificates, settingsRepo))
+
+	// Регистрируем команды блокировки пользователей
+	dispatcher.Register(handler.NewBanHandler(userRepo, bans))
+	dispatcher.Register(handler.NewUnbanHandler(userRepo, bans))
+	dispatcher.Register(handler.NewBanListHandler(bans))
+
+	// Регистрируем журнал аудита (команда /audit и страницы aud_*)
+	auditHandler := handler.NewAuditHandler(audit, userRepo)
+	dispatcher.Register(auditHandler)
+	dispatcher.RegisterCallback(auditHandler)
+
+	// Регистрируем управление режимом обслуживания (команда /maintenance)
+	dispatcher.Register(handler.NewMaintenanceHandler(maintenance))
+
+	// Регистрируем управление флагами функций (команда /flags)
+	dispatcher.Register(handler.NewFeatureHandler(featureService, userRepo))
+
+	// Регистрируем управление автоответами (команда /faq)
+	dispatcher.Register(handler.NewFAQAdminHandler(faq))
+
+	// Регистрируем подписки (команды /premium и /subscription) и напоминания об их окончании
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptions, settingsRepo)
+	dispatcher.Register(subscriptionHandler)
+	dispatcher.Register(handler.NewSubscriptionAdminHandler(subscriptions, userRepo, settingsRepo))
+	subscriptionHandler.StartReminders(bot)
+
+	// Регистрируем оплату курсов и подписок (кнопки pay_*) и управление заказами (команда /orders)
+	paymentHandler := handler.NewPaymentHandler(paymentService, courseService, subscriptions, settingsRepo, cfg.Payment.ProviderToken)
+	dispatcher.RegisterCallback(paymentHandler)
+	dispatcher.Register(handler.NewPaymentAdminHandler(paymentService, userRepo, settingsRepo))
+
+	// Регистрируем команды управления ролями
+	dispatcher.Register(handler.NewGrantRoleHandler(access))
+	dispatcher.Register(handler.NewRevokeRoleHandler(access))
+	dispatcher.Register(handler.NewRolesHandler(access))
 
 	// Создаём обработчик обычных сообщений
-	messageHandler := handler.NewMessageHandler()
+	messageHandler := handler.NewMessageHandler(faq, cfg.FAQ.Fallback)
+
+	// Защита от флуда: ограничивает частоту сообщений, команд и нажатий на кнопки
+	flood := middleware.NewFloodGuard(cfg.Flood)
 
 	// Настраиваем получение обновлений
 	u := tgbotapi.NewUpdate(0)
@@ -98,7 +417,25 @@ func main() {
 
 	// Обрабатываем обновления
 	for update := range updates {
-		handleUpdate(bot, dispatcher, messageHandler, update)
+		handleUpdate(bot, dispatcher, messageHandler, paymentHandler, stats, bans, flood, maintenance, subscriptions, cfg.Subscription.Commands, update)
+	}
+}
+
+// newCourseRepository создаёт хранилище курсов, выбранное в конфигурации
+func newCourseRepository(cfg *config.Config, store *repository.JSONStore) (repository.CourseRepository, error) {
+	switch cfg.Storage.Courses {
+	case "memory":
+		return repository.NewMemoryCourseRepository(), nil
+	case "file":
+		return repository.NewFileCourseRepository(store)
+	case "postgres":
+		db, err := repository.NewPostgresDB(cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewPostgresCourseRepository(db.DB)
+	default:
+		return nil, fmt.Errorf("неизвестное хранилище курсов %q (memory, file или postgres)", cfg.Storage.Courses)
 	}
 }
 
@@ -106,11 +443,69 @@ func handleUpdate(
 	bot *tgbotapi.BotAPI,
 	dispatcher *handler.Dispatcher,
 	messageHandler *handler.MessageHandler,
+	paymentHandler *handler.PaymentHandler,
+	stats *service.StatsService,
+	bans *service.BanService,
+	flood *middleware.FloodGuard,
+	maintenance *service.MaintenanceService,
+	subscriptions *service.SubscriptionService,
+	premiumCommands []string,
 	update tgbotapi.Update,
 ) {
+	// Платежи обрабатываются до остальных проверок: на pre_checkout_query нужно ответить за 10 секунд,
+	// а после successful_payment деньги уже списаны и доступ нужно выдать в любом случае
+	if update.PreCheckoutQuery != nil {
+		if err := paymentHandler.HandlePreCheckout(bot, update.PreCheckoutQuery); err != nil {
+			log.Printf("Ошибка проверки оплаты: %v", err)
+			stats.Track(domain.EventError, "")
+		}
+		return
+	}
+	if update.Message != nil && update.Message.SuccessfulPayment != nil {
+		if err := paymentHandler.HandleSuccessfulPayment(bot, update.Message); err != nil {
+			log.Printf("Ошибка обработки платежа: %v", err)
+			stats.Track(domain.EventError, "")
+		}
+		return
+	}
+
+	// Заблокированные пользователи не проходят дальше (получают одно уведомление)
+	if !middleware.RequireNotBanned(bot, update, bans) {
+		return
+	}
+
+	// Слишком частые обновления не обрабатываются (пользователь получает просьбу подождать)
+	if !flood.Allow(bot, update) {
+		return
+	}
+
+	// Во время обслуживания обычные пользователи получают только сообщение о работах
+	if !middleware.RequireNotMaintenance(bot, update, maintenance) {
+		return
+	}
+
+	// Премиум-команды доступны только по подписке
+	if !middleware.RequireSubscription(bot, update, subscriptions, premiumCommands) {
+		return
+	}
+
 	// Обрабатываем callback-запросы (нажатия на инлайн-кнопки)
 	if update.CallbackQuery != nil {
-		handleCallbackQuery(bot, update.CallbackQuery)
+		stats.TrackUser(update.CallbackQuery.From)
+		stats.Track(domain.EventCallback, update.CallbackQuery.Data)
+		if err := handleCallbackQuery(bot, dispatcher, update.CallbackQuery); err != nil {
+			log.Printf("Ошибка обработки callback: %v", err)
+			stats.Track(domain.EventError, "")
+		}
+		return
+	}
+
+	// Обрабатываем ответы в опросах (тесты в режиме опросов Telegram)
+	if update.PollAnswer != nil {
+		if _, err := dispatcher.HandlePollAnswer(bot, update.PollAnswer); err != nil {
+			log.Printf("Ошибка обработки ответа в опросе: %v", err)
+			stats.Track(domain.EventError, "")
+		}
 		return
 	}
 
@@ -120,28 +515,46 @@ func handleUpdate(
 	}
 
 	msg := update.Message
+	stats.TrackUser(msg.From)
 
 	if msg.IsCommand() {
 		middleware.LogCommand(msg)
+		// Неизвестные команды не учитываются: иначе любой пользователь может завести сколько угодно счётчиков
+		if dispatcher.Known(msg.Command()) {
+			stats.Track(domain.EventCommand, msg.Command())
+		}
 		err := dispatcher.HandleCommand(bot, msg)
 		if err != nil {
 			log.Printf("Ошибка обработки команды: %v", err)
+			stats.Track(domain.EventError, "")
+		}
+		return
+	}
+
+	// Сообщение может быть ответом в многошаговом сценарии (например, составление рассылки)
+	if handled, err := dispatcher.HandleInput(bot, msg); handled {
+		if err != nil {
+			log.Printf("Ошибка обработки ответа: %v", err)
+			stats.Track(domain.EventError, "")
 		}
 		return
 	}
 
 	if msg.Text != "" {
 		middleware.LogMessage(msg)
+		stats.Track(domain.EventMessage, "")
 		// Передаём функции получения состояния уведомлений и языка
 		err := messageHandler.Handle(bot, msg, getNotificationState, getLanguage)
 		if err != nil {
 			log.Printf("Ошибка обработки сообщения: %v", err)
+			stats.Track(domain.EventError, "")
 		}
 	}
 }
 
 // handleCallbackQuery обрабатывает нажатие на инлайн-кнопку
-func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) {
+// Возвращает ошибку обработчика, зарегистрированного в диспетчере
+func handleCallbackQuery(bot *tgbotapi.BotAPI, dispatcher *handler.Dispatcher, callback *tgbotapi.CallbackQuery) error {
 	data := callback.Data
 	chatID := callback.Message.Chat.ID
 	messageID := callback.Message.MessageID
@@ -149,14 +562,6 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 
 	log.Printf("Callback от пользователя %d: %s", userID, data)
 
-	// Отвечаем на callback-запрос (обязательно!)
-	// Сначала отвечаем пустым ответом, затем при необходимости обновим с текстом
-	callbackConfig := tgbotapi.NewCallback(callback.ID, "")
-	if _, err := bot.Request(callbackConfig); err != nil {
-		log.Printf("Ошибка ответа на callback: %v", err)
-		return
-	}
-
 	// Сохраняем текущее состояние перед переходом (если это не кнопка "назад")
 	if data != "nav_back" {
 		currentText := callback.Message.Text
@@ -165,6 +570,19 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 		saveNavigationState(chatID, currentText, currentKeyboard, messageID)
 	}
 
+	// Маршруты, зарегистрированные в диспетчере, сами отвечают на callback
+	if handled, err := dispatcher.HandleCallback(bot, callback); handled {
+		return err
+	}
+
+	// Отвечаем на callback-запрос (обязательно!)
+	// Сначала отвечаем пустым ответом, затем при необходимости обновим с текстом
+	callbackConfig := tgbotapi.NewCallback(callback.ID, "")
+	if _, err := bot.Request(callbackConfig); err != nil {
+		log.Printf("Ошибка ответа на callback: %v", err)
+		return nil
+	}
+
 	// Обрабатываем данные в зависимости от префикса
 	switch {
 	case strings.HasPrefix(data, "delete_profile_"):
@@ -185,12 +603,19 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 	case strings.HasPrefix(data, "courses_"):
 		handleCoursesNavigation(bot, callback.ID, chatID, messageID, data)
 
+	case strings.HasPrefix(data, "course_cover_"):
+		handleCourseCover(bot, callback.ID, chatID, data)
+
 	case strings.HasPrefix(data, "course_"):
 		handleCourseDetails(bot, callback.ID, chatID, messageID, data)
 
 	case data == "nav_back":
 		handleBackNavigation(bot, chatID, messageID)
 
+	case data == "nav_close":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		bot.Request(tgbotapi.NewDeleteMessage(chatID, messageID))
+
 	default:
 		// Обработка неизвестных callback-запросов
 		log.Printf("Неизвестный callback-запрос: %s от пользователя %d", data, userID)
@@ -198,6 +623,8 @@ func handleCallbackQuery(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery)
 		callbackConfig := tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда")
 		bot.Request(callbackConfig)
 	}
+
+	return nil
 }
 
 // handleMainMenuNavigation обрабатывает навигацию из главного меню
@@ -250,11 +677,11 @@ func handleMenuSettings(bot *tgbotapi.BotAPI, chatID int64, messageID int) {
 		"Выберите настройку для изменения:"
 
 	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
-	btnNotif := tgbotapi.NewInlineKeyboardButtonData("🔔 Уведомления", "settings_notif")
-	btnLang := tgbotapi.NewInlineKeyboardButtonData("🌐 Язык", "settings_lang")
-	row := tgbotapi.NewInlineKeyboardRow(btnNotif, btnLang)
-	kb := tgbotapi.NewInlineKeyboardMarkup(row)
-	kb = keyboard.AddBackButton(kb)
+	kb := keyboard.NewBuilder().Columns(2).
+		Button("🔔 Уведомления", "settings_notif").
+		Button("🌐 Язык", "settings_lang").
+		Footer(keyboard.FooterBack, keyboard.FooterClose).
+		MustBuild()
 	edit.ReplyMarkup = &kb
 	bot.Send(edit)
 }
@@ -272,64 +699,68 @@ func handleMenuCourses(bot *tgbotapi.BotAPI, chatID int64, messageID int) {
 
 // showCoursesPage показывает курсы на указанной странице
 func showCoursesPage(bot *tgbotapi.BotAPI, chatID int64, messageID int, page int) {
-	const itemsPerPage = 3 // Количество курсов на странице
+	courses, err := courseService.Catalog()
+	if err != nil {
+		log.Printf("Ошибка загрузки каталога курсов: %v", err)
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Не удалось загрузить курсы. Попробуйте позже.")
+		bot.Send(edit)
+		return
+	}
+
+	// Собираем текст и клавиатуру текущей страницы
+	view, err := keyboard.NewCoursesPaginator(getLanguage(chatID)).Render(keyboard.SliceSource(courses), page)
+	if err != nil {
+		log.Printf("Ошибка формирования страницы курсов: %v", err)
+		return
+	}
 
 	// Обновляем текущую страницу пользователя
 	coursesPageMu.Lock()
-	userCoursesPage[chatID] = page
+	userCoursesPage[chatID] = view.Number
 	coursesPageMu.Unlock()
 
-	// Вычисляем индексы для текущей страницы
-	startIdx := page * itemsPerPage
-	endIdx := startIdx + itemsPerPage
-	if endIdx > len(coursesList) {
-		endIdx = len(coursesList)
-	}
-
-	// Формируем текст с курсами на текущей странице
-	text := "📚 Доступные курсы:\n\n"
-	for i := startIdx; i < endIdx; i++ {
-		course := coursesList[i]
-		text += fmt.Sprintf("%d. %s\n%s\n\n", i+1, course.Title, course.Description)
-	}
-
-	// Создаём клавиатуру с пагинацией
-	kb := keyboard.NewCoursesKeyboard(coursesList, page, itemsPerPage)
+	// Добавляем поиск и кнопку "назад"
+	kb := keyboard.AddCatalogSearchRow(view.Keyboard)
 	kb = keyboard.AddBackButton(kb)
 
-	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, view.Text)
 	edit.ReplyMarkup = &kb
 	bot.Send(edit)
 }
 
+// showCoursesPicker заменяет кнопки каталога выбором номера страницы
+func showCoursesPicker(bot *tgbotapi.BotAPI, chatID int64, messageID int, page int) {
+	courses, err := courseService.Catalog()
+	if err != nil {
+		log.Printf("Ошибка загрузки каталога курсов: %v", err)
+		return
+	}
+
+	kb, err := keyboard.NewCoursesPaginator(getLanguage(chatID)).Picker(keyboard.SliceSource(courses), page)
+	if err != nil {
+		log.Printf("Ошибка формирования выбора страницы: %v", err)
+		return
+	}
+	bot.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, kb))
+}
+
 // handleCoursesNavigation обрабатывает навигацию по страницам курсов
+// Формат: courses_p_<страница> — переход, courses_pick_<страница> — выбор номера страницы
 func handleCoursesNavigation(bot *tgbotapi.BotAPI, callbackID string, chatID int64, messageID int, data string) {
-	if data == "courses_info" {
-		// Просто обновляем текущую страницу (информация о странице)
-		coursesPageMu.RLock()
-		currentPage := userCoursesPage[chatID]
-		coursesPageMu.RUnlock()
-		showCoursesPage(bot, chatID, messageID, currentPage)
+	page, picker, ok := keyboard.NewCoursesPaginator(getLanguage(chatID)).Parse(data)
+	if !ok {
+		log.Printf("Ошибка парсинга номера страницы: %s", data)
+		// Отвечаем на callback с ошибкой
+		callbackConfig := tgbotapi.NewCallback(callbackID, "❌ Ошибка навигации")
+		bot.Request(callbackConfig)
 		return
 	}
 
-	// Извлекаем номер страницы из callback data (формат: "courses_page_0")
-	if strings.HasPrefix(data, "courses_page_") {
-		var page int
-		_, err := fmt.Sscanf(data, "courses_page_%d", &page)
-		if err != nil {
-			log.Printf("Ошибка парсинга номера страницы: %v", err)
-			// Отвечаем на callback с ошибкой
-			callbackConfig := tgbotapi.NewCallback(callbackID, "❌ Ошибка навигации")
-			bot.Request(callbackConfig)
-			return
-		}
-		showCoursesPage(bot, chatID, messageID, page)
-	} else {
-		// Неизвестный callback - отвечаем с ошибкой
-		callbackConfig := tgbotapi.NewCallback(callbackID, "❌ Неизвестная команда")
-		bot.Request(callbackConfig)
+	if picker {
+		showCoursesPicker(bot, chatID, messageID, page)
+		return
 	}
+	showCoursesPage(bot, chatID, messageID, page)
 }
 
 // handleCourseDetails обрабатывает нажатие на конкретный курс
@@ -345,16 +776,12 @@ func handleCourseDetails(bot *tgbotapi.BotAPI, callbackID string, chatID int64,
 		return
 	}
 
-	// Находим курс по ID
-	var course *keyboard.Course
-	for i := range coursesList {
-		if coursesList[i].ID == courseID {
-			course = &coursesList[i]
-			break
+	// Находим курс по ID (неопубликованные курсы пользователям не показываются)
+	course, err := courseService.Published(courseID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCourseNotFound) {
+			log.Printf("Ошибка загрузки курса %d: %v", courseID, err)
 		}
-	}
-
-	if course == nil {
 		text := "❌ Курс не найден"
 		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
 		// Отвечаем на callback с ошибкой
@@ -371,13 +798,10 @@ func handleCourseDetails(bot *tgbotapi.BotAPI, callbackID string, chatID int64,
 	bot.Request(callbackConfig)
 
 	// Показываем детали курса
-	text := fmt.Sprintf("📚 %s\n\n%s", course.Title, course.Description)
-
-	// Кнопка "Назад к списку курсов"
-	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К списку курсов", "menu_courses")
-	row := tgbotapi.NewInlineKeyboardRow(btnBack)
-	kb := tgbotapi.NewInlineKeyboardMarkup(row)
-	kb = keyboard.AddBackButton(kb)
+	text := handler.CourseDetailsText(course, getLanguage(chatID))
+	_, enrolled := enrollmentService.Get(chatID, course.ID)
+	locked := !paymentService.CourseAllowed(chatID, course)
+	kb := keyboard.NewCourseDetailsKeyboard(course, featureService.Gate(chatID), enrolled, locked)
 
 	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
 	edit.ReplyMarkup = &kb
@@ -386,6 +810,28 @@ func handleCourseDetails(bot *tgbotapi.BotAPI, callbackID string, chatID int64,
 	}
 }
 
+// handleCourseCover отправляет обложку курса отдельным фото
+func handleCourseCover(bot *tgbotapi.BotAPI, callbackID string, chatID int64, data string) {
+	var courseID int
+	if _, err := fmt.Sscanf(data, "course_cover_%d", &courseID); err != nil {
+		bot.Request(tgbotapi.NewCallback(callbackID, "❌ Ошибка загрузки курса"))
+		return
+	}
+
+	course, err := courseService.Published(courseID)
+	if err != nil || course.CoverFileID == "" {
+		bot.Request(tgbotapi.NewCallback(callbackID, "❌ Обложка не найдена"))
+		return
+	}
+
+	bot.Request(tgbotapi.NewCallback(callbackID, ""))
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(course.CoverFileID))
+	photo.Caption = course.Title.Get(getLanguage(chatID))
+	if _, err := bot.Send(photo); err != nil {
+		log.Printf("Ошибка отправки обложки курса %d: %v", courseID, err)
+	}
+}
+
 // handleBackNavigation обрабатывает нажатие на кнопку "назад"
 func handleBackNavigation(bot *tgbotapi.BotAPI, chatID int64, messageID int) {
 	navigationMu.Lock()
@@ -514,10 +960,10 @@ func handleNotificationToggle(bot *tgbotapi.BotAPI, callbackID string, chatID in
 		return
 	}
 
-	// Сохраняем новое состояние в памяти
-	notificationsMu.Lock()
-	userNotifications[chatID] = newState
-	notificationsMu.Unlock()
+	// Сохраняем новое состояние в хранилище
+	if err := settingsRepo.SetNotifications(chatID, newState); err != nil {
+		log.Printf("Ошибка сохранения настроек уведомлений: %v", err)
+	}
 
 	// Отвечаем на callback с текстом (покажет уведомление пользователю)
 	callbackConfig := tgbotapi.NewCallback(callbackID, callbackText)
@@ -596,10 +1042,10 @@ func handleLanguageChange(bot *tgbotapi.BotAPI, callbackID string, chatID int64,
 		return
 	}
 
-	// Сохраняем выбранный язык в памяти
-	languagesMu.Lock()
-	userLanguages[chatID] = langCode
-	languagesMu.Unlock()
+	// Сохраняем выбранный язык в хранилище
+	if err := settingsRepo.SetLanguage(chatID, langCode); err != nil {
+		log.Printf("Ошибка сохранения языка: %v", err)
+	}
 
 	// Отвечаем на callback с текстом (покажет уведомление пользователю)
 	callbackConfig := tgbotapi.NewCallback(callbackID, callbackText)
@@ -619,27 +1065,11 @@ func handleLanguageChange(bot *tgbotapi.BotAPI, callbackID string, chatID int64,
 // getLanguage возвращает текущий язык пользователя
 // Если язык не сохранён, возвращает "ru" (по умолчанию русский)
 func getLanguage(chatID int64) string {
-	languagesMu.RLock()
-	defer languagesMu.RUnlock()
-
-	lang, exists := userLanguages[chatID]
-	if !exists {
-		// Если язык не сохранён, возвращаем значение по умолчанию (русский)
-		return "ru"
-	}
-	return lang
+	return settingsRepo.Get(chatID).Language
 }
 
 // getNotificationState возвращает текущее состояние уведомлений для пользователя
 // Если состояние не сохранено, возвращает true (по умолчанию включено)
 func getNotificationState(chatID int64) bool {
-	notificationsMu.RLock()
-	defer notificationsMu.RUnlock()
-
-	state, exists := userNotifications[chatID]
-	if !exists {
-		// Если состояние не сохранено, возвращаем значение по умолчанию (включено)
-		return true
-	}
-	return state
+	return settingsRepo.Get(chatID).NotificationsEnabled
 }
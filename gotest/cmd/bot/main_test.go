@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/config"
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/handler"
+	"telegram-bot/internal/middleware"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// Пользователи из записанных обновлений в testdata
+const (
+	testBuyerID  = 1243350911 // Покупатель из pre_checkout_query.json и successful_payment.json
+	testAdminID  = 5021749    // Сотрудник из orders_refund_command.json
+	testChargeID = "stxMcKq6A1Z5tQ3fHbLw9RjuVYd2sNpXoE8Gk4Ti7CzyPgnUhfDW0aBmr"
+)
+
+// apiCall — запрос бота к Bot API
+type apiCall struct {
+	method string
+	params url.Values
+}
+
+// fakeTelegram — Bot API на httptest: записывает запросы и отвечает заготовленными ответами
+type fakeTelegram struct {
+	mu        sync.Mutex
+	calls     []apiCall
+	responses map[string]string // Ключ - метод Bot API; остальным методам отвечает ok
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{method: method, params: r.PostForm})
+	response, ok := f.responses[method]
+	f.mu.Unlock()
+
+	if !ok {
+		switch method {
+		case "getMe":
+			response = `{"ok":true,"result":{"id":7000000001,"is_bot":true,"first_name":"Test","username":"test_bot"}}`
+		case "sendMessage", "editMessageText":
+			response = `{"ok":true,"result":{"message_id":1,"date":0,"chat":{"id":` + r.PostForm.Get("chat_id") + `,"type":"private"}}}`
+		default:
+			response = `{"ok":true,"result":true}`
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, response)
+}
+
+// requests возвращает запросы к методу method
+func (f *fakeTelegram) requests(method string) []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var params []url.Values
+	for _, call := range f.calls {
+		if call.method == method {
+			params = append(params, call.params)
+		}
+	}
+	return params
+}
+
+// paymentBot — бот с сервисами на временном хранилище и платным курсом #1 за 250 XTR
+type paymentBot struct {
+	bot            *tgbotapi.BotAPI
+	telegram       *fakeTelegram
+	dispatcher     *handler.Dispatcher
+	payments       *service.PaymentService
+	orders         *repository.OrderRepository
+	paymentHandler *handler.PaymentHandler
+	enrollments    *service.EnrollmentService
+	stats          *service.StatsService
+	bans           *service.BanService
+	flood          *middleware.FloodGuard
+	maintenance    *service.MaintenanceService
+	subs           *service.SubscriptionService
+	order          domain.Order // Заказ курса покупателем из testdata (order_1)
+}
+
+// newPaymentBot собирает обработку платежей так же, как main; responses — ответы Bot API по методам
+func newPaymentBot(t *testing.T, responses map[string]string) *paymentBot {
+	t.Helper()
+
+	telegram := &fakeTelegram{responses: responses}
+	server := httptest.NewServer(telegram)
+	t.Cleanup(server.Close)
+	bot, err := tgbotapi.NewBotAPIWithClient("TEST:TOKEN", server.URL+"/bot%s/%s", server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := repository.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	auditRepo, err := repository.NewAuditRepository(store)
+	must(err)
+	roleRepo, err := repository.NewRoleRepository(store)
+	must(err)
+	userRepo, err := repository.NewUserRepository(store)
+	must(err)
+	settings, err := repository.NewSettingsRepository(store)
+	must(err)
+	eventRepo, err := repository.NewEventRepository(store)
+	must(err)
+	banRepo, err := repository.NewBanRepository(store)
+	must(err)
+	maintenanceRepo, err := repository.NewMaintenanceRepository(store)
+	must(err)
+	subscriptionRepo, err := repository.NewSubscriptionRepository(store)
+	must(err)
+	orderRepo, err := repository.NewOrderRepository(store)
+	must(err)
+	enrollmentRepo, err := repository.NewEnrollmentRepository(store)
+	must(err)
+
+	audit := service.NewAuditService(auditRepo)
+	access := service.NewAccessService([]int64{testAdminID}, roleRepo, audit)
+	subs := service.NewSubscriptionService(subscriptionRepo, audit, nil)
+	must(subs.SeedIfEmpty(repository.DefaultPlans()))
+	courses := service.NewCourseService(repository.NewMemoryCourseRepository(), audit)
+	enrollments := service.NewEnrollmentService(enrollmentRepo, courses)
+	payments := service.NewPaymentService(orderRepo, courses, enrollments, subs, audit)
+	enrollments.SetCourseAccess(payments)
+
+	course := domain.Course{
+		Title:       domain.LocalizedText{"ru": "Go для профи"},
+		Description: domain.LocalizedText{"ru": "Платный курс"},
+		Category:    "backend",
+		Price:       250,
+		Currency:    domain.CurrencyStars,
+	}
+	must(courses.Create(testAdminID, &course))
+	must(courses.SetPublished(testAdminID, course.ID, true))
+	order, err := payments.OrderCourse(testBuyerID, course.ID)
+	must(err)
+
+	dispatcher := handler.NewDispatcher(access)
+	dispatcher.Register(handler.NewPaymentAdminHandler(payments, userRepo, settings))
+
+	return &paymentBot{
+		bot:            bot,
+		telegram:       telegram,
+		dispatcher:     dispatcher,
+		payments:       payments,
+		orders:         orderRepo,
+		paymentHandler: handler.NewPaymentHandler(payments, courses, subs, settings, ""),
+		enrollments:    enrollments,
+		stats:          service.NewStatsService(userRepo, settings, eventRepo),
+		bans:           service.NewBanService(banRepo, access, audit),
+		flood:          middleware.NewFloodGuard(config.FloodConfig{Window: time.Second, MaxMessages: 100, MaxCommands: 100, MaxCallbacks: 100}),
+		maintenance:    service.NewMaintenanceService(maintenanceRepo, settings, access, audit, ""),
+		subs:           subs,
+		order:          order,
+	}
+}
+
+// handle передаёт записанное обновление testdata/<name>.json в handleUpdate
+func (b *paymentBot) handle(t *testing.T, name string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var update tgbotapi.Update
+	if err := json.Unmarshal(data, &update); err != nil {
+		t.Fatal(err)
+	}
+	handleUpdate(b.bot, b.dispatcher, nil, b.paymentHandler, b.stats, b.bans, b.flood, b.maintenance, b.subs, nil, update)
+}
+
+// orderStatus возвращает текущее состояние заказа
+func (b *paymentBot) orderStatus(t *testing.T) domain.Order {
+	t.Helper()
+
+	order, err := b.payments.Get(b.order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestHandleUpdatePayments(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(t *testing.T, b *paymentBot)
+		updates    []string
+		wantAnswer []bool // Подтверждена ли оплата в ответах на pre_checkout_query
+		wantStatus domain.OrderStatus
+		wantSent   int // Сообщений покупателю
+	}{
+		{
+			name:       "pre-checkout accepted",
+			updates:    []string{"pre_checkout_query"},
+			wantAnswer: []bool{true},
+			wantStatus: domain.OrderPending,
+		},
+		{
+			name: "pre-checkout of expired invoice rejected",
+			prepare: func(t *testing.T, b *paymentBot) {
+				order := b.order
+				order.CreatedAt = time.Now().Add(-48 * time.Hour)
+				if err := b.orders.Update(order); err != nil {
+					t.Fatal(err)
+				}
+			},
+			updates:    []string{"pre_checkout_query"},
+			wantAnswer: []bool{false},
+			wantStatus: domain.OrderPending,
+		},
+		{
+			name:       "payment completes order",
+			updates:    []string{"pre_checkout_query", "successful_payment"},
+			wantAnswer: []bool{true},
+			wantStatus: domain.OrderPaid,
+			wantSent:   1,
+		},
+		{
+			name:       "repeated payment update",
+			updates:    []string{"pre_checkout_query", "successful_payment", "successful_payment"},
+			wantAnswer: []bool{true},
+			wantStatus: domain.OrderPaid,
+			wantSent:   2,
+		},
+		{
+			name:       "pre-checkout after payment rejected",
+			updates:    []string{"successful_payment", "pre_checkout_query"},
+			wantAnswer: []bool{false},
+			wantStatus: domain.OrderPaid,
+			wantSent:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newPaymentBot(t, nil)
+			if tt.prepare != nil {
+				tt.prepare(t, b)
+			}
+			for _, update := range tt.updates {
+				b.handle(t, update)
+			}
+
+			answers := b.telegram.requests("answerPreCheckoutQuery")
+			if len(answers) != len(tt.wantAnswer) {
+				t.Fatalf("answerPreCheckoutQuery called %d times, want %d", len(answers), len(tt.wantAnswer))
+			}
+			for i, answer := range answers {
+				// tgbotapi не передаёт ok=false: отказ — это ответ без ok, но с error_message
+				ok := answer.Get("ok") == "true"
+				if answer.Get("pre_checkout_query_id") != "5340087612984127311" || ok != tt.wantAnswer[i] {
+					t.Errorf("answer %d = %v, want ok=%v", i, answer, tt.wantAnswer[i])
+				}
+				if !ok && answer.Get("error_message") == "" {
+					t.Errorf("answer %d has no error_message", i)
+				}
+			}
+
+			order := b.orderStatus(t)
+			if order.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", order.Status, tt.wantStatus)
+			}
+			if tt.wantSent > 0 && order.TelegramChargeID != testChargeID {
+				t.Errorf("TelegramChargeID = %q, want %q", order.TelegramChargeID, testChargeID)
+			}
+			_, enrolled := b.enrollments.Get(testBuyerID, order.CourseID)
+			if want := tt.wantStatus == domain.OrderPaid; enrolled != want {
+				t.Errorf("enrolled = %v, want %v", enrolled, want)
+			}
+
+			sent := 0
+			for _, msg := range b.telegram.requests("sendMessage") {
+				if msg.Get("chat_id") == fmt.Sprint(testBuyerID) {
+					if !strings.Contains(msg.Get("text"), "Оплата получена") {
+						t.Errorf("unexpected message to buyer: %q", msg.Get("text"))
+					}
+					sent++
+				}
+			}
+			if sent != tt.wantSent {
+				t.Errorf("messages to buyer = %d, want %d", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateRefund(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string // Ответ refundStarPayment из testdata
+		wantStatus domain.OrderStatus
+	}{
+		{name: "stars refunded", response: "refund_star_payment_ok", wantStatus: domain.OrderRefunded},
+		{name: "stars already refunded", response: "refund_star_payment_already_refunded", wantStatus: domain.OrderRefunded},
+		{name: "telegram refused", response: "refund_star_payment_not_found", wantStatus: domain.OrderPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := os.ReadFile(filepath.Join("testdata", tt.response+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := newPaymentBot(t, map[string]string{"refundStarPayment": string(response)})
+
+			b.handle(t, "successful_payment")
+			b.handle(t, "orders_refund_command")
+			// Уведомление Telegram о возврате бот не обрабатывает: заказ уже отмечен командой
+			b.handle(t, "refunded_payment")
+
+			refunds := b.telegram.requests("refundStarPayment")
+			if len(refunds) != 1 {
+				t.Fatalf("refundStarPayment called %d times, want 1", len(refunds))
+			}
+			if refunds[0].Get("user_id") != fmt.Sprint(testBuyerID) || refunds[0].Get("telegram_payment_charge_id") != testChargeID {
+				t.Errorf("refundStarPayment params = %v", refunds[0])
+			}
+
+			order := b.orderStatus(t)
+			if order.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", order.Status, tt.wantStatus)
+			}
+			course := domain.Course{ID: order.CourseID, Price: order.Amount, Currency: order.Currency}
+			if allowed, want := b.payments.CourseAllowed(testBuyerID, course), tt.wantStatus == domain.OrderPaid; allowed != want {
+				t.Errorf("course allowed = %v, want %v", allowed, want)
+			}
+		})
+	}
+}
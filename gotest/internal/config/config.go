@@ -4,6 +4,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -12,17 +13,28 @@ import (
 // Config — главная структура конфигурации приложения
 // Все поля заполняются из переменных окружения
 type Config struct {
-	Bot      BotConfig      // Настройки бота
-	Database DatabaseConfig // Настройки базы данных
-	Logging  LoggingConfig  // Настройки логирования
+	Bot          BotConfig          // Настройки бота
+	Database     DatabaseConfig     // Настройки базы данных
+	Logging      LoggingConfig      // Настройки логирования
+	Storage      StorageConfig      // Настройки файлового хранилища
+	Flood        FloodConfig        // Настройки защиты от флуда
+	Maintenance  MaintenanceConfig  // Настройки режима обслуживания
+	Certificate  CertificateConfig  // Настройки сертификатов о прохождении курсов
+	Dialog       DialogConfig       // Настройки многошаговых диалогов
+	Support      SupportConfig      // Настройки поддержки
+	FAQ          FAQConfig          // Настройки автоответов
+	Subscription SubscriptionConfig // Настройки подписок
+	Payment      PaymentConfig      // Настройки оплаты
 }
 
 // BotConfig — настройки Telegram-бота
 type BotConfig struct {
-	Token    string  `envconfig:"BOT_TOKEN" required:"true"` // Токен бота (обязательный)
-	Debug    bool    `envconfig:"BOT_DEBUG" default:"false"` // Режим отладки
-	Timeout  int     `envconfig:"BOT_TIMEOUT" default:"60"`  // Таймаут запросов (секунды)
-	AdminIDs []int64 `envconfig:"ADMIN_IDS"`                 // ID администраторов
+	Token         string  `envconfig:"BOT_TOKEN" required:"true"`            // Токен бота (обязательный)
+	Debug         bool    `envconfig:"BOT_DEBUG" default:"false"`            // Режим отладки
+	Timeout       int     `envconfig:"BOT_TIMEOUT" default:"60"`             // Таймаут запросов (секунды)
+	AdminIDs      []int64 `envconfig:"ADMIN_IDS"`                            // ID администраторов
+	BroadcastRate int     `envconfig:"BROADCAST_RATE" default:"25"`          // Лимит сообщений рассылки в секунду
+	TimeZone      string  `envconfig:"BOT_TIMEZONE" default:"Europe/Moscow"` // Часовой пояс по умолчанию для расписаний
 }
 
 // DatabaseConfig — настройки подключения к PostgreSQL
@@ -41,6 +53,63 @@ type LoggingConfig struct {
 	File  string `envconfig:"LOG_FILE" default:"bot.log"` // Файл для логов
 }
 
+// StorageConfig — настройки файлового хранилища (роли, настройки пользователей и т.д.)
+type StorageConfig struct {
+	Dir     string `envconfig:"STORAGE_DIR" default:"data"`    // Каталог с JSON-файлами данных
+	Courses string `envconfig:"COURSE_STORAGE" default:"file"` // Хранилище курсов: memory, file или postgres
+}
+
+// FloodConfig — настройки защиты от флуда
+// Лимиты задаются на одного пользователя в скользящем окне Window
+type FloodConfig struct {
+	Window       time.Duration `envconfig:"FLOOD_WINDOW" default:"10s"`       // Размер скользящего окна
+	MaxMessages  int           `envconfig:"FLOOD_MAX_MESSAGES" default:"10"`  // Сообщений за окно
+	MaxCommands  int           `envconfig:"FLOOD_MAX_COMMANDS" default:"6"`   // Команд за окно
+	MaxCallbacks int           `envconfig:"FLOOD_MAX_CALLBACKS" default:"10"` // Нажатий на кнопки за окно
+	Cooldown     time.Duration `envconfig:"FLOOD_COOLDOWN" default:"10s"`     // Пауза после первого нарушения
+	MaxCooldown  time.Duration `envconfig:"FLOOD_MAX_COOLDOWN" default:"10m"` // Максимальная пауза при повторных нарушениях
+}
+
+// MaintenanceConfig — настройки режима обслуживания
+// Режим также включается и выключается командой /maintenance; состояние сохраняется в хранилище
+type MaintenanceConfig struct {
+	Enabled bool   `envconfig:"MAINTENANCE_MODE" default:"false"` // Включить режим обслуживания при запуске
+	Message string `envconfig:"MAINTENANCE_MESSAGE" default:""`   // Текст для пользователей (по умолчанию — встроенный на языке пользователя)
+}
+
+// CertificateConfig — настройки сертификатов о прохождении курсов
+type CertificateConfig struct {
+	Format string `envconfig:"CERTIFICATE_FORMAT" default:"png"` // Формат сертификата: png (фото) или pdf (документ)
+}
+
+// DialogConfig — настройки многошаговых диалогов
+type DialogConfig struct {
+	Timeout time.Duration `envconfig:"DIALOG_TIMEOUT" default:"15m"` // Время ожидания ответа пользователя по умолчанию
+}
+
+// SupportConfig — настройки поддержки
+// Если чат не задан, обращения получают в личные сообщения сотрудники с доступом к поддержке
+type SupportConfig struct {
+	ChatID  int64 `envconfig:"SUPPORT_CHAT_ID"`  // Чат поддержки для обращений (группа или форум)
+	TopicID int   `envconfig:"SUPPORT_TOPIC_ID"` // Тема форума в чате поддержки (0 — без темы)
+}
+
+// FAQConfig — настройки автоответов на текстовые сообщения
+type FAQConfig struct {
+	Fallback string `envconfig:"FAQ_FALLBACK" default:"echo"` // Ответ, если ни одно правило не подошло: echo или support
+}
+
+// SubscriptionConfig — настройки подписок
+type SubscriptionConfig struct {
+	Commands   []string `envconfig:"SUBSCRIPTION_COMMANDS"`                  // Команды без «/», доступные только по подписке
+	RemindDays []int    `envconfig:"SUBSCRIPTION_REMIND_DAYS" default:"3,1"` // За сколько дней до окончания подписки напоминать
+}
+
+// PaymentConfig — настройки оплаты через Telegram Payments
+type PaymentConfig struct {
+	ProviderToken string `envconfig:"PAYMENT_PROVIDER_TOKEN"` // Токен платёжного провайдера от @BotFather; для цен в Stars (XTR) не нужен
+}
+
 // Load загружает конфигурацию из переменных окружения
 // Сначала пытается прочитать файл .env, затем читает переменные окружения
 func Load() (*Config, error) {
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// AuditOutcome — результат действия, записанного в журнал аудита
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success" // Действие выполнено
+	AuditFailure AuditOutcome = "failure" // Действие завершилось ошибкой
+	AuditDenied  AuditOutcome = "denied"  // Недостаточно прав
+)
+
+// Действия, которые записываются в журнал аудита
+const (
+	AuditRoleGrant          = "role.grant"          // Выдача роли
+	AuditRoleRevoke         = "role.revoke"         // Отзыв роли
+	AuditUserBan            = "user.ban"            // Блокировка пользователя
+	AuditUserUnban          = "user.unban"          // Разблокировка пользователя
+	AuditUserReset          = "user.reset_settings" // Сброс настроек пользователя
+	AuditUserMessage        = "user.message"        // Личное сообщение пользователю от имени бота
+	AuditBroadcastStart     = "broadcast.start"     // Запуск рассылки
+	AuditScheduleCreate     = "schedule.create"     // Планирование рассылки
+	AuditSchedulePause      = "schedule.pause"      // Пауза запланированной рассылки
+	AuditScheduleResume     = "schedule.resume"     // Возобновление запланированной рассылки
+	AuditScheduleCancel     = "schedule.cancel"     // Отмена запланированной рассылки
+	AuditMaintenanceOn      = "maintenance.on"      // Включение режима обслуживания
+	AuditMaintenanceOff     = "maintenance.off"     // Выключение режима обслуживания
+	AuditMaintenanceMsg     = "maintenance.message" // Изменение текста режима обслуживания
+	AuditFeatureUpdate      = "feature.update"      // Изменение флага функции
+	AuditFeatureDelete      = "feature.delete"      // Удаление флага функции
+	AuditCourseCreate       = "course.create"       // Создание курса
+	AuditCourseUpdate       = "course.update"       // Изменение курса
+	AuditCourseDelete       = "course.delete"       // Удаление курса
+	AuditCoursePublish      = "course.publish"      // Публикация или снятие курса с публикации
+	AuditCourseMove         = "course.move"         // Изменение порядка курсов
+	AuditLessonCreate       = "lesson.create"       // Создание урока
+	AuditLessonUpdate       = "lesson.update"       // Изменение урока
+	AuditLessonDelete       = "lesson.delete"       // Удаление урока
+	AuditLessonMove         = "lesson.move"         // Изменение порядка уроков
+	AuditQuizUpdate         = "quiz.update"         // Создание или изменение теста
+	AuditQuizDelete         = "quiz.delete"         // Удаление теста
+	AuditQuizAttempts       = "quiz.attempts"       // Возврат пользователю попыток теста
+	AuditTicketAssign       = "ticket.assign"       // Назначение обращения в поддержку
+	AuditTicketClose        = "ticket.close"        // Закрытие обращения в поддержку
+	AuditTicketReopen       = "ticket.reopen"       // Повторное открытие обращения
+	AuditFAQCreate          = "faq.create"          // Создание правила автоответа
+	AuditFAQUpdate          = "faq.update"          // Изменение правила автоответа
+	AuditFAQDelete          = "faq.delete"          // Удаление правила автоответа
+	AuditSubscriptionGrant  = "subscription.grant"  // Выдача подписки
+	AuditSubscriptionExtend = "subscription.extend" // Продление подписки
+	AuditSubscriptionRevoke = "subscription.revoke" // Отзыв подписки
+	AuditOrderRefund        = "order.refund"        // Возврат оплаты заказа
+	AuditOrderRefundStart   = "order.refund_start"  // Начало возврата Stars через Telegram
+	AuditOrderRefundCancel  = "order.refund_cancel" // Отмена возврата: Telegram не вернул Stars
+	AuditAccessDenied       = "access.denied"       // Попытка выполнить действие без прав
+)
+
+// AuditEntry — запись журнала аудита
+// Каждая запись содержит хеш предыдущей, поэтому изменение или удаление
+// записей в файле журнала обнаруживается при проверке цепочки
+type AuditEntry struct {
+	ID       int64             `json:"id"`
+	At       time.Time         `json:"at"`               // Время действия
+	ActorID  int64             `json:"actor_id"`         // Кто выполнил действие
+	Action   string            `json:"action"`           // Что сделано (см. константы Audit*)
+	TargetID int64             `json:"target_id"`        // Над кем или чем (0, если не применимо)
+	Params   map[string]string `json:"params,omitempty"` // Параметры действия
+	Outcome  AuditOutcome      `json:"outcome"`          // Результат
+	Error    string            `json:"error,omitempty"`  // Текст ошибки для AuditFailure
+	PrevHash string            `json:"prev_hash"`        // Хеш предыдущей записи
+	Hash     string            `json:"hash"`             // Хеш этой записи
+}
+
+// AuditFilter — условия выборки записей журнала аудита
+type AuditFilter struct {
+	ActorID  int64     // Только действия этого пользователя (0 — все)
+	TargetID int64     // Только действия над этим объектом (0 — все)
+	Action   string    // Префикс действия, например "user." (пусто — все)
+	Since    time.Time // Только записи не раньше этого времени
+	Limit    int       // Максимум записей (самые новые); 0 — без ограничения
+}
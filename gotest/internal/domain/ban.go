@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Ban — блокировка пользователя администратором
+type Ban struct {
+	UserID    int64     `json:"user_id"`
+	Reason    string    `json:"reason"`               // Причина блокировки
+	BannedBy  int64     `json:"banned_by"`            // ID администратора, выдавшего бан
+	CreatedAt time.Time `json:"created_at"`           // Когда выдан бан
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // Когда истекает (нулевое значение — бессрочно)
+	Notified  bool      `json:"notified"`             // Пользователь уже получил уведомление о бане
+}
+
+// Permanent сообщает, что блокировка бессрочная
+func (b Ban) Permanent() bool {
+	return b.ExpiresAt.IsZero()
+}
+
+// Active проверяет, действует ли блокировка в момент now
+func (b Ban) Active(now time.Time) bool {
+	return b.Permanent() || now.Before(b.ExpiresAt)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MediaType — тип вложения в сообщении рассылки
+type MediaType string
+
+const (
+	MediaNone      MediaType = ""          // Только текст
+	MediaPhoto     MediaType = "photo"     // Фото
+	MediaVideo     MediaType = "video"     // Видео
+	MediaDocument  MediaType = "document"  // Документ
+	MediaAnimation MediaType = "animation" // GIF-анимация
+)
+
+// BroadcastButton — кнопка-ссылка под сообщением рассылки
+type BroadcastButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// BroadcastMessage — содержимое рассылки
+type BroadcastMessage struct {
+	Text      string                   `json:"text"`       // Текст или подпись к вложению
+	Entities  []tgbotapi.MessageEntity `json:"entities"`   // Форматирование текста
+	MediaType MediaType                `json:"media_type"` // Тип вложения
+	FileID    string                   `json:"file_id"`    // file_id вложения в Telegram
+	Buttons   []BroadcastButton        `json:"buttons"`    // Кнопки-ссылки (по одной в ряду)
+}
+
+// SegmentKind — тип аудитории рассылки
+type SegmentKind string
+
+const (
+	SegmentAll      SegmentKind = "all"      // Все пользователи
+	SegmentLanguage SegmentKind = "language" // Пользователи с выбранным языком
+	SegmentCourse   SegmentKind = "course"   // Записанные на курс
+)
+
+// Segment — аудитория рассылки
+type Segment struct {
+	Kind     SegmentKind `json:"kind"`
+	Language string      `json:"language,omitempty"`  // Для SegmentLanguage
+	CourseID int         `json:"course_id,omitempty"` // Для SegmentCourse
+}
+
+// BroadcastStatus — состояние задания рассылки
+type BroadcastStatus string
+
+const (
+	BroadcastRunning  BroadcastStatus = "running"  // Выполняется (или будет продолжена после перезапуска)
+	BroadcastFinished BroadcastStatus = "finished" // Завершена
+)
+
+// Broadcast — задание рассылки
+// Хранит список получателей и позицию, поэтому может быть продолжено после перезапуска
+type Broadcast struct {
+	ID                int64            `json:"id"`
+	CreatedBy         int64            `json:"created_by"`          // ID администратора
+	ReportChatID      int64            `json:"report_chat_id"`      // Чат для сообщения о прогрессе
+	ProgressMessageID int              `json:"progress_message_id"` // Сообщение с прогрессом
+	Message           BroadcastMessage `json:"message"`
+	Segment           Segment          `json:"segment"`
+	Recipients        []int64          `json:"recipients"` // Получатели на момент запуска
+	Cursor            int              `json:"cursor"`     // Индекс следующего получателя
+	Delivered         int              `json:"delivered"`  // Доставлено
+	Blocked           int              `json:"blocked"`    // Пользователь заблокировал бота
+	Failed            int              `json:"failed"`     // Другие ошибки
+	Skipped           int              `json:"skipped"`    // Отключили уведомления после запуска
+	Status            BroadcastStatus  `json:"status"`
+	CreatedAt         time.Time        `json:"created_at"`
+	FinishedAt        time.Time        `json:"finished_at,omitempty"`
+}
+
+// Processed возвращает, для скольких получателей известен результат отправки
+func (b Broadcast) Processed() int {
+	return b.Delivered + b.Blocked + b.Failed + b.Skipped
+}
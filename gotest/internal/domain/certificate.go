@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Certificate — сертификат о прохождении курса
+// Имя и название курса сохраняются на момент выдачи, чтобы сертификат не менялся вместе с каталогом
+type Certificate struct {
+	Code        string        `json:"code"` // Уникальный код проверки
+	UserID      int64         `json:"user_id"`
+	CourseID    int           `json:"course_id"`
+	UserName    string        `json:"user_name"`
+	CourseTitle LocalizedText `json:"course_title"`
+	IssuedAt    time.Time     `json:"issued_at"`
+}
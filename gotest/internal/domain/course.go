@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLanguage — язык, на который откатываются локализованные тексты
+const DefaultLanguage = "ru"
+
+// CourseLevel — уровень сложности курса
+type CourseLevel string
+
+const (
+	LevelBeginner     CourseLevel = "beginner"     // Для начинающих
+	LevelIntermediate CourseLevel = "intermediate" // Средний уровень
+	LevelAdvanced     CourseLevel = "advanced"     // Продвинутый уровень
+)
+
+// CourseLevels содержит все уровни от простого к сложному
+var CourseLevels = []CourseLevel{LevelBeginner, LevelIntermediate, LevelAdvanced}
+
+// Title возвращает название уровня для отображения пользователю
+func (l CourseLevel) Title() string {
+	switch l {
+	case LevelBeginner:
+		return "🟢 Начальный"
+	case LevelIntermediate:
+		return "🟡 Средний"
+	case LevelAdvanced:
+		return "🔴 Продвинутый"
+	default:
+		return string(l)
+	}
+}
+
+// LocalizedText — текст на нескольких языках (ключ - код языка: ru, en, zh)
+type LocalizedText map[string]string
+
+// Get возвращает текст на языке lang
+// Если перевода нет — возвращает текст на языке по умолчанию
+func (t LocalizedText) Get(lang string) string {
+	if text, ok := t[lang]; ok && text != "" {
+		return text
+	}
+	return t[DefaultLanguage]
+}
+
+// Course — курс из каталога
+type Course struct {
+	ID              int           `json:"id"`
+	Slug            string        `json:"slug"`             // Короткое имя для ссылок (go-basics)
+	Category        string        `json:"category"`         // Категория (backend, devops...)
+	Level           CourseLevel   `json:"level"`            // Уровень сложности
+	DurationMinutes int           `json:"duration_minutes"` // Продолжительность курса
+	Price           int64         `json:"price"`            // Цена в минимальных единицах валюты (0 — бесплатно)
+	Currency        string        `json:"currency"`         // Код валюты (RUB, XTR для Telegram Stars)
+	Published       bool          `json:"published"`        // Виден ли курс в каталоге
+	Position        int           `json:"position"`         // Порядок в каталоге (меньше — выше)
+	CoverFileID     string        `json:"cover_file_id"`    // Обложка (file_id фото в Telegram)
+	Title           LocalizedText `json:"title"`
+	Description     LocalizedText `json:"description"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// Free возвращает true, если курс бесплатный
+func (c Course) Free() bool {
+	return c.Price == 0
+}
+
+// PriceText возвращает цену курса для отображения пользователю
+func (c Course) PriceText() string {
+	if c.Free() {
+		return "Бесплатно"
+	}
+	return FormatPrice(c.Price, c.Currency)
+}
+
+// FormatPrice возвращает сумму в минимальных единицах валюты для отображения пользователю
+func FormatPrice(amount int64, currency string) string {
+	switch currency {
+	case CurrencyStars:
+		return fmt.Sprintf("%d ⭐", amount)
+	case "RUB":
+		return fmt.Sprintf("%d.%02d ₽", amount/100, amount%100)
+	default:
+		return fmt.Sprintf("%d.%02d %s", amount/100, amount%100, currency)
+	}
+}
+
+// DurationText возвращает продолжительность курса для отображения пользователю
+func (c Course) DurationText() string {
+	hours, minutes := c.DurationMinutes/60, c.DurationMinutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d мин", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d ч", hours)
+	default:
+		return fmt.Sprintf("%d ч %d мин", hours, minutes)
+	}
+}
+
+// CourseFilter — условия поиска по каталогу курсов
+// Пустые поля не ограничивают выдачу
+type CourseFilter struct {
+	Query    string      // Текст запроса (ищется в названиях и описаниях на всех языках)
+	Category string      // Категория курса
+	Level    CourseLevel // Уровень сложности
+}
+
+// Empty возвращает true, если фильтр не задан
+func (f CourseFilter) Empty() bool {
+	return f.Query == "" && f.Category == "" && f.Level == ""
+}
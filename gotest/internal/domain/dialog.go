@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DialogState — состояние многошагового диалога (конечного автомата) в чате
+// Состояние хранится в хранилище, поэтому диалог продолжается после перезапуска бота
+type DialogState struct {
+	ChatID    int64           `json:"chat_id"`
+	UserID    int64           `json:"user_id"`        // 0 — общее состояние всего чата
+	Scene     string          `json:"scene"`          // Сценарий диалога
+	Step      string          `json:"step"`           // Текущий шаг сценария
+	Data      json.RawMessage `json:"data,omitempty"` // Данные сценария, собранные на предыдущих шагах
+	Timeout   time.Duration   `json:"timeout"`        // Время ожидания ответа (0 — без ограничения)
+	StartedAt time.Time       `json:"started_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Expired возвращает true, если время ожидания ответа истекло
+func (s DialogState) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// Touch отмечает активность в диалоге и продлевает время ожидания ответа
+func (s *DialogState) Touch(now time.Time) {
+	s.UpdatedAt = now
+	s.ExpiresAt = time.Time{}
+	if s.Timeout > 0 {
+		s.ExpiresAt = now.Add(s.Timeout)
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Enrollment — запись пользователя на курс и его прогресс
+type Enrollment struct {
+	UserID           int64     `json:"user_id"`
+	CourseID         int       `json:"course_id"`
+	EnrolledAt       time.Time `json:"enrolled_at"`
+	CompletedLessons []int     `json:"completed_lessons,omitempty"` // ID пройденных уроков
+	LastLessonID     int       `json:"last_lesson_id,omitempty"`    // Последний открытый урок (для продолжения)
+	CompletedAt      time.Time `json:"completed_at"`                // Когда пройдены все уроки (нулевое — ещё не пройден)
+
+	Quizzes map[int]QuizResult `json:"quizzes,omitempty"` // Результаты тестов, ключ - ID теста
+}
+
+// LessonCompleted проверяет, пройден ли урок
+func (e Enrollment) LessonCompleted(lessonID int) bool {
+	return slices.Contains(e.CompletedLessons, lessonID)
+}
+
+// Completed возвращает true, если курс пройден полностью
+func (e Enrollment) Completed() bool {
+	return !e.CompletedAt.IsZero()
+}
+
+// CourseProgress — курс пользователя вместе с прогрессом
+type CourseProgress struct {
+	Course     Course
+	Enrollment Enrollment
+	Done       int // Пройдено уроков
+	Total      int // Всего уроков в курсе
+}
+
+// Percent возвращает прогресс в процентах
+func (p CourseProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Done * 100 / p.Total
+}
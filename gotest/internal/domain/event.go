@@ -0,0 +1,27 @@
+package domain
+
+// EventType — тип события, учитываемого в статистике
+type EventType string
+
+const (
+	EventCommand  EventType = "command"  // Команда (/start, /help, ...)
+	EventCallback EventType = "callback" // Нажатие на инлайн-кнопку
+	EventMessage  EventType = "message"  // Обычное текстовое сообщение
+	EventError    EventType = "error"    // Ошибка при обработке обновления
+)
+
+// DailyEvents — счётчики событий за один день
+type DailyEvents struct {
+	Commands  map[string]int `json:"commands"`  // Команда -> количество вызовов
+	Callbacks map[string]int `json:"callbacks"` // Маршрут callback -> количество нажатий
+	Messages  int            `json:"messages"`  // Количество текстовых сообщений
+	Errors    int            `json:"errors"`    // Количество ошибок
+}
+
+// NewDailyEvents создаёт пустые счётчики за день
+func NewDailyEvents() *DailyEvents {
+	return &DailyEvents{
+		Commands:  make(map[string]int),
+		Callbacks: make(map[string]int),
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// FAQMatch — способ сопоставления правила автоответа с текстом сообщения
+type FAQMatch string
+
+const (
+	FAQKeyword FAQMatch = "keyword" // Текст содержит одно из ключевых слов (без учёта регистра)
+	FAQRegex   FAQMatch = "regex"   // Текст подходит под одно из регулярных выражений (без учёта регистра)
+	FAQFuzzy   FAQMatch = "fuzzy"   // Слова одной из фраз встречаются в тексте с допуском на опечатки
+)
+
+// FAQMatches содержит все способы сопоставления
+var FAQMatches = []FAQMatch{FAQKeyword, FAQRegex, FAQFuzzy}
+
+// FAQButton — кнопка под автоответом: ссылка (URL) или кнопка бота (Data)
+type FAQButton struct {
+	Text LocalizedText `json:"text"`
+	URL  string        `json:"url,omitempty"`
+	Data string        `json:"data,omitempty"` // callback_data существующей кнопки бота, например sup_open
+}
+
+// FAQRule — правило автоответа на текстовые сообщения
+// Из подходящих правил срабатывает правило с наибольшим приоритетом
+type FAQRule struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Match     FAQMatch      `json:"match"`
+	Patterns  []string      `json:"patterns"` // Ключевые слова, регулярные выражения или фразы
+	Priority  int           `json:"priority"`
+	Answer    LocalizedText `json:"answer"`
+	Buttons   []FAQButton   `json:"buttons,omitempty"`
+	Enabled   bool          `json:"enabled"`
+	UpdatedBy int64         `json:"updated_by"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
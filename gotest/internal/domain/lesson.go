@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// LessonKind — вид содержимого урока
+type LessonKind string
+
+const (
+	LessonText     LessonKind = "text"     // Текст (может содержать блоки кода ```)
+	LessonPhoto    LessonKind = "photo"    // Фото с подписью
+	LessonVideo    LessonKind = "video"    // Видео с подписью
+	LessonDocument LessonKind = "document" // Файл с подписью
+)
+
+// Icon возвращает значок вида урока
+func (k LessonKind) Icon() string {
+	switch k {
+	case LessonPhoto:
+		return "🖼"
+	case LessonVideo:
+		return "🎬"
+	case LessonDocument:
+		return "📎"
+	default:
+		return "📝"
+	}
+}
+
+// Lesson — урок курса
+type Lesson struct {
+	ID              int           `json:"id"`
+	CourseID        int           `json:"course_id"`
+	Position        int           `json:"position"` // Порядок урока в курсе (начинается с 1)
+	Kind            LessonKind    `json:"kind"`
+	Title           LocalizedText `json:"title"`
+	Body            LocalizedText `json:"body"`                    // Текст урока или подпись к медиа
+	MediaFileID     string        `json:"media_file_id,omitempty"` // file_id фото, видео или файла в Telegram
+	UnlockAfterDays int           `json:"unlock_after_days"`       // Через сколько дней после записи открывается урок
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// UnlockAt возвращает момент, когда урок откроется пользователю, записавшемуся в enrolledAt
+func (l Lesson) UnlockAt(enrolledAt time.Time) time.Time {
+	return enrolledAt.AddDate(0, 0, l.UnlockAfterDays)
+}
+
+// Unlocked проверяет, открыт ли урок в момент now
+func (l Lesson) Unlocked(enrolledAt, now time.Time) bool {
+	return !now.Before(l.UnlockAt(enrolledAt))
+}
+
+// LessonStep — урок в плане прохождения курса пользователем
+type LessonStep struct {
+	Lesson    Lesson
+	Number    int       // Номер урока в курсе (начинается с 1)
+	Completed bool      // Урок пройден
+	Locked    bool      // Урок ещё не открыт по расписанию
+	UnlockAt  time.Time // Когда урок откроется пользователю
+}
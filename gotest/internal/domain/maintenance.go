@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Maintenance — состояние режима обслуживания
+// Пока режим включён, бот отвечает обычным пользователям только сообщением о работах
+type Maintenance struct {
+	Enabled   bool              `json:"enabled"`
+	Messages  map[string]string `json:"messages,omitempty"` // Тексты для пользователей по кодам языков
+	ChangedBy int64             `json:"changed_by"`         // Кто последним включил или выключил режим (0 — конфигурация)
+	ChangedAt time.Time         `json:"changed_at"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// CurrencyStars — валюта Telegram Stars: счёт выставляется без платёжного провайдера
+const CurrencyStars = "XTR"
+
+// OrderKind — что покупается в заказе
+type OrderKind string
+
+const (
+	OrderCourse OrderKind = "course" // Доступ к платному курсу
+	OrderPlan   OrderKind = "plan"   // Подписка на тарифный план
+)
+
+// OrderStatus — состояние заказа
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"   // Счёт выставлен, оплаты ещё нет
+	OrderPaid      OrderStatus = "paid"      // Оплачен, доступ выдан
+	OrderRefunding OrderStatus = "refunding" // Деньги возвращаются через Telegram, доступ закрыт
+	OrderRefunded  OrderStatus = "refunded"  // Деньги возвращены, доступ отозван
+)
+
+// Title возвращает состояние заказа для отображения
+func (s OrderStatus) Title() string {
+	switch s {
+	case OrderPending:
+		return "⏳ Ожидает оплаты"
+	case OrderPaid:
+		return "✅ Оплачен"
+	case OrderRefunding:
+		return "⏳ Возвращается"
+	case OrderRefunded:
+		return "↩️ Возвращён"
+	default:
+		return string(s)
+	}
+}
+
+// Order — заказ пользователя: курс или подписка
+type Order struct {
+	ID               int         `json:"id"`
+	UserID           int64       `json:"user_id"`
+	Kind             OrderKind   `json:"kind"`
+	CourseID         int         `json:"course_id,omitempty"`
+	PlanID           string      `json:"plan_id,omitempty"`
+	Days             int         `json:"days,omitempty"` // Срок подписки по заказу на момент заказа
+	Title            string      `json:"title"`          // Название товара на момент заказа
+	Amount           int64       `json:"amount"`         // Сумма в минимальных единицах валюты
+	Currency         string      `json:"currency"`       // Код валюты (XTR для Telegram Stars)
+	Status           OrderStatus `json:"status"`
+	TelegramChargeID string      `json:"telegram_charge_id,omitempty"` // Идентификатор платежа в Telegram (нужен для возврата Stars)
+	ProviderChargeID string      `json:"provider_charge_id,omitempty"` // Идентификатор платежа у провайдера
+	CreatedAt        time.Time   `json:"created_at"`
+	PaidAt           time.Time   `json:"paid_at"`
+	RefundedAt       time.Time   `json:"refunded_at"`
+	RefundedBy       int64       `json:"refunded_by,omitempty"`
+}
+
+// Payload возвращает данные счёта, по которым платёж связывается с заказом
+func (o Order) Payload() string {
+	return "order_" + strconv.Itoa(o.ID)
+}
+
+// PriceText возвращает сумму заказа для отображения
+func (o Order) PriceText() string {
+	return FormatPrice(o.Amount, o.Currency)
+}
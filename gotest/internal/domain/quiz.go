@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// QuizMode — способ показа вопросов теста
+type QuizMode string
+
+const (
+	QuizButtons QuizMode = "buttons" // Вопросы с инлайн-кнопками
+	QuizPoll    QuizMode = "poll"    // Нативные опросы Telegram в режиме викторины
+)
+
+// QuizQuestion — вопрос теста с одним или несколькими правильными ответами
+type QuizQuestion struct {
+	Text        string   `json:"text"`
+	Options     []string `json:"options"`
+	Correct     []int    `json:"correct"`               // Индексы правильных вариантов
+	Explanation string   `json:"explanation,omitempty"` // Пояснение после ответа
+}
+
+// Multiple возвращает true, если в вопросе несколько правильных ответов
+func (q QuizQuestion) Multiple() bool {
+	return len(q.Correct) > 1
+}
+
+// Check проверяет ответ: выбраны ровно все правильные варианты
+func (q QuizQuestion) Check(answer []int) bool {
+	if len(answer) != len(q.Correct) {
+		return false
+	}
+	for _, option := range answer {
+		if !slices.Contains(q.Correct, option) {
+			return false
+		}
+	}
+	return true
+}
+
+// Quiz — тест в конце урока
+type Quiz struct {
+	ID          int            `json:"id"`
+	CourseID    int            `json:"course_id"`
+	LessonID    int            `json:"lesson_id"`
+	Mode        QuizMode       `json:"mode"`
+	PassPercent int            `json:"pass_percent"` // Проходной балл в процентах
+	MaxAttempts int            `json:"max_attempts"` // Лимит попыток (0 — без ограничений)
+	Questions   []QuizQuestion `json:"questions"`
+	UpdatedBy   int64          `json:"updated_by"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// QuizResult — результаты пользователя по тесту, хранятся в записи на курс
+type QuizResult struct {
+	Attempts  int       `json:"attempts"`   // Завершённых попыток
+	LastScore int       `json:"last_score"` // Результат последней попытки в процентах
+	BestScore int       `json:"best_score"` // Лучший результат в процентах
+	Passed    bool      `json:"passed"`     // Проходной балл набран хотя бы в одной попытке
+	LastAt    time.Time `json:"last_at"`
+}
+
+// AttemptsLeft возвращает число оставшихся попыток (-1 — без ограничений)
+func (r QuizResult) AttemptsLeft(quiz Quiz) int {
+	if quiz.MaxAttempts == 0 {
+		return -1
+	}
+	return max(quiz.MaxAttempts-r.Attempts, 0)
+}
+
+// QuizSession — попытка прохождения теста, которая идёт сейчас
+type QuizSession struct {
+	Quiz      Quiz
+	ChatID    int64
+	Question  int   // Индекс текущего вопроса
+	Selected  []int // Отмеченные варианты текущего вопроса (для вопросов с несколькими ответами)
+	Correct   int   // Правильных ответов в этой попытке
+	StartedAt time.Time
+}
+
+// Score возвращает результат попытки в процентах
+func (s QuizSession) Score() int {
+	if len(s.Quiz.Questions) == 0 {
+		return 0
+	}
+	return s.Correct * 100 / len(s.Quiz.Questions)
+}
@@ -0,0 +1,110 @@
+package domain
+
+// Role — роль пользователя в боте
+// Роли упорядочены по уровню: owner > admin > moderator > support > user
+type Role string
+
+const (
+	RoleOwner     Role = "owner"     // Владелец бота (задаётся через ADMIN_IDS)
+	RoleAdmin     Role = "admin"     // Администратор
+	RoleModerator Role = "moderator" // Модератор
+	RoleSupport   Role = "support"   // Сотрудник поддержки
+	RoleUser      Role = "user"      // Обычный пользователь
+)
+
+// Roles содержит все роли от старшей к младшей
+var Roles = []Role{RoleOwner, RoleAdmin, RoleModerator, RoleSupport, RoleUser}
+
+// Permission — разрешение на выполнение действия (команды или callback-маршрута)
+type Permission string
+
+const (
+	PermAdminPanel    Permission = "admin.panel"   // Доступ к админ-панели
+	PermRolesView     Permission = "roles.view"    // Просмотр ролей
+	PermRolesManage   Permission = "roles.manage"  // Выдача и отзыв ролей
+	PermBroadcast     Permission = "broadcast"     // Рассылка сообщений пользователям
+	PermUsersView     Permission = "users.view"    // Поиск и просмотр пользователей
+	PermUsersBan      Permission = "users.ban"     // Блокировка пользователей
+	PermUsersManage   Permission = "users.manage"  // Сброс настроек пользователей
+	PermUsersWrite    Permission = "users.write"   // Личные сообщения пользователям от имени бота
+	PermAuditView     Permission = "audit.view"    // Просмотр и выгрузка журнала аудита
+	PermMaintenance   Permission = "maintenance"   // Включение и выключение режима обслуживания
+	PermFeatures      Permission = "features"      // Просмотр и изменение флагов функций
+	PermCourses       Permission = "courses"       // Создание, изменение и удаление курсов
+	PermSupport       Permission = "support"       // Ответы на обращения в поддержку
+	PermFAQ           Permission = "faq"           // Управление правилами автоответов
+	PermSubscriptions Permission = "subscriptions" // Выдача, продление и отзыв подписок
+	PermPayments      Permission = "payments"      // Просмотр заказов и возврат оплаты
+)
+
+// rolePermissions описывает разрешения каждой роли
+// Владелец получает все разрешения автоматически (см. Role.Has)
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermAdminPanel,
+		PermRolesView,
+		PermRolesManage,
+		PermBroadcast,
+		PermUsersView,
+		PermUsersBan,
+		PermUsersManage,
+		PermUsersWrite,
+		PermAuditView,
+		PermMaintenance,
+		PermFeatures,
+		PermCourses,
+		PermSupport,
+		PermFAQ,
+		PermSubscriptions,
+		PermPayments,
+	},
+	RoleModerator: {
+		PermAdminPanel,
+		PermRolesView,
+		PermUsersView,
+		PermUsersBan,
+		PermUsersWrite,
+		PermSupport,
+	},
+	RoleSupport: {
+		PermAdminPanel,
+		PermUsersView,
+		PermUsersWrite,
+		PermSupport,
+		PermFAQ,
+	},
+}
+
+// ParseRole преобразует строку в роль
+// Второе значение false, если такой роли нет
+func ParseRole(s string) (Role, bool) {
+	for _, role := range Roles {
+		if string(role) == s {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// Level возвращает уровень роли: чем больше, тем больше прав
+func (r Role) Level() int {
+	for i, role := range Roles {
+		if role == r {
+			return len(Roles) - i
+		}
+	}
+	return 0
+}
+
+// Has проверяет, есть ли у роли разрешение
+func (r Role) Has(perm Permission) bool {
+	if r == RoleOwner {
+		return true
+	}
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// ScheduleStatus — состояние запланированной рассылки
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"    // Ждёт следующего запуска
+	SchedulePaused    ScheduleStatus = "paused"    // Приостановлена администратором
+	ScheduleCancelled ScheduleStatus = "cancelled" // Отменена администратором
+	ScheduleDone      ScheduleStatus = "done"      // Разовая рассылка выполнена
+)
+
+// ScheduledBroadcast — рассылка, запланированная на время или по расписанию cron
+type ScheduledBroadcast struct {
+	ID           int64            `json:"id"`
+	CreatedBy    int64            `json:"created_by"`     // ID администратора
+	ReportChatID int64            `json:"report_chat_id"` // Чат для отчётов о запусках
+	Message      BroadcastMessage `json:"message"`
+	Segment      Segment          `json:"segment"`
+	Cron         string           `json:"cron,omitempty"` // Выражение cron; пусто для разовой рассылки
+	TimeZone     string           `json:"time_zone"`      // Часовой пояс расписания (IANA)
+	NextRunAt    time.Time        `json:"next_run_at"`    // Время следующего запуска
+	LastRunAt    time.Time        `json:"last_run_at,omitempty"`
+	Runs         int              `json:"runs"` // Сколько раз рассылка уже запускалась
+	Status       ScheduleStatus   `json:"status"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// Recurring сообщает, повторяется ли рассылка по расписанию cron
+func (s ScheduledBroadcast) Recurring() bool {
+	return s.Cron != ""
+}
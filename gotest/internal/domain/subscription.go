@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Возможности тарифных планов, которые проверяет код бота
+const (
+	PlanPaidCourses     = "paid_courses"     // Доступ ко всем платным курсам
+	PlanPremiumCommands = "premium_commands" // Команды из SUBSCRIPTION_COMMANDS
+)
+
+// KnownPlanFeatures описывает возможности тарифных планов
+var KnownPlanFeatures = map[string]string{
+	PlanPaidCourses:     "Все платные курсы",
+	PlanPremiumCommands: "Премиум-команды",
+}
+
+// Plan — тарифный план подписки
+type Plan struct {
+	ID       string        `json:"id"` // Короткое имя (month, year)
+	Title    LocalizedText `json:"title"`
+	Days     int           `json:"days"`     // Срок действия подписки
+	Price    int64         `json:"price"`    // Цена в минимальных единицах валюты
+	Currency string        `json:"currency"` // Код валюты (XTR для Telegram Stars)
+	Features []string      `json:"features"` // Возможности плана (см. константы Plan*)
+	Active   bool          `json:"active"`   // Доступен ли план для оформления
+	Position int           `json:"position"` // Порядок в списке планов (меньше — выше)
+}
+
+// Has возвращает true, если план даёт возможность feature
+func (p Plan) Has(feature string) bool {
+	return slices.Contains(p.Features, feature)
+}
+
+// Subscription — подписка пользователя
+// У пользователя одна подписка: новый план заменяет прежний, продление сдвигает дату окончания
+type Subscription struct {
+	UserID    int64     `json:"user_id"`
+	PlanID    string    `json:"plan_id"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	GrantedBy int64     `json:"granted_by,omitempty"` // Сотрудник, выдавший подписку вручную (0 — оформлена пользователем)
+	Reminded  int       `json:"reminded,omitempty"`   // За сколько дней до окончания отправлено последнее напоминание
+	Expired   bool      `json:"expired,omitempty"`    // Пользователь уведомлён об окончании подписки
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Active возвращает true, если подписка действует в момент now
+func (s Subscription) Active(now time.Time) bool {
+	return now.Before(s.ExpiresAt)
+}
+
+// DaysLeft возвращает число оставшихся дней подписки, округлённое вверх (0 — подписка закончилась)
+func (s Subscription) DaysLeft(now time.Time) int {
+	if !s.Active(now) {
+		return 0
+	}
+	return int((s.ExpiresAt.Sub(now) + 24*time.Hour - 1) / (24 * time.Hour))
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// TicketStatus — статус обращения в поддержку
+type TicketStatus string
+
+const (
+	TicketOpen    TicketStatus = "open"    // Ждёт ответа поддержки
+	TicketPending TicketStatus = "pending" // Поддержка ответила, ждём пользователя
+	TicketClosed  TicketStatus = "closed"  // Обращение закрыто
+)
+
+// TicketStatuses содержит все статусы обращений
+var TicketStatuses = []TicketStatus{TicketOpen, TicketPending, TicketClosed}
+
+// Title возвращает название статуса для отображения
+func (s TicketStatus) Title() string {
+	switch s {
+	case TicketOpen:
+		return "🟢 Открыто"
+	case TicketPending:
+		return "🟡 Ждёт пользователя"
+	case TicketClosed:
+		return "⚪️ Закрыто"
+	default:
+		return string(s)
+	}
+}
+
+// TicketMessage — сообщение в истории обращения
+type TicketMessage struct {
+	FromSupport bool      `json:"from_support"` // Ответ сотрудника поддержки
+	AuthorID    int64     `json:"author_id"`
+	Text        string    `json:"text"` // Текст или подпись; для вложений без подписи — тип вложения
+	At          time.Time `json:"at"`
+}
+
+// Ticket — обращение пользователя в поддержку
+type Ticket struct {
+	ID         int             `json:"id"`
+	UserID     int64           `json:"user_id"`
+	ChatID     int64           `json:"chat_id"`   // Чат, в котором пользователь получает ответы
+	UserName   string          `json:"user_name"` // Имя и @username на момент обращения
+	Status     TicketStatus    `json:"status"`
+	AssigneeID int64           `json:"assignee_id"` // Ответственный сотрудник (0 — не назначен)
+	Messages   []TicketMessage `json:"messages"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	ClosedAt   time.Time       `json:"closed_at"`
+}
+
+// Active возвращает true, если обращение не закрыто
+func (t Ticket) Active() bool {
+	return t.Status != TicketClosed
+}
+
+// TicketLink — связь сообщения в Telegram с обращением
+// По ней ответ реплаем (в чате поддержки или у пользователя) находит своё обращение
+type TicketLink struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+	TicketID  int   `json:"ticket_id"`
+}
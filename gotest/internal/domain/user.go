@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// User представляет пользователя бота
+type User struct {
+	ID           int64     `json:"id"`            // Telegram User ID
+	Username     string    `json:"username"`      // Username пользователя
+	FirstName    string    `json:"first_name"`    // Имя
+	LastName     string    `json:"last_name"`     // Фамилия
+	LanguageCode string    `json:"language_code"` // Код языка из Telegram
+	CreatedAt    time.Time `json:"created_at"`    // Дата первого обращения к боту
+	LastSeenAt   time.Time `json:"last_seen_at"`  // Дата последней активности
+}
+
+// UserSettings содержит настройки пользователя
+type UserSettings struct {
+	UserID               int64  `json:"user_id"`
+	NotificationsEnabled bool   `json:"notifications_enabled"` // Уведомления включены
+	Language             string `json:"language"`              // Язык интерфейса (ru, en, zh)
+}
+
+// DefaultUserSettings возвращает настройки по умолчанию:
+// уведомления включены, язык — русский
+func DefaultUserSettings(userID int64) UserSettings {
+	return UserSettings{
+		UserID:               userID,
+		NotificationsEnabled: true,
+		Language:             "ru",
+	}
+}
@@ -2,21 +2,25 @@ package handler
 
 import (
 	"fmt"
-	"telegram-bot/internal/middleware"
+	"sort"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/service"
 )
 
-// InfoHandler обрабатывает команду /info
-// AdminHandler обрабатывает админ-команду
+// AdminHandler обрабатывает команду /admin и кнопки админ-панели (admin_*)
 type AdminHandler struct {
-	adminIDs []int64
+	stats *service.StatsService
 }
 
-// NewInfoHandler создаёт новый обработчик команды /admin
-func NewAdminHandler(adminIDs []int64) *AdminHandler {
+// NewAdminHandler создаёт новый обработчик админ-панели
+func NewAdminHandler(stats *service.StatsService) *AdminHandler {
 	return &AdminHandler{
-		adminIDs: adminIDs,
+		stats: stats,
 	}
 }
 
@@ -25,31 +29,109 @@ func (h *AdminHandler) Command() string {
 	return "admin"
 }
 
-// Handle обрабатывает команду /info
+// Prefix возвращает префикс callback-запросов админ-панели
+func (h *AdminHandler) Prefix() string {
+	return "admin_"
+}
+
+// Permission возвращает разрешение, необходимое для админ-панели
+func (h *AdminHandler) Permission() domain.Permission {
+	return domain.PermAdminPanel
+}
+
+// Handle обрабатывает команду /admin — открывает админ-панель со статистикой
 func (h *AdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
-	if !middleware.RequireAdmin(bot, msg, h.adminIDs) {
-		return nil // Сообщение уже отправлено middleware
+	reply := tgbotapi.NewMessage(msg.Chat.ID, h.statsText())
+	reply.ParseMode = tgbotapi.ModeHTML
+	kb := keyboard.NewAdminPanelKeyboard()
+	reply.ReplyMarkup = &kb
+	_, err := bot.Send(reply)
+	return err
+}
+
+// HandleCallback обрабатывает нажатия на кнопки админ-панели
+func (h *AdminHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	switch callback.Data {
+	case "admin_stats":
+		bot.Request(tgbotapi.NewCallback(callback.ID, "🔄 Статистика обновлена"))
+
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, h.statsText())
+		edit.ParseMode = tgbotapi.ModeHTML
+		kb := keyboard.NewAdminPanelKeyboard()
+		edit.ReplyMarkup = &kb
+		_, err := bot.Send(edit)
+		return err
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
 	}
-	chatID := msg.Chat.ID
-	user := msg.From
+}
+
+// statsText формирует текст экрана статистики
+func (h *AdminHandler) statsText() string {
+	now := time.Now()
+	stats := h.stats.Collect(now)
 
-	info := "<b>Информация о вас:</b>\n\n"
-	info += fmt.Sprintf("<b>ID:</b> <code>%d</code>\n", user.ID)
-	info += fmt.Sprintf("<b>Имя:</b> %s\n", user.FirstName)
+	text := "<b>📊 Статистика бота</b>\n\n"
 
-	if user.LastName != "" {
-		info += fmt.Sprintf("<b>Фамилия:</b> %s\n", user.LastName)
+	text += "<b>👥 Пользователи</b>\n"
+	text += fmt.Sprintf("Всего: <code>%d</code>\n", stats.TotalUsers)
+	text += fmt.Sprintf("Новых за сутки: <code>%d</code>\n", stats.NewToday)
+	text += fmt.Sprintf("Новых за неделю: <code>%d</code>\n", stats.NewWeek)
+	text += fmt.Sprintf("Активных за сутки: <code>%d</code>\n", stats.ActiveToday)
+	text += fmt.Sprintf("Активных за неделю: <code>%d</code>\n\n", stats.ActiveWeek)
+
+	text += "<b>🌐 Языки</b>\n"
+	langs := make([]string, 0, len(stats.Languages))
+	for lang := range stats.Languages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		text += fmt.Sprintf("%s: <code>%d</code> (%s)\n", lang, stats.Languages[lang], percent(stats.Languages[lang], stats.TotalUsers))
 	}
+	if len(langs) == 0 {
+		text += "Нет данных\n"
+	}
+
+	text += fmt.Sprintf("\n<b>🔔 Уведомления включены:</b> <code>%d</code> из <code>%d</code> (%s)\n\n",
+		stats.NotificationsOn, stats.TotalUsers, percent(stats.NotificationsOn, stats.TotalUsers))
 
-	if user.UserName != "" {
-		info += fmt.Sprintf("<b>Username:</b> @%s\n", user.UserName)
+	text += "<b>⌨️ Популярные команды (7 дней)</b>\n"
+	text += countersText(stats.TopCommands, "/")
+
+	text += "\n<b>🔘 Популярные кнопки (7 дней)</b>\n"
+	text += countersText(stats.TopCallbacks, "")
+
+	text += fmt.Sprintf("\n<b>💬 Сообщений за неделю:</b> <code>%d</code>\n", stats.MessagesWeek)
+	text += fmt.Sprintf("<b>⚠️ Ошибок:</b> сегодня <code>%d</code>, за неделю <code>%d</code>\n\n",
+		stats.ErrorsToday, stats.ErrorsWeek)
+
+	text += fmt.Sprintf("<i>Обновлено: %s</i>", now.Format("2006-01-02 15:04:05"))
+	return text
+}
+
+// countersText форматирует список счётчиков, по одному на строку
+func countersText(counters []service.Counter, prefix string) string {
+	if len(counters) == 0 {
+		return "Нет данных\n"
 	}
 
-	info += fmt.Sprintf("<b>Язык:</b> %s\n", user.LanguageCode)
-	info += fmt.Sprintf("<b>Бот:</b> %v\n", user.IsBot)
+	text := ""
+	for i, counter := range counters {
+		text += fmt.Sprintf("%d. %s%s — <code>%d</code>\n", i+1, prefix, counter.Name, counter.Count)
+	}
+	return text
+}
 
-	reply := tgbotapi.NewMessage(chatID, info)
-	reply.ParseMode = tgbotapi.ModeHTML
-	_, err := bot.Send(reply)
-	return err
+// percent возвращает долю part от total в процентах
+func percent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
 }
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+const (
+	auditPerPage     = 6   // Записей на странице: с параметрами и ошибкой страница укладывается в лимит сообщения
+	auditParamsLimit = 200 // Максимальная длина параметров и ошибки одной записи в чате
+)
+
+// AuditHandler обрабатывает команду /audit — просмотр и выгрузку журнала аудита
+// Страницы журнала листаются кнопками aud_* с последними фильтрами администратора
+type AuditHandler struct {
+	audit   *service.AuditService
+	users   *repository.UserRepository
+	mu      sync.Mutex
+	filters map[int64]domain.AuditFilter // Ключ - ID администратора
+}
+
+// NewAuditHandler создаёт новый обработчик команды /audit
+func NewAuditHandler(audit *service.AuditService, users *repository.UserRepository) *AuditHandler {
+	return &AuditHandler{
+		audit:   audit,
+		users:   users,
+		filters: make(map[int64]domain.AuditFilter),
+	}
+}
+
+// Command возвращает команду
+func (h *AuditHandler) Command() string {
+	return "audit"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *AuditHandler) Prefix() string {
+	return "aud_"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *AuditHandler) Permission() domain.Permission {
+	return domain.PermAuditView
+}
+
+// Handle обрабатывает команду /audit [фильтры] [csv|json|verify]
+func (h *AuditHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	var filter domain.AuditFilter
+	format := ""
+
+	for _, arg := range strings.Fields(msg.CommandArguments()) {
+		key, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue {
+			switch key {
+			case "csv", "json", "verify":
+				format = key
+				continue
+			}
+			return h.sendUsage(bot, msg.Chat.ID)
+		}
+
+		ok := true
+		switch key {
+		case "actor":
+			filter.ActorID, ok = resolveUserID(h.users, value)
+		case "target":
+			filter.TargetID, ok = resolveUserID(h.users, value)
+		case "action":
+			filter.Action = value
+		case "since":
+			var period time.Duration
+			period, ok = parseBanDuration(value)
+			filter.Since = time.Now().Add(-period)
+		case "limit":
+			var err error
+			filter.Limit, err = strconv.Atoi(value)
+			ok = err == nil && filter.Limit > 0
+		default:
+			ok = false
+		}
+		if !ok {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Неверный фильтр «%s».", arg))
+		}
+	}
+
+	if format == "verify" {
+		return h.verify(bot, msg.Chat.ID)
+	}
+
+	if format == "" {
+		h.mu.Lock()
+		h.filters[msg.From.ID] = filter
+		h.mu.Unlock()
+		return h.showPage(bot, msg.From.ID, msg.Chat.ID, 0, 0)
+	}
+
+	entries, err := h.audit.Find(filter)
+	if err != nil {
+		sendText(bot, msg.Chat.ID, "❌ Не удалось прочитать журнал аудита.")
+		return fmt.Errorf("ошибка чтения журнала аудита: %w", err)
+	}
+
+	if format == "csv" {
+		return h.sendCSV(bot, msg.Chat.ID, entries)
+	}
+	return h.sendJSON(bot, msg.Chat.ID, entries)
+}
+
+// HandleCallback листает журнал: aud_p_<страница>, aud_pick_<страница>
+func (h *AuditHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	page, picker, ok := auditPaginator().Parse(callback.Data)
+	if !ok {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	if !picker {
+		return h.showPage(bot, callback.From.ID, chatID, messageID, page)
+	}
+
+	entries, err := h.entries(callback.From.ID)
+	if err != nil {
+		return err
+	}
+	kb, err := auditPaginator().Picker(keyboard.SliceSource(entries), page)
+	if err != nil {
+		return err
+	}
+	_, err = bot.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, kb))
+	return err
+}
+
+// showPage показывает страницу журнала с последними фильтрами администратора
+// Если messageID равен 0, отправляет новое сообщение вместо редактирования
+func (h *AuditHandler) showPage(bot *tgbotapi.BotAPI, adminID, chatID int64, messageID, page int) error {
+	entries, err := h.entries(adminID)
+	if err != nil {
+		sendText(bot, chatID, "❌ Не удалось прочитать журнал аудита.")
+		return err
+	}
+
+	view, err := auditPaginator().Render(keyboard.SliceSource(entries), page)
+	if err != nil {
+		return err
+	}
+
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, view.Text)
+		reply.ParseMode = tgbotapi.ModeHTML
+		reply.ReplyMarkup = view.Keyboard
+		_, err = bot.Send(reply)
+		return err
+	}
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, view.Text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.ReplyMarkup = &view.Keyboard
+	_, err = bot.Send(edit)
+	return err
+}
+
+// entries возвращает записи журнала по фильтрам администратора, начиная с новых
+func (h *AuditHandler) entries(adminID int64) ([]domain.AuditEntry, error) {
+	h.mu.Lock()
+	filter := h.filters[adminID]
+	h.mu.Unlock()
+
+	entries, err := h.audit.Find(filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения журнала аудита: %w", err)
+	}
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// verify проверяет целостность журнала и сообщает результат
+func (h *AuditHandler) verify(bot *tgbotapi.BotAPI, chatID int64) error {
+	brokenID, err := h.audit.Verify()
+	if err != nil {
+		sendText(bot, chatID, "❌ Не удалось прочитать журнал аудита.")
+		return fmt.Errorf("ошибка проверки журнала аудита: %w", err)
+	}
+	if brokenID != 0 {
+		return sendText(bot, chatID, fmt.Sprintf("⚠️ Журнал аудита изменён: цепочка нарушена на записи #%d.", brokenID))
+	}
+	return sendText(bot, chatID, "✅ Журнал аудита не изменялся.")
+}
+
+// sendCSV отправляет записи журнала CSV-файлом
+func (h *AuditHandler) sendCSV(bot *tgbotapi.BotAPI, chatID int64, entries []domain.AuditEntry) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"id", "at", "actor_id", "action", "target_id", "params", "outcome", "error", "hash"})
+	for _, e := range entries {
+		w.Write([]string{
+			strconv.FormatInt(e.ID, 10),
+			e.At.Format(time.RFC3339),
+			strconv.FormatInt(e.ActorID, 10),
+			e.Action,
+			strconv.FormatInt(e.TargetID, 10),
+			auditParams(e.Params),
+			string(e.Outcome),
+			e.Error,
+			e.Hash,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("ошибка формирования CSV: %w", err)
+	}
+
+	return h.sendFile(bot, chatID, "audit.csv", buf.Bytes(), len(entries))
+}
+
+// sendJSON отправляет записи журнала JSON-файлом
+func (h *AuditHandler) sendJSON(bot *tgbotapi.BotAPI, chatID int64, entries []domain.AuditEntry) error {
+	if entries == nil {
+		entries = []domain.AuditEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка формирования JSON: %w", err)
+	}
+
+	return h.sendFile(bot, chatID, "audit.json", data, len(entries))
+}
+
+// sendFile отправляет выгрузку журнала документом
+func (h *AuditHandler) sendFile(bot *tgbotapi.BotAPI, chatID int64, name string, data []byte, count int) error {
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	document.Caption = fmt.Sprintf("🧾 Журнал аудита: %d записей", count)
+	_, err := bot.Send(document)
+	return err
+}
+
+// sendUsage отправляет подсказку по команде /audit
+func (h *AuditHandler) sendUsage(bot *tgbotapi.BotAPI, chatID int64) error {
+	return sendText(bot, chatID,
+		"❌ Использование: /audit [фильтры] [csv|json|verify]\n"+
+			"Фильтры: actor=<ID или @username>, target=<ID или @username>, action=<префикс>, since=<7d>, limit=<N>\n"+
+			"Пример: /audit action=user. since=7d csv")
+}
+
+// auditPaginator создаёт постраничный список записей журнала (кнопки aud_*)
+func auditPaginator() keyboard.Paginator[domain.AuditEntry] {
+	return keyboard.Paginator[domain.AuditEntry]{
+		Namespace: "aud",
+		PerPage:   auditPerPage,
+		Header:    "<b>🧾 Журнал аудита</b>",
+		Empty:     "🧾 Записей в журнале аудита не найдено.",
+		Separator: "\n",
+		Item: func(_ int, e domain.AuditEntry) string {
+			return auditEntryText(e)
+		},
+	}
+}
+
+// auditEntryText формирует одну запись журнала для отображения в чате
+func auditEntryText(e domain.AuditEntry) string {
+	var line strings.Builder
+	line.WriteString(fmt.Sprintf("%s <code>#%d</code> %s · <code>%d</code> → <b>%s</b>",
+		auditOutcomeIcon(e.Outcome), e.ID, e.At.Local().Format("2006-01-02 15:04"), e.ActorID, e.Action))
+	if e.TargetID != 0 {
+		line.WriteString(fmt.Sprintf(" → <code>%d</code>", e.TargetID))
+	}
+	if len(e.Params) > 0 {
+		line.WriteString("\n    " + html.EscapeString(truncateRunes(auditParams(e.Params), auditParamsLimit)))
+	}
+	if e.Error != "" {
+		line.WriteString("\n    ⚠️ " + html.EscapeString(truncateRunes(e.Error, auditParamsLimit)))
+	}
+	return line.String()
+}
+
+// auditOutcomeIcon возвращает значок результата действия
+func auditOutcomeIcon(outcome domain.AuditOutcome) string {
+	switch outcome {
+	case domain.AuditSuccess:
+		return "✅"
+	case domain.AuditDenied:
+		return "⛔"
+	default:
+		return "❌"
+	}
+}
+
+// auditParams записывает параметры действия в строку key=value, отсортированную по ключу
+func auditParams(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+strconv.Quote(params[key]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// truncateRunes обрезает строку до limit символов
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "…"
+}
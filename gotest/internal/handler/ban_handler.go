@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// BanHandler обрабатывает команду /ban <ID или @username> [срок] [причина]
+type BanHandler struct {
+	users *repository.UserRepository
+	bans  *service.BanService
+}
+
+// NewBanHandler создаёт новый обработчик команды /ban
+func NewBanHandler(users *repository.UserRepository, bans *service.BanService) *BanHandler {
+	return &BanHandler{users: users, bans: bans}
+}
+
+// Command возвращает команду
+func (h *BanHandler) Command() string {
+	return "ban"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *BanHandler) Permission() domain.Permission {
+	return domain.PermUsersBan
+}
+
+// Handle обрабатывает команду /ban
+func (h *BanHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return sendText(bot, msg.Chat.ID,
+			"❌ Использование: /ban <ID или @username> [срок] [причина]\n"+
+				"Срок: 30m, 12h, 7d, 2w. Без срока — бессрочно.\n"+
+				"Пример: /ban @spammer 7d реклама")
+	}
+
+	targetID, ok := resolveUserID(h.users, args[0])
+	if !ok {
+		return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Пользователь «%s» не найден.", args[0]))
+	}
+
+	var duration time.Duration
+	rest := args[1:]
+	if len(rest) > 0 {
+		if d, ok := parseBanDuration(rest[0]); ok {
+			duration = d
+			rest = rest[1:]
+		}
+	}
+	reason := strings.Join(rest, " ")
+
+	ban, err := h.bans.Ban(msg.From.ID, targetID, reason, duration)
+	if err != nil {
+		return sendRoleError(bot, msg.Chat.ID, err)
+	}
+
+	return sendText(bot, msg.Chat.ID, fmt.Sprintf("🚫 Пользователь %d заблокирован (%s).", targetID, banTerm(ban)))
+}
+
+// UnbanHandler обрабатывает команду /unban <ID или @username>
+type UnbanHandler struct {
+	users *repository.UserRepository
+	bans  *service.BanService
+}
+
+// NewUnbanHandler создаёт новый обработчик команды /unban
+func NewUnbanHandler(users *repository.UserRepository, bans *service.BanService) *UnbanHandler {
+	return &UnbanHandler{users: users, bans: bans}
+}
+
+// Command возвращает команду
+func (h *UnbanHandler) Command() string {
+	return "unban"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *UnbanHandler) Permission() domain.Permission {
+	return domain.PermUsersBan
+}
+
+// Handle обрабатывает команду /unban
+func (h *UnbanHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	query := strings.TrimSpace(msg.CommandArguments())
+	if query == "" {
+		return sendText(bot, msg.Chat.ID, "❌ Использование: /unban <ID или @username>")
+	}
+
+	targetID, ok := resolveUserID(h.users, query)
+	if !ok {
+		return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Пользователь «%s» не найден.", query))
+	}
+
+	if err := h.bans.Unban(msg.From.ID, targetID); err != nil {
+		if errors.Is(err, service.ErrNotBanned) {
+			return sendText(bot, msg.Chat.ID, "❌ Пользователь не заблокирован.")
+		}
+		return err
+	}
+
+	return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Пользователь %d разблокирован.", targetID))
+}
+
+// BanListHandler обрабатывает команду /banlist — список действующих блокировок
+type BanListHandler struct {
+	bans *service.BanService
+}
+
+// NewBanListHandler создаёт новый обработчик команды /banlist
+func NewBanListHandler(bans *service.BanService) *BanListHandler {
+	return &BanListHandler{bans: bans}
+}
+
+// Command возвращает команду
+func (h *BanListHandler) Command() string {
+	return "banlist"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *BanListHandler) Permission() domain.Permission {
+	return domain.PermUsersBan
+}
+
+// Handle обрабатывает команду /banlist
+func (h *BanListHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	bans := h.bans.List()
+
+	text := "<b>🚫 Заблокированные пользователи</b>\n\n"
+	if len(bans) == 0 {
+		text += "Заблокированных пользователей нет."
+	}
+	for _, ban := range bans {
+		text += fmt.Sprintf("<code>%d</code> — %s, выдал <code>%d</code>\n", ban.UserID, banTerm(ban), ban.BannedBy)
+		if ban.Reason != "" {
+			text += fmt.Sprintf("Причина: %s\n", html.EscapeString(ban.Reason))
+		}
+		text += "\n"
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ParseMode = tgbotapi.ModeHTML
+	_, err := bot.Send(reply)
+	return err
+}
+
+// resolveUserID возвращает ID пользователя по числовому ID или @username
+// Числовой ID принимается, даже если пользователь ещё не писал боту
+func resolveUserID(users *repository.UserRepository, query string) (int64, bool) {
+	if id, err := strconv.ParseInt(query, 10, 64); err == nil {
+		return id, true
+	}
+	if user := users.GetByUsername(query); user != nil {
+		return user.ID, true
+	}
+	return 0, false
+}
+
+// parseBanDuration разбирает срок блокировки: 30m, 12h, 7d, 2w
+func parseBanDuration(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+
+	switch s[len(s)-1] {
+	case 'm':
+		return time.Duration(n) * time.Minute, true
+	case 'h':
+		return time.Duration(n) * time.Hour, true
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, true
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
+
+// banTerm возвращает срок блокировки для отображения администратору
+func banTerm(ban domain.Ban) string {
+	if ban.Permanent() {
+		return "бессрочно"
+	}
+	return "до " + ban.ExpiresAt.Format("2006-01-02 15:04")
+}
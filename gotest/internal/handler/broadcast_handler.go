@@ -0,0 +1,406 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/service"
+)
+
+// maxBroadcastButtons — максимальное количество кнопок-ссылок в рассылке
+const maxBroadcastButtons = 10
+
+// broadcastStep — шаг составления рассылки
+type broadcastStep int
+
+const (
+	stepBroadcastContent  broadcastStep = iota // Ждём сообщение для рассылки
+	stepBroadcastButtons                       // Ждём кнопки-ссылки
+	stepBroadcastSegment                       // Ждём выбор аудитории (кнопками)
+	stepBroadcastCourse                        // Ждём ID курса для сегмента
+	stepBroadcastConfirm                       // Ждём подтверждение отправки
+	stepBroadcastSchedule                      // Ждём время или расписание cron
+)
+
+// broadcastDraft — черновик рассылки, который составляет администратор
+type broadcastDraft struct {
+	mu      sync.Mutex // Последовательная обработка ответов и кнопок одного черновика
+	step    broadcastStep
+	message domain.BroadcastMessage
+	segment domain.Segment
+}
+
+// BroadcastHandler обрабатывает команду /broadcast и шаги составления рассылки (bc_*)
+type BroadcastHandler struct {
+	broadcasts *service.BroadcastService
+	scheduler  *service.SchedulerService
+	mu         sync.Mutex
+	drafts     map[int64]*broadcastDraft // Ключ - ID администратора
+}
+
+// NewBroadcastHandler создаёт новый обработчик рассылок
+func NewBroadcastHandler(broadcasts *service.BroadcastService, scheduler *service.SchedulerService) *BroadcastHandler {
+	return &BroadcastHandler{
+		broadcasts: broadcasts,
+		scheduler:  scheduler,
+		drafts:     make(map[int64]*broadcastDraft),
+	}
+}
+
+// Command возвращает команду
+func (h *BroadcastHandler) Command() string {
+	return "broadcast"
+}
+
+// Prefix возвращает префикс callback-запросов рассылки
+func (h *BroadcastHandler) Prefix() string {
+	return "bc_"
+}
+
+// Permission возвращает разрешение, необходимое для рассылки
+func (h *BroadcastHandler) Permission() domain.Permission {
+	return domain.PermBroadcast
+}
+
+// Handle обрабатывает команду /broadcast — начинает составление рассылки
+func (h *BroadcastHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	return h.startDraft(bot, msg.Chat.ID, msg.From.ID)
+}
+
+// CancelDraft удаляет черновик рассылки администратора (по /cancel)
+func (h *BroadcastHandler) CancelDraft(adminID int64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, exists := h.drafts[adminID]
+	delete(h.drafts, adminID)
+	return exists
+}
+
+// HandleInput принимает ответы администратора на шагах составления рассылки
+func (h *BroadcastHandler) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	draft, exists := h.lockDraft(msg.From.ID)
+	if !exists {
+		return false, nil
+	}
+	defer draft.mu.Unlock()
+
+	chatID := msg.Chat.ID
+
+	switch draft.step {
+	case stepBroadcastContent:
+		message, ok := broadcastMessageFrom(msg)
+		if !ok {
+			return true, sendText(bot, chatID, "❌ Поддерживаются текст, фото, видео, GIF и документы. Отправьте сообщение ещё раз.")
+		}
+		draft.message = message
+		draft.step = stepBroadcastButtons
+
+		reply := tgbotapi.NewMessage(chatID, "🔗 Добавьте кнопки-ссылки: по одной в строке в формате\n"+
+			"<code>Текст кнопки | https://example.com</code>\n\n"+
+			"Или нажмите «Без кнопок».")
+		reply.ParseMode = tgbotapi.ModeHTML
+		reply.ReplyMarkup = keyboard.NewBroadcastButtonsKeyboard()
+		_, err := bot.Send(reply)
+		return true, err
+
+	case stepBroadcastButtons:
+		buttons, err := parseBroadcastButtons(msg.Text)
+		if err != nil {
+			return true, sendText(bot, chatID, "❌ "+err.Error())
+		}
+		draft.message.Buttons = buttons
+		return true, h.showPreview(bot, chatID, draft)
+
+	case stepBroadcastCourse:
+		courseID, err := strconv.Atoi(strings.TrimSpace(msg.Text))
+		if err != nil {
+			return true, sendText(bot, chatID, "❌ Отправьте числовой ID курса.")
+		}
+		draft.segment = domain.Segment{Kind: domain.SegmentCourse, CourseID: courseID}
+		return true, h.askConfirm(bot, chatID, 0, draft)
+
+	case stepBroadcastSchedule:
+		return true, h.schedule(bot, msg, draft)
+
+	default:
+		// На остальных шагах ждём нажатия кнопок
+		return true, sendText(bot, chatID, "Используйте кнопки под сообщением или нажмите «❌ Отмена».")
+	}
+}
+
+// HandleCallback обрабатывает кнопки составления рассылки
+func (h *BroadcastHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	data := callback.Data
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	adminID := callback.From.ID
+
+	if data == "bc_new" {
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.startDraft(bot, chatID, adminID)
+	}
+
+	draft, exists := h.lockDraft(adminID)
+	if !exists {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Черновик рассылки не найден. Начните заново: /broadcast"))
+		return err
+	}
+	defer draft.mu.Unlock()
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	switch {
+	case data == "bc_cancel" || data == "bc_send_no":
+		h.mu.Lock()
+		delete(h.drafts, adminID)
+		h.mu.Unlock()
+		return h.edit(bot, chatID, messageID, "❌ Рассылка отменена.", nil)
+
+	case data == "bc_nobuttons" && draft.step == stepBroadcastButtons:
+		draft.message.Buttons = nil
+		return h.showPreview(bot, chatID, draft)
+
+	// Кнопки аудитории работают только на шаге выбора аудитории: старая клавиатура
+	// не должна подставлять аудиторию в черновик, где ещё нет сообщения
+	case data == "bc_seg_all" && draft.step == stepBroadcastSegment:
+		draft.segment = domain.Segment{Kind: domain.SegmentAll}
+		return h.askConfirm(bot, chatID, messageID, draft)
+
+	case data == "bc_seg_lang" && draft.step == stepBroadcastSegment:
+		kb := keyboard.NewBroadcastLanguageKeyboard()
+		return h.edit(bot, chatID, messageID, "🌐 Выберите язык аудитории:", &kb)
+
+	case strings.HasPrefix(data, "bc_lang_") && draft.step == stepBroadcastSegment:
+		draft.segment = domain.Segment{Kind: domain.SegmentLanguage, Language: strings.TrimPrefix(data, "bc_lang_")}
+		return h.askConfirm(bot, chatID, messageID, draft)
+
+	case data == "bc_seg_course" && draft.step == stepBroadcastSegment && h.broadcasts.HasCourseSegment():
+		draft.step = stepBroadcastCourse
+		kb := keyboard.NewCancelKeyboard("bc_cancel")
+		return h.edit(bot, chatID, messageID, "📚 Отправьте ID курса, записанным на который нужно сделать рассылку:", &kb)
+
+	case data == "bc_schedule" && draft.step == stepBroadcastConfirm:
+		draft.step = stepBroadcastSchedule
+		kb := keyboard.NewCancelKeyboard("bc_cancel")
+		return h.edit(bot, chatID, messageID, "🕒 Когда отправить рассылку?\n\n"+
+			"Разово: <code>2026-01-31 09:00</code> или <code>2026-01-31 09:00 Europe/Moscow</code>\n"+
+			"По расписанию cron: <code>0 9 * * 1</code> или <code>CRON_TZ=Europe/Moscow 0 9 * * 1</code>\n"+
+			"(5 полей: минуты, часы, день месяца, месяц, день недели)", &kb)
+
+	case data == "bc_send_yes" && draft.step == stepBroadcastConfirm:
+		h.mu.Lock()
+		delete(h.drafts, adminID)
+		h.mu.Unlock()
+
+		job := &domain.Broadcast{
+			CreatedBy:         adminID,
+			ReportChatID:      chatID,
+			ProgressMessageID: messageID,
+			Message:           draft.message,
+			Segment:           draft.segment,
+		}
+		if err := h.broadcasts.Start(job); err != nil {
+			h.edit(bot, chatID, messageID, "❌ Не удалось запустить рассылку.", nil)
+			return err
+		}
+		return h.edit(bot, chatID, messageID, service.BroadcastProgressText(*job), nil)
+
+	default:
+		return nil
+	}
+}
+
+// lockDraft находит черновик администратора и блокирует его; вызывающий должен разблокировать draft.mu
+// Второе значение false, если черновика нет или его успели удалить или заменить
+func (h *BroadcastHandler) lockDraft(adminID int64) (*broadcastDraft, bool) {
+	h.mu.Lock()
+	draft, exists := h.drafts[adminID]
+	h.mu.Unlock()
+	if !exists {
+		return nil, false
+	}
+
+	draft.mu.Lock()
+	h.mu.Lock()
+	current := h.drafts[adminID] == draft
+	h.mu.Unlock()
+	if !current {
+		draft.mu.Unlock()
+		return nil, false
+	}
+	return draft, true
+}
+
+// startDraft создаёт новый черновик рассылки и просит прислать сообщение
+func (h *BroadcastHandler) startDraft(bot *tgbotapi.BotAPI, chatID, adminID int64) error {
+	h.mu.Lock()
+	h.drafts[adminID] = &broadcastDraft{step: stepBroadcastContent}
+	h.mu.Unlock()
+
+	reply := tgbotapi.NewMessage(chatID, "📣 Новая рассылка\n\n"+
+		"Отправьте сообщение для рассылки: текст, фото, видео, GIF или документ с подписью.")
+	reply.ReplyMarkup = keyboard.NewCancelKeyboard("bc_cancel")
+	_, err := bot.Send(reply)
+	return err
+}
+
+// showPreview отправляет предпросмотр рассылки и предлагает выбрать аудиторию
+func (h *BroadcastHandler) showPreview(bot *tgbotapi.BotAPI, chatID int64, draft *broadcastDraft) error {
+	draft.step = stepBroadcastSegment
+
+	if err := sendText(bot, chatID, "👀 Предпросмотр рассылки:"); err != nil {
+		return err
+	}
+	if err := h.broadcasts.Preview(chatID, draft.message); err != nil {
+		sendText(bot, chatID, "❌ Не удалось показать предпросмотр. Проверьте ссылки в кнопках и начните заново: /broadcast")
+		return err
+	}
+
+	reply := tgbotapi.NewMessage(chatID, "👥 Выберите аудиторию рассылки:")
+	reply.ReplyMarkup = keyboard.NewBroadcastSegmentKeyboard(h.broadcasts.HasCourseSegment())
+	_, err := bot.Send(reply)
+	return err
+}
+
+// askConfirm показывает размер аудитории и просит подтвердить отправку
+// Если messageID равен 0, отправляет новое сообщение вместо редактирования
+func (h *BroadcastHandler) askConfirm(bot *tgbotapi.BotAPI, chatID int64, messageID int, draft *broadcastDraft) error {
+	count := len(h.broadcasts.Audience(draft.segment))
+	if count == 0 {
+		draft.step = stepBroadcastSegment
+		text := "⚠️ В выбранной аудитории нет получателей. Выберите другую аудиторию:"
+		kb := keyboard.NewBroadcastSegmentKeyboard(h.broadcasts.HasCourseSegment())
+		if messageID == 0 {
+			reply := tgbotapi.NewMessage(chatID, text)
+			reply.ReplyMarkup = kb
+			_, err := bot.Send(reply)
+			return err
+		}
+		return h.edit(bot, chatID, messageID, text, &kb)
+	}
+
+	draft.step = stepBroadcastConfirm
+	text := fmt.Sprintf("📣 Аудитория: %s\nПолучателей: %d\n\nОтправить рассылку?", segmentTitle(draft.segment), count)
+	kb := keyboard.NewBroadcastConfirmKeyboard()
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ReplyMarkup = kb
+		_, err := bot.Send(reply)
+		return err
+	}
+	return h.edit(bot, chatID, messageID, text, &kb)
+}
+
+// schedule сохраняет черновик как запланированную рассылку
+func (h *BroadcastHandler) schedule(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, draft *broadcastDraft) error {
+	job := &domain.ScheduledBroadcast{
+		CreatedBy:    msg.From.ID,
+		ReportChatID: msg.Chat.ID,
+		Message:      draft.message,
+		Segment:      draft.segment,
+	}
+
+	if err := h.scheduler.Schedule(job, msg.Text); err != nil {
+		return sendText(bot, msg.Chat.ID, "❌ "+err.Error()+". Попробуйте ещё раз.")
+	}
+
+	h.mu.Lock()
+	delete(h.drafts, msg.From.ID)
+	h.mu.Unlock()
+
+	return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Рассылка #%d запланирована.\nСледующий запуск: %s (%s)\n\nУправление: /schedules",
+		job.ID, job.NextRunAt.In(scheduleLocation(*job)).Format("2006-01-02 15:04"), job.TimeZone))
+}
+
+// edit заменяет текст и клавиатуру сообщения
+func (h *BroadcastHandler) edit(bot *tgbotapi.BotAPI, chatID int64, messageID int, text string, kb *tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.ReplyMarkup = kb
+	_, err := bot.Send(edit)
+	return err
+}
+
+// broadcastMessageFrom извлекает содержимое рассылки из сообщения администратора
+func broadcastMessageFrom(msg *tgbotapi.Message) (domain.BroadcastMessage, bool) {
+	message := domain.BroadcastMessage{
+		Text:     msg.Caption,
+		Entities: msg.CaptionEntities,
+	}
+
+	switch {
+	case len(msg.Photo) > 0:
+		message.MediaType = domain.MediaPhoto
+		message.FileID = msg.Photo[len(msg.Photo)-1].FileID // Самое большое разрешение
+	case msg.Video != nil:
+		message.MediaType = domain.MediaVideo
+		message.FileID = msg.Video.FileID
+	case msg.Animation != nil:
+		// Проверяем до Document: у GIF Telegram заполняет оба поля
+		message.MediaType = domain.MediaAnimation
+		message.FileID = msg.Animation.FileID
+	case msg.Document != nil:
+		message.MediaType = domain.MediaDocument
+		message.FileID = msg.Document.FileID
+	case msg.Text != "":
+		message.Text = msg.Text
+		message.Entities = msg.Entities
+	default:
+		return domain.BroadcastMessage{}, false
+	}
+
+	return message, true
+}
+
+// parseBroadcastButtons разбирает кнопки в формате «Текст | URL», по одной в строке
+func parseBroadcastButtons(text string) ([]domain.BroadcastButton, error) {
+	var buttons []domain.BroadcastButton
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		label, link, found := strings.Cut(line, "|")
+		label = strings.TrimSpace(label)
+		link = strings.TrimSpace(link)
+		if !found || label == "" || link == "" {
+			return nil, fmt.Errorf("неверный формат строки «%s». Используйте: Текст | https://example.com", line)
+		}
+
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https" && parsed.Scheme != "tg") {
+			return nil, fmt.Errorf("неверная ссылка «%s». Ссылка должна начинаться с https://, http:// или tg://", link)
+		}
+
+		buttons = append(buttons, domain.BroadcastButton{Text: label, URL: link})
+	}
+
+	if len(buttons) == 0 {
+		return nil, fmt.Errorf("не найдено ни одной кнопки. Используйте формат: Текст | https://example.com")
+	}
+	if len(buttons) > maxBroadcastButtons {
+		return nil, fmt.Errorf("слишком много кнопок: максимум %d", maxBroadcastButtons)
+	}
+	return buttons, nil
+}
+
+// segmentTitle возвращает описание аудитории рассылки
+func segmentTitle(segment domain.Segment) string {
+	switch segment.Kind {
+	case domain.SegmentLanguage:
+		return "пользователи с языком " + segment.Language
+	case domain.SegmentCourse:
+		return fmt.Sprintf("записанные на курс #%d", segment.CourseID)
+	default:
+		return "все пользователи с включёнными уведомлениями"
+	}
+}
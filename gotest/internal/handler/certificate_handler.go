@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// VerifyHandler обрабатывает команду /verify <код> — проверку подлинности сертификата
+// Команда доступна всем: код сертификата может проверить кто угодно, не только его владелец
+type VerifyHandler struct {
+	certificates *service.CertificateService
+	settings     *repository.SettingsRepository
+}
+
+// NewVerifyHandler создаёт новый обработчик команды /verify
+func NewVerifyHandler(certificates *service.CertificateService, settings *repository.SettingsRepository) *VerifyHandler {
+	return &VerifyHandler{
+		certificates: certificates,
+		settings:     settings,
+	}
+}
+
+// Command возвращает команду
+func (h *VerifyHandler) Command() string {
+	return "verify"
+}
+
+// Handle обрабатывает команду /verify
+func (h *VerifyHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	code := strings.TrimSpace(msg.CommandArguments())
+	if code == "" {
+		return sendText(bot, msg.Chat.ID,
+			"❌ Использование: /verify <код>\nКод указан на сертификате, например: /verify ABCD-EFGH-JK")
+	}
+
+	certificate, err := h.certificates.Verify(code)
+	if errors.Is(err, repository.ErrCertificateNotFound) {
+		return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Сертификат с кодом «%s» не найден.", code))
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка проверки сертификата: %w", err)
+	}
+
+	lang := h.settings.Get(msg.From.ID).Language
+	return sendHTML(bot, msg.Chat.ID, certificateVerifiedText(certificate, lang))
+}
+
+// sendCertificate рисует сертификат и отправляет его: PNG — фото, PDF — документом
+func sendCertificate(bot *tgbotapi.BotAPI, chatID int64, certificate domain.Certificate, lang string, certificates *service.CertificateService) error {
+	data, name, err := certificates.Render(certificate, lang)
+	if err != nil {
+		return err
+	}
+
+	file := tgbotapi.FileBytes{Name: name, Bytes: data}
+	caption := fmt.Sprintf("📜 Сертификат № %s\nПроверить подлинность: /verify %s", certificate.Code, certificate.Code)
+
+	// Кнопка копирования кода не поддерживается tgbotapi, поэтому используется расширенная разметка
+	markup, err := keyboard.NewBuilder().CopyText("📋 Скопировать код", certificate.Code).Markup()
+	if err != nil {
+		return err
+	}
+
+	var msg tgbotapi.Chattable
+	if certificates.Format() == service.CertificatePDF {
+		document := tgbotapi.NewDocument(chatID, file)
+		document.Caption = caption
+		document.ReplyMarkup = markup
+		msg = document
+	} else {
+		photo := tgbotapi.NewPhoto(chatID, file)
+		photo.Caption = caption
+		photo.ReplyMarkup = markup
+		msg = photo
+	}
+	if _, err := bot.Send(msg); err != nil {
+		return fmt.Errorf("ошибка отправки сертификата: %w", err)
+	}
+
+	log.Printf("Сертификат %s отправлен в чат %d", certificate.Code, chatID)
+	return nil
+}
+
+// certificateName возвращает имя пользователя для сертификата
+// Если имя не заполнено, используется @username
+func certificateName(user *tgbotapi.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+	return fmt.Sprintf("ID %d", user.ID)
+}
+
+// certificateVerifiedText формирует ответ на успешную проверку сертификата
+func certificateVerifiedText(certificate domain.Certificate, lang string) string {
+	return fmt.Sprintf("✅ <b>Сертификат подлинный</b>\n\n"+
+		"<b>Код:</b> <code>%s</code>\n"+
+		"<b>Выдан:</b> %s\n"+
+		"<b>Курс:</b> %s\n"+
+		"<b>Дата выдачи:</b> %s",
+		certificate.Code,
+		html.EscapeString(certificate.UserName),
+		html.EscapeString(certificate.CourseTitle.Get(lang)),
+		certificate.IssuedAt.Format("02.01.2006"),
+	)
+}
@@ -0,0 +1,533 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// courseNewForm — имя формы создания курса; формы изменения называются course_edit_<поле>
+const courseNewForm = "course_new"
+
+// courseFormResult — заполненная форма нового курса
+// Значения хранятся как введены и разбираются setCourseField
+type courseFormResult struct {
+	Title       string `form:"title"`
+	Description string `form:"desc"`
+	Category    string `form:"cat"`
+	Cover       string `form:"cover"`
+	Price       string `form:"price"`
+}
+
+// courseEditResult — заполненная форма изменения одного поля курса
+type courseEditResult struct {
+	ID    int    `form:"id"` // Передаётся при старте формы, в сводке не показывается
+	Value string `form:"value"`
+}
+
+// courseAuditFields сопоставляет поле формы (и callback crs_edit_<id>_<поле>) с названием поля в журнале аудита
+var courseAuditFields = map[string]string{
+	"title": "title",
+	"desc":  "description",
+	"cat":   "category",
+	"cover": "cover",
+	"price": "price",
+}
+
+// localePrefix — строка «en: текст», начинающая перевод на другой язык
+var localePrefix = regexp.MustCompile(`^(ru|en|zh):\s*(.*)$`)
+
+// CourseAdminHandler обрабатывает команду /managecourses и управление курсами (crs_*)
+// Создание и изменение курса идут формами поверх диалогов FSM
+type CourseAdminHandler struct {
+	courses   *service.CourseService
+	fsm       *FSM
+	newForm   *Form[courseFormResult]
+	editForms map[string]*Form[courseEditResult] // Ключ - поле из callback crs_edit_<id>_<поле>
+}
+
+// NewCourseAdminHandler создаёт новый обработчик управления курсами и регистрирует его формы в fsm
+func NewCourseAdminHandler(courses *service.CourseService, fsm *FSM) *CourseAdminHandler {
+	h := &CourseAdminHandler{
+		courses:   courses,
+		fsm:       fsm,
+		editForms: make(map[string]*Form[courseEditResult]),
+	}
+
+	fields := courseFormFields()
+	h.newForm = &Form[courseFormResult]{
+		Name:     courseNewForm,
+		Title:    domain.LocalizedText{"ru": "📚 Новый курс (будет создан скрытым — опубликуйте его, когда будете готовы)"},
+		Fields:   fields,
+		OnSubmit: h.submitNew,
+	}
+	h.newForm.Register(fsm)
+
+	for _, field := range fields {
+		key := field.Name
+		field.Name = "value"
+		// При изменении пропустить можно только обложку — это удаляет её
+		field.Optional = key == "cover"
+		form := &Form[courseEditResult]{
+			Name:     "course_edit_" + key,
+			Title:    domain.LocalizedText{"ru": "✏️ Изменение курса"},
+			Fields:   []Field{field},
+			OnSubmit: h.submitEdit(key),
+		}
+		form.Register(fsm)
+		h.editForms[key] = form
+	}
+
+	return h
+}
+
+// Command возвращает команду
+func (h *CourseAdminHandler) Command() string {
+	return "managecourses"
+}
+
+// Prefix возвращает префикс callback-запросов управления курсами
+func (h *CourseAdminHandler) Prefix() string {
+	return "crs_"
+}
+
+// Permission возвращает разрешение, необходимое для управления курсами
+func (h *CourseAdminHandler) Permission() domain.Permission {
+	return domain.PermCourses
+}
+
+// Handle обрабатывает команду /managecourses — показывает список курсов
+func (h *CourseAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	return h.showList(bot, msg.Chat.ID, 0)
+}
+
+// HandleCallback обрабатывает кнопки управления курсами
+func (h *CourseAdminHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	data := callback.Data
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	adminID := callback.From.ID
+
+	switch data {
+	case "crs_list":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.showList(bot, chatID, messageID)
+
+	case "crs_new":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.newForm.Start(h.fsm, bot, chatID, adminID, domain.DefaultLanguage)
+	}
+
+	// Формат: crs_<действие>_<id>[_<поле>]
+	parts := strings.Split(strings.TrimPrefix(data, "crs_"), "_")
+	if len(parts) < 2 {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	action := parts[0]
+	courseID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	course, err := h.courses.Get(courseID)
+	if errors.Is(err, repository.ErrCourseNotFound) {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Курс не найден"))
+		return err
+	}
+	if err != nil {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+		return err
+	}
+
+	callbackText := ""
+	switch action {
+	case "view":
+		// Просто показываем карточку курса
+
+	case "edit":
+		if len(parts) < 3 {
+			return nil
+		}
+		form, ok := h.editForms[parts[2]]
+		if !ok {
+			return nil
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return form.StartWith(h.fsm, bot, chatID, adminID, domain.DefaultLanguage,
+			map[string]string{"id": strconv.Itoa(course.ID)})
+
+	case "pub", "unpub":
+		published := action == "pub"
+		if err := h.courses.SetPublished(adminID, courseID, published); err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+		course.Published = published
+		callbackText = "👁 Курс опубликован"
+		if !published {
+			callbackText = "🙈 Курс снят с публикации"
+		}
+
+	case "up", "down":
+		delta := 1
+		if action == "up" {
+			delta = -1
+		}
+		if err := h.courses.Move(adminID, courseID, delta); err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "↕️ Порядок изменён"))
+		return h.showList(bot, chatID, messageID)
+
+	case "preview":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.sendPreview(bot, chatID, course)
+
+	case "del":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		kb := keyboard.NewCourseDeleteKeyboard(courseID)
+		return h.edit(bot, chatID, messageID,
+			fmt.Sprintf("🗑 Удалить курс «%s»? Это действие нельзя отменить.\n\nКурс с записями или оплаченными заказами удалить нельзя — снимите его с публикации.", course.Title.Get(domain.DefaultLanguage)), &kb)
+
+	case "delyes":
+		err := h.courses.Delete(adminID, courseID)
+		if errors.Is(err, service.ErrCourseInUse) {
+			_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, "❌ "+err.Error()))
+			return err
+		}
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "🗑 Курс удалён"))
+		return h.showList(bot, chatID, messageID)
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, callbackText))
+	kb := keyboard.NewCourseAdminKeyboard(course)
+	return h.edit(bot, chatID, messageID, courseAdminText(course), &kb)
+}
+
+// showList показывает все курсы в порядке каталога
+// Если messageID равен 0, отправляет новое сообщение вместо редактирования
+func (h *CourseAdminHandler) showList(bot *tgbotapi.BotAPI, chatID int64, messageID int) error {
+	courses, err := h.courses.All()
+	if err != nil {
+		sendText(bot, chatID, "❌ Не удалось загрузить курсы.")
+		return err
+	}
+
+	text := fmt.Sprintf("📚 Курсы (%d)\n\n👁 — опубликован, 🙈 — скрыт. Выберите курс для изменения:", len(courses))
+	kb := keyboard.NewCourseAdminListKeyboard(courses)
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ReplyMarkup = kb
+		_, err := bot.Send(reply)
+		return err
+	}
+	return h.edit(bot, chatID, messageID, text, &kb)
+}
+
+// courseFormFields описывает поля курса в порядке создания
+// Имя поля совпадает с полем из callback crs_edit_<id>_<поле>
+func courseFormFields() []Field {
+	return []Field{
+		{
+			Name:  "title",
+			Type:  FieldText,
+			Label: domain.LocalizedText{"ru": "Название"},
+			Prompt: domain.LocalizedText{"ru": "✏️ Отправьте название курса (до 64 символов).\n\n" +
+				"Переводы — отдельными строками:\nGo для начинающих\nen: Go for Beginners"},
+			Validators: []Validator{courseFieldValidator("title")},
+		},
+		{
+			Name:  "desc",
+			Type:  FieldText,
+			Label: domain.LocalizedText{"ru": "Описание"},
+			Prompt: domain.LocalizedText{"ru": "✏️ Отправьте описание курса (до 1000 символов).\n\n" +
+				"Перевод начинается со строки «en:» или «zh:»"},
+			Validators: []Validator{courseFieldValidator("desc")},
+		},
+		{
+			Name:       "cat",
+			Type:       FieldText,
+			Label:      domain.LocalizedText{"ru": "Категория"},
+			Prompt:     domain.LocalizedText{"ru": "🏷 Отправьте категорию курса латиницей, например backend или devops."},
+			Validators: []Validator{courseFieldValidator("cat")},
+		},
+		{
+			Name:     "cover",
+			Type:     FieldPhoto,
+			Label:    domain.LocalizedText{"ru": "Обложка"},
+			Prompt:   domain.LocalizedText{"ru": "🖼 Отправьте фото обложки курса или пропустите этот шаг, чтобы курс был без обложки."},
+			Optional: true,
+		},
+		{
+			Name:  "price",
+			Type:  FieldText,
+			Label: domain.LocalizedText{"ru": "Цена"},
+			Prompt: domain.LocalizedText{"ru": "💳 Отправьте цену курса:\n" +
+				"250 XTR — в Telegram Stars (без платёжного провайдера)\n" +
+				"990 RUB или 9.90 USD — через платёжного провайдера\n" +
+				"0 — бесплатный курс\n\n" +
+				"Платный курс доступен после покупки или по подписке."},
+			Optional:   true,
+			Validators: []Validator{courseFieldValidator("price")},
+		},
+	}
+}
+
+// submitNew создаёт скрытый курс из заполненной формы
+func (h *CourseAdminHandler) submitNew(bot *tgbotapi.BotAPI, chatID, adminID int64, result courseFormResult) error {
+	var course domain.Course
+	values := map[string]string{
+		"title": result.Title,
+		"desc":  result.Description,
+		"cat":   result.Category,
+		"cover": result.Cover,
+		"price": result.Price,
+	}
+	for key, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := setCourseField(&course, key, value); err != nil {
+			return sendText(bot, chatID, "❌ "+err.Error())
+		}
+	}
+
+	if err := h.courses.Create(adminID, &course); err != nil {
+		if errors.Is(err, service.ErrCourseInvalid) {
+			return sendText(bot, chatID, "❌ "+err.Error())
+		}
+		sendText(bot, chatID, "❌ Не удалось сохранить курс.")
+		return err
+	}
+
+	reply := tgbotapi.NewMessage(chatID, "✅ Курс создан.\n\n"+courseAdminText(course))
+	reply.ReplyMarkup = keyboard.NewCourseAdminKeyboard(course)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// submitEdit возвращает обработчик формы, которая меняет поле key существующего курса
+func (h *CourseAdminHandler) submitEdit(key string) func(*tgbotapi.BotAPI, int64, int64, courseEditResult) error {
+	return func(bot *tgbotapi.BotAPI, chatID, adminID int64, result courseEditResult) error {
+		// Берём актуальную версию курса: пока администратор вводил значение, курс могли изменить
+		course, err := h.courses.Get(result.ID)
+		if errors.Is(err, repository.ErrCourseNotFound) {
+			return sendText(bot, chatID, "❌ Курс не найден.")
+		}
+		if err != nil {
+			sendText(bot, chatID, "❌ Не удалось загрузить курс.")
+			return err
+		}
+
+		if err := setCourseField(&course, key, result.Value); err != nil {
+			return sendText(bot, chatID, "❌ "+err.Error())
+		}
+		if err := h.courses.Update(adminID, &course, courseAuditFields[key]); err != nil {
+			if errors.Is(err, service.ErrCourseInvalid) {
+				return sendText(bot, chatID, "❌ "+err.Error())
+			}
+			sendText(bot, chatID, "❌ Не удалось сохранить курс.")
+			return err
+		}
+
+		reply := tgbotapi.NewMessage(chatID, "✅ Курс обновлён.\n\n"+courseAdminText(course))
+		reply.ReplyMarkup = keyboard.NewCourseAdminKeyboard(course)
+		_, err = bot.Send(reply)
+		return err
+	}
+}
+
+// sendPreview показывает, как курс увидят пользователи
+func (h *CourseAdminHandler) sendPreview(bot *tgbotapi.BotAPI, chatID int64, course domain.Course) error {
+	if err := sendText(bot, chatID, "👀 Так курс увидят пользователи:"); err != nil {
+		return err
+	}
+
+	if course.CoverFileID != "" {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(course.CoverFileID))
+		photo.Caption = course.Title.Get(domain.DefaultLanguage)
+		if _, err := bot.Send(photo); err != nil {
+			return err
+		}
+	}
+
+	reply := tgbotapi.NewMessage(chatID, CourseDetailsText(course, domain.DefaultLanguage))
+	// Кнопка записи в предпросмотре не показывается: курс может быть ещё не опубликован
+	reply.ReplyMarkup = keyboard.NewCourseDetailsKeyboard(course, nil, false, false)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// edit заменяет текст и клавиатуру сообщения
+func (h *CourseAdminHandler) edit(bot *tgbotapi.BotAPI, chatID int64, messageID int, text string, kb *tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ReplyMarkup = kb
+	_, err := bot.Send(edit)
+	return err
+}
+
+// CourseDetailsText формирует экран курса для пользователя на языке lang
+func CourseDetailsText(course domain.Course, lang string) string {
+	text := fmt.Sprintf("📚 %s\n\n%s\n\n%s\n⏱ %s\n💰 %s",
+		course.Title.Get(lang),
+		course.Description.Get(lang),
+		course.Level.Title(),
+		course.DurationText(),
+		course.PriceText(),
+	)
+	if !course.Free() {
+		text += "\n💎 Входит в подписку: /premium"
+	}
+	return text
+}
+
+// courseAdminText формирует карточку курса для администратора
+func courseAdminText(course domain.Course) string {
+	status := "👁 опубликован"
+	if !course.Published {
+		status = "🙈 скрыт"
+	}
+	cover := "нет"
+	if course.CoverFileID != "" {
+		cover = "есть"
+	}
+
+	text := fmt.Sprintf("📚 #%d %s\n\nSlug: %s\nКатегория: %s\nЦена: %s\nСтатус: %s\nОбложка: %s\nПозиция: %d",
+		course.ID, course.Title.Get(domain.DefaultLanguage), course.Slug, course.Category, course.PriceText(), status, cover, course.Position)
+
+	for _, lang := range []string{"en", "zh"} {
+		if title, ok := course.Title[lang]; ok {
+			text += fmt.Sprintf("\nНазвание (%s): %s", lang, title)
+		}
+	}
+	return text
+}
+
+// parseLocalized разбирает текст с переводами
+// Текст до первой строки «xx:» относится к языку по умолчанию,
+// строка «en: ...» начинает перевод на английский и т.д.
+func parseLocalized(text string) domain.LocalizedText {
+	result := make(domain.LocalizedText)
+	lang := domain.DefaultLanguage
+	var lines []string
+
+	flush := func() {
+		if value := strings.TrimSpace(strings.Join(lines, "\n")); value != "" {
+			result[lang] = value
+		}
+		lines = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if match := localePrefix.FindStringSubmatch(line); match != nil {
+			flush()
+			lang = match[1]
+			lines = append(lines, match[2])
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return result
+}
+
+// setCourseField записывает в курс значение поля формы key в том виде, в каком его ввёл администратор
+func setCourseField(course *domain.Course, key, value string) error {
+	switch key {
+	case "title":
+		course.Title = parseLocalized(value)
+	case "desc":
+		course.Description = parseLocalized(value)
+	case "cat":
+		course.Category = strings.ToLower(strings.TrimSpace(value))
+	case "cover":
+		course.CoverFileID = value
+	case "price":
+		price, currency, err := parseCoursePrice(value)
+		if err != nil {
+			return err
+		}
+		course.Price = price
+		if currency != "" {
+			course.Currency = currency
+		}
+	}
+	return nil
+}
+
+// courseFieldValidator проверяет значение поля формы key правилами service.ValidateCourse
+// Остальные поля курса на этом шаге ещё могут быть пустыми, поэтому проверяется только это поле
+func courseFieldValidator(key string) Validator {
+	return func(value string) domain.LocalizedText {
+		check := domain.Course{
+			Title:       domain.LocalizedText{domain.DefaultLanguage: "-"},
+			Description: domain.LocalizedText{domain.DefaultLanguage: "-"},
+			Category:    "general",
+		}
+		err := setCourseField(&check, key, value)
+		if err == nil {
+			err = service.ValidateCourse(check)
+		}
+		if err == nil {
+			return nil
+		}
+		return domain.LocalizedText{domain.DefaultLanguage: err.Error() + ". Попробуйте ещё раз."}
+	}
+}
+
+// parseCoursePrice разбирает цену курса: «0», «250 XTR», «990 RUB», «9.90 USD»
+// Возвращает сумму в минимальных единицах валюты; для «0» валюта пустая — её менять не нужно
+func parseCoursePrice(text string) (int64, string, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 1 && fields[0] == "0" {
+		return 0, "", nil
+	}
+	if len(fields) != 2 {
+		return 0, "", errors.New("укажите сумму и валюту, например 250 XTR")
+	}
+
+	currency := strings.ToUpper(fields[1])
+	if currency == domain.CurrencyStars {
+		stars, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil || stars <= 0 {
+			return 0, "", errors.New("цена в Stars — целое положительное число")
+		}
+		return stars, currency, nil
+	}
+
+	// Остальные валюты хранятся в сотых долях: 9.90 USD -> 990
+	units, cents, found := strings.Cut(strings.ReplaceAll(fields[0], ",", "."), ".")
+	if found && len(cents) == 1 {
+		cents += "0"
+	}
+	if !found {
+		cents = "00"
+	}
+	whole, err := strconv.ParseInt(units, 10, 64)
+	fraction, fracErr := strconv.ParseInt(cents, 10, 64)
+	if err != nil || fracErr != nil || len(cents) != 2 || strings.ContainsAny(fields[0], "+-") || whole*100+fraction <= 0 {
+		return 0, "", errors.New("неверная сумма: используйте формат 990 или 9.90")
+	}
+	return whole*100 + fraction, currency, nil
+}
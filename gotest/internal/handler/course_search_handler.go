@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// maxSearchQuery — максимальная длина поискового запроса в символах
+const maxSearchQuery = 100
+
+// courseSearchScene — сценарий ввода поискового запроса после кнопки «🔍 Поиск»
+const courseSearchScene = "course_search"
+
+// courseSearch — текущий поиск пользователя по каталогу
+type courseSearch struct {
+	filter domain.CourseFilter
+	page   int
+}
+
+// CourseSearchHandler обрабатывает команду /courses [запрос] и поиск по каталогу (srch_*)
+type CourseSearchHandler struct {
+	courses  *service.CourseService
+	settings *repository.SettingsRepository
+	fsm      *FSM
+	mu       sync.Mutex
+	searches map[int64]*courseSearch // Ключ - ID пользователя
+}
+
+// NewCourseSearchHandler создаёт новый обработчик поиска курсов
+// и регистрирует в fsm сценарий ввода поискового запроса
+func NewCourseSearchHandler(courses *service.CourseService, settings *repository.SettingsRepository, fsm *FSM) *CourseSearchHandler {
+	h := &CourseSearchHandler{
+		courses:  courses,
+		settings: settings,
+		fsm:      fsm,
+		searches: make(map[int64]*courseSearch),
+	}
+	fsm.Register(&Scene[struct{}]{
+		Name:    courseSearchScene,
+		First:   "query",
+		Steps:   map[string]Step[struct{}]{"query": {Handle: h.handleQuery}},
+		Timeout: 10 * time.Minute,
+		// Поиск без запроса не требует уведомления: пользователь просто ушёл
+		OnTimeout: func(*tgbotapi.BotAPI, int64, struct{}) error { return nil },
+		OnCancel: func(bot *tgbotapi.BotAPI, chatID int64, _ struct{}) error {
+			return sendText(bot, chatID, "❌ Поиск отменён.")
+		},
+	})
+	return h
+}
+
+// Command возвращает команду
+func (h *CourseSearchHandler) Command() string {
+	return "courses"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *CourseSearchHandler) Prefix() string {
+	return "srch_"
+}
+
+// Handle обрабатывает команду /courses — ищет курсы по запросу или показывает весь каталог с фильтрами
+func (h *CourseSearchHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	query := strings.TrimSpace(msg.CommandArguments())
+	if len([]rune(query)) > maxSearchQuery {
+		return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Запрос слишком длинный (максимум %d символов).", maxSearchQuery))
+	}
+
+	if err := h.fsm.Cancel(msg.Chat.ID, msg.From.ID, courseSearchScene); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.searches[msg.From.ID] = &courseSearch{filter: domain.CourseFilter{Query: query}}
+	h.mu.Unlock()
+
+	return h.show(bot, msg.From.ID, msg.Chat.ID, 0)
+}
+
+// handleQuery принимает текст запроса после кнопки «🔍 Поиск»
+func (h *CourseSearchHandler) handleQuery(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, _ *struct{}) (Transition, error) {
+	query := strings.TrimSpace(msg.Text)
+	if query == "" || len([]rune(query)) > maxSearchQuery {
+		return Stay(), sendText(bot, msg.Chat.ID,
+			fmt.Sprintf("❌ Отправьте текст запроса (до %d символов) или /cancel.", maxSearchQuery))
+	}
+
+	// Поиск мог пропасть из памяти при перезапуске — тогда ищем без фильтров
+	h.mu.Lock()
+	search, exists := h.searches[msg.From.ID]
+	if !exists {
+		search = &courseSearch{}
+		h.searches[msg.From.ID] = search
+	}
+	search.filter.Query = query
+	search.page = 0
+	h.mu.Unlock()
+
+	return Finish(), h.show(bot, msg.From.ID, msg.Chat.ID, 0)
+}
+
+// HandleCallback обрабатывает кнопки поиска, фильтров и страниц результатов
+// Формат: srch_ask, srch_show, srch_reset, srch_p_<страница>, srch_pick_<страница>,
+// srch_cats, srch_cat_<номер категории|all>, srch_levels, srch_lvl_<уровень|all>
+func (h *CourseSearchHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	action, arg, _ := strings.Cut(strings.TrimPrefix(callback.Data, "srch_"), "_")
+
+	h.mu.Lock()
+	search, exists := h.searches[userID]
+	if !exists {
+		search = &courseSearch{}
+		h.searches[userID] = search
+	}
+	filter := search.filter
+	h.mu.Unlock()
+
+	// Любая кнопка, кроме «Поиск», отменяет ожидание запроса
+	if action != "ask" {
+		if err := h.fsm.Cancel(chatID, userID, courseSearchScene); err != nil {
+			return err
+		}
+	}
+
+	switch action {
+	case "ask":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageText(chatID, messageID,
+			"🔍 Отправьте название курса или слова из описания.\nОпечатки не страшны: «микросервесы» найдёт «Микросервисы».")
+		kb := keyboard.NewCancelKeyboard("srch_show")
+		edit.ReplyMarkup = &kb
+		if _, err := bot.Send(edit); err != nil {
+			return err
+		}
+		return h.fsm.Start(bot, chatID, userID, courseSearchScene, nil)
+
+	case "show":
+		// Показываем текущую страницу ниже
+
+	case "reset":
+		h.update(userID, func(s *courseSearch) { s.filter = domain.CourseFilter{} })
+
+	case "p", "pick":
+		page, picker, ok := courseSearchPaginator(nil, filter, "").Parse(callback.Data)
+		if !ok {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка навигации"))
+			return err
+		}
+		if picker {
+			bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+			return h.showPicker(bot, userID, chatID, messageID, page)
+		}
+		h.update(userID, func(s *courseSearch) { s.page = page })
+
+	case "cats":
+		categories, err := h.courses.Categories()
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return fmt.Errorf("ошибка загрузки категорий: %w", err)
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, "🗂 Выберите категорию:")
+		kb := keyboard.NewCategoryFilterKeyboard(categories, filter.Category)
+		edit.ReplyMarkup = &kb
+		_, err = bot.Send(edit)
+		return err
+
+	case "cat":
+		category := ""
+		if arg != "all" {
+			categories, err := h.courses.Categories()
+			if err != nil {
+				bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+				return fmt.Errorf("ошибка загрузки категорий: %w", err)
+			}
+			index, err := strconv.Atoi(arg)
+			if err != nil || index < 0 || index >= len(categories) {
+				_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Категория не найдена"))
+				return err
+			}
+			category = categories[index]
+		}
+		h.update(userID, func(s *courseSearch) { s.filter.Category = category })
+
+	case "levels":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, "📶 Выберите уровень сложности:")
+		kb := keyboard.NewLevelFilterKeyboard(filter.Level)
+		edit.ReplyMarkup = &kb
+		_, err := bot.Send(edit)
+		return err
+
+	case "lvl":
+		level := domain.CourseLevel("")
+		if arg != "all" {
+			level = domain.CourseLevel(arg)
+			if !slices.Contains(domain.CourseLevels, level) {
+				_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестный уровень"))
+				return err
+			}
+		}
+		h.update(userID, func(s *courseSearch) { s.filter.Level = level })
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+	return h.show(bot, userID, chatID, messageID)
+}
+
+// update меняет поиск пользователя; фильтры сбрасывают выдачу на первую страницу
+func (h *CourseSearchHandler) update(userID int64, change func(s *courseSearch)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	search := h.searches[userID]
+	filter := search.filter
+	change(search)
+	if search.filter != filter {
+		search.page = 0
+	}
+}
+
+// show показывает текущую страницу результатов поиска
+// messageID — сообщение для изменения (0 — отправить новое сообщение)
+func (h *CourseSearchHandler) show(bot *tgbotapi.BotAPI, userID, chatID int64, messageID int) error {
+	h.mu.Lock()
+	search := h.searches[userID]
+	filter, page := search.filter, search.page
+	h.mu.Unlock()
+
+	courses, err := h.courses.Search(filter)
+	if err != nil {
+		return fmt.Errorf("ошибка поиска курсов: %w", err)
+	}
+
+	lang := h.settings.Get(userID).Language
+	view, err := courseSearchPaginator(courses, filter, lang).Render(keyboard.SliceSource(courses), page)
+	if err != nil {
+		return err
+	}
+	kb := keyboard.AddCourseSearchRows(view.Keyboard, filter)
+
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, view.Text)
+		reply.ReplyMarkup = kb
+		_, err = bot.Send(reply)
+		return err
+	}
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, view.Text)
+	edit.ReplyMarkup = &kb
+	_, err = bot.Send(edit)
+	return err
+}
+
+// showPicker заменяет кнопки результатов выбором номера страницы
+func (h *CourseSearchHandler) showPicker(bot *tgbotapi.BotAPI, userID, chatID int64, messageID, page int) error {
+	h.mu.Lock()
+	filter := h.searches[userID].filter
+	h.mu.Unlock()
+
+	courses, err := h.courses.Search(filter)
+	if err != nil {
+		return fmt.Errorf("ошибка поиска курсов: %w", err)
+	}
+
+	kb, err := courseSearchPaginator(courses, filter, "").Picker(keyboard.SliceSource(courses), page)
+	if err != nil {
+		return err
+	}
+	_, err = bot.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, kb))
+	return err
+}
+
+// courseSearchPaginator создаёт постраничный список результатов поиска (кнопки srch_p_*, srch_pick_*)
+// В заголовке — условия поиска и число найденных курсов
+func courseSearchPaginator(courses []domain.Course, filter domain.CourseFilter, lang string) keyboard.Paginator[domain.Course] {
+	header := "🔍 Поиск курсов\n"
+	if filter.Query != "" {
+		header += fmt.Sprintf("\nЗапрос: «%s»", filter.Query)
+	}
+	if filter.Category != "" {
+		header += fmt.Sprintf("\nКатегория: %s", filter.Category)
+	}
+	if filter.Level != "" {
+		header += fmt.Sprintf("\nУровень: %s", filter.Level.Title())
+	}
+
+	return keyboard.Paginator[domain.Course]{
+		Namespace: "srch",
+		PerPage:   keyboard.CourseResultsPerPage,
+		Header:    header + fmt.Sprintf("\nНайдено курсов: %d", len(courses)),
+		Empty:     strings.TrimSuffix(header, "\n") + "\n\nНичего не найдено. Измените запрос или сбросьте фильтры.",
+		Item: func(i int, course domain.Course) string {
+			return fmt.Sprintf("%d. %s\n%s · %s\n%s", i+1, course.Title.Get(lang),
+				course.Category, course.Level.Title(), truncateRunes(course.Description.Get(lang), 150))
+		},
+		Button: keyboard.CourseButton(lang),
+	}
+}
@@ -2,19 +2,28 @@ package handler
 
 import (
 	"log"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/middleware"
+	"telegram-bot/internal/service"
 )
 
-// Dispatcher управляет обработчиками команд
+// Dispatcher управляет обработчиками команд и callback-маршрутов
 type Dispatcher struct {
-	handlers map[string]Handler // Карта: команда -> обработчик
+	handlers  map[string]Handler     // Карта: команда -> обработчик
+	callbacks []CallbackHandler      // Обработчики callback-запросов по префиксам
+	inputs    []InputHandler         // Обработчики ответов в многошаговых сценариях
+	polls     []PollAnswerHandler    // Обработчики ответов в опросах
+	access    *service.AccessService // Проверка разрешений для защищённых обработчиков
 }
 
 // NewDispatcher создаёт новый диспетчер
-func NewDispatcher() *Dispatcher {
+func NewDispatcher(access *service.AccessService) *Dispatcher {
 	return &Dispatcher{
 		handlers: make(map[string]Handler),
+		access:   access,
 	}
 }
 
@@ -25,6 +34,28 @@ func (d *Dispatcher) Register(handler Handler) {
 	log.Printf("Зарегистрирован обработчик команды /%s", command)
 }
 
+// RegisterCallback регистрирует обработчик callback-запросов
+func (d *Dispatcher) RegisterCallback(handler CallbackHandler) {
+	d.callbacks = append(d.callbacks, handler)
+	log.Printf("Зарегистрирован обработчик callback-запросов %s*", handler.Prefix())
+}
+
+// RegisterInput регистрирует обработчик ответов в многошаговых сценариях
+func (d *Dispatcher) RegisterInput(handler InputHandler) {
+	d.inputs = append(d.inputs, handler)
+}
+
+// RegisterPollAnswer регистрирует обработчик ответов в опросах
+func (d *Dispatcher) RegisterPollAnswer(handler PollAnswerHandler) {
+	d.polls = append(d.polls, handler)
+}
+
+// Known возвращает true, если для команды зарегистрирован обработчик
+func (d *Dispatcher) Known(command string) bool {
+	_, exists := d.handlers[command]
+	return exists
+}
+
 // HandleCommand обрабатывает команду, направляя её к соответствующему обработчику
 func (d *Dispatcher) HandleCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
 	command := msg.Command()
@@ -36,6 +67,13 @@ func (d *Dispatcher) HandleCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message)
 		return d.handleUnknownCommand(bot, msg)
 	}
 
+	// Проверяем права доступа для защищённых команд
+	if protected, ok := handler.(Protected); ok {
+		if !middleware.RequirePermission(bot, msg, d.access, protected.Permission()) {
+			return nil // Сообщение уже отправлено middleware
+		}
+	}
+
 	// Вызываем обработчик
 	err := handler.Handle(bot, msg)
 	if err != nil {
@@ -46,6 +84,53 @@ func (d *Dispatcher) HandleCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message)
 	return nil
 }
 
+// HandleCallback направляет callback-запрос к обработчику с подходящим префиксом
+// Возвращает false, если подходящего обработчика нет
+func (d *Dispatcher) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) (bool, error) {
+	for _, handler := range d.callbacks {
+		if !strings.HasPrefix(callback.Data, handler.Prefix()) {
+			continue
+		}
+
+		// Проверяем права доступа для защищённых маршрутов
+		if protected, ok := handler.(Protected); ok {
+			if !middleware.RequireCallbackPermission(bot, callback, d.access, protected.Permission()) {
+				return true, nil // На callback уже ответил middleware
+			}
+		}
+
+		return true, handler.HandleCallback(bot, callback)
+	}
+
+	return false, nil
+}
+
+// HandleInput передаёт сообщение обработчикам многошаговых сценариев
+// Возвращает false, если ни один сценарий не ожидает ответа от пользователя
+func (d *Dispatcher) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	for _, handler := range d.inputs {
+		handled, err := handler.HandleInput(bot, msg)
+		if handled {
+			return true, err
+		}
+	}
+
+	return false, nil
+}
+
+// HandlePollAnswer передаёт ответ в опросе обработчикам опросов
+// Возвращает false, если опрос не относится ни к одному обработчику
+func (d *Dispatcher) HandlePollAnswer(bot *tgbotapi.BotAPI, answer *tgbotapi.PollAnswer) (bool, error) {
+	for _, handler := range d.polls {
+		handled, err := handler.HandlePollAnswer(bot, answer)
+		if handled {
+			return true, err
+		}
+	}
+
+	return false, nil
+}
+
 // handleUnknownCommand обрабатывает неизвестные команды
 func (d *Dispatcher) handleUnknownCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
 	chatID := msg.Chat.ID
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// enrollmentDisabledText — ответ, когда запись на курсы выключена флагом функции
+const enrollmentDisabledText = "Запись на курсы пока недоступна."
+
+// EnrollmentHandler обрабатывает команду /mycourses и кнопки записи на курсы (enr_*)
+type EnrollmentHandler struct {
+	enrollments  *service.EnrollmentService
+	certificates *service.CertificateService
+	features     *service.FeatureService
+	settings     *repository.SettingsRepository
+}
+
+// NewEnrollmentHandler создаёт новый обработчик записи на курсы
+func NewEnrollmentHandler(enrollments *service.EnrollmentService, certificates *service.CertificateService, features *service.FeatureService, settings *repository.SettingsRepository) *EnrollmentHandler {
+	return &EnrollmentHandler{
+		enrollments:  enrollments,
+		certificates: certificates,
+		features:     features,
+		settings:     settings,
+	}
+}
+
+// Command возвращает команду
+func (h *EnrollmentHandler) Command() string {
+	return "mycourses"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *EnrollmentHandler) Prefix() string {
+	return "enr_"
+}
+
+// Handle обрабатывает команду /mycourses — показывает курсы пользователя с прогрессом
+func (h *EnrollmentHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	if !h.features.Enabled(domain.FeatureCourseEnrollment, msg.From.ID) {
+		return sendText(bot, msg.Chat.ID, enrollmentDisabledText)
+	}
+
+	lang := h.settings.Get(msg.From.ID).Language
+	courses, err := h.enrollments.MyCourses(msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки курсов пользователя: %w", err)
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, myCoursesText(courses, lang))
+	reply.ReplyMarkup = keyboard.NewMyCoursesKeyboard(courses, lang)
+	_, err = bot.Send(reply)
+	return err
+}
+
+// HandleCallback обрабатывает запись, отписку, экраны прогресса и выдачу сертификата
+func (h *EnrollmentHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	userID := callback.From.ID
+	if !h.features.Enabled(domain.FeatureCourseEnrollment, userID) {
+		_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, enrollmentDisabledText))
+		return err
+	}
+
+	data := strings.TrimPrefix(callback.Data, "enr_")
+	if data == "my" {
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.showMyCourses(bot, callback)
+	}
+
+	action, idStr, _ := strings.Cut(data, "_")
+	courseID, err := strconv.Atoi(idStr)
+	if err != nil {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	callbackText := ""
+	switch action {
+	case "join":
+		_, err = h.enrollments.Enroll(userID, courseID)
+		callbackText = "✅ Вы записаны на курс"
+	case "view":
+		// Экран прогресса показывается ниже
+	case "leave":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID,
+			"Отписаться от курса? Прогресс по урокам будет удалён.")
+		kb := keyboard.NewUnenrollConfirmKeyboard(courseID)
+		edit.ReplyMarkup = &kb
+		_, err := bot.Send(edit)
+		return err
+	case "leaveyes":
+		if err := h.enrollments.Unenroll(userID, courseID); err != nil {
+			return answerEnrollmentError(bot, callback, err)
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "🚪 Вы отписались от курса"))
+		return h.showMyCourses(bot, callback)
+	case "cert":
+		certificate, err := h.certificates.Issue(userID, courseID, certificateName(callback.From))
+		if err != nil {
+			return answerEnrollmentError(bot, callback, err)
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "📜 Отправляем сертификат"))
+		return sendCertificate(bot, callback.Message.Chat.ID, certificate, h.settings.Get(userID).Language, h.certificates)
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+
+	progress, err := h.enrollments.Progress(userID, courseID)
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, callbackText))
+
+	lang := h.settings.Get(userID).Language
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, courseProgressText(progress, lang))
+	kb := keyboard.NewEnrollmentKeyboard(courseID, progress.Total > 0, progress.Enrollment.Completed())
+	edit.ReplyMarkup = &kb
+	_, err = bot.Send(edit)
+	return err
+}
+
+// showMyCourses показывает список курсов пользователя в текущем сообщении
+func (h *EnrollmentHandler) showMyCourses(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	userID := callback.From.ID
+	lang := h.settings.Get(userID).Language
+	courses, err := h.enrollments.MyCourses(userID)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки курсов пользователя: %w", err)
+	}
+
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, myCoursesText(courses, lang))
+	kb := keyboard.NewMyCoursesKeyboard(courses, lang)
+	edit.ReplyMarkup = &kb
+	_, err = bot.Send(edit)
+	return err
+}
+
+// answerEnrollmentError отвечает на callback понятной ошибкой записи на курс или урока
+// Неожиданные ошибки возвращаются вызывающему для логирования
+func answerEnrollmentError(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAlreadyEnrolled),
+		errors.Is(err, service.ErrNotEnrolled),
+		errors.Is(err, service.ErrLessonLocked),
+		errors.Is(err, service.ErrCourseNotCompleted),
+		errors.Is(err, service.ErrSubscriptionRequired),
+		errors.Is(err, repository.ErrCourseNotFound),
+		errors.Is(err, repository.ErrLessonNotFound):
+		_, reqErr := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ "+err.Error()))
+		return reqErr
+	default:
+		bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+		return err
+	}
+}
+
+// myCoursesText формирует экран «Мои курсы»
+func myCoursesText(courses []domain.CourseProgress, lang string) string {
+	text := "🎓 Мои курсы\n\n"
+	if len(courses) == 0 {
+		return text + "Вы пока не записаны ни на один курс.\nВыберите курс в каталоге и нажмите «Записаться»."
+	}
+
+	for _, progress := range courses {
+		text += fmt.Sprintf("%s\n%s\n\n", progress.Course.Title.Get(lang), progressLine(progress))
+	}
+	return text
+}
+
+// courseProgressText формирует экран прогресса по одному курсу
+func courseProgressText(progress domain.CourseProgress, lang string) string {
+	text := fmt.Sprintf("🎓 %s\n\nЗаписаны: %s\n%s",
+		progress.Course.Title.Get(lang),
+		progress.Enrollment.EnrolledAt.Format("2006-01-02"),
+		progressLine(progress),
+	)
+	if progress.Enrollment.Completed() {
+		text += fmt.Sprintf("\n\n🏆 Курс пройден %s", progress.Enrollment.CompletedAt.Format("2006-01-02"))
+	}
+	return text
+}
+
+// progressLine формирует строку прогресса: полоску и число пройденных уроков
+func progressLine(progress domain.CourseProgress) string {
+	if progress.Total == 0 {
+		return "Уроки курса скоро появятся."
+	}
+	return fmt.Sprintf("%s %d%% · %d из %d уроков",
+		progressBar(progress.Percent()), progress.Percent(), progress.Done, progress.Total)
+}
+
+// progressBar рисует полоску прогресса из 10 делений
+func progressBar(percent int) string {
+	filled := percent / 10
+	return strings.Repeat("▰", filled) + strings.Repeat("▱", 10-filled)
+}
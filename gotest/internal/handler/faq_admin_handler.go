@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// faqFormatHelp описывает формат правила для команды /faq
+const faqFormatHelp = "Формат: <code>/faq add</code> или <code>/faq ID</code>, затем с новой строки:\n" +
+	"<code>name: Название</code>\n" +
+	"<code>match: keyword</code>, <code>regex</code> или <code>fuzzy</code>\n" +
+	"<code>priority: 10</code> — правила с большим приоритетом проверяются раньше\n" +
+	"<code>pattern: шаблон</code> — ключевое слово, регулярное выражение или фраза (можно несколько строк)\n" +
+	"<code>ru: ответ</code>, <code>en: answer</code>, <code>zh: 回答</code> — следующие строки продолжают ответ\n" +
+	"<code>button: Текст | https://ссылка</code> или <code>button: Текст | sup_open</code>\n" +
+	"<code>button.en: Text</code> — перевод подписи последней кнопки\n\n" +
+	"Другие команды:\n" +
+	"<code>/faq</code> — список правил\n" +
+	"<code>/faq ID</code> — правило целиком\n" +
+	"<code>/faq ID on | off | delete</code>\n" +
+	"<code>/faq test текст</code> — какие правила сработают"
+
+// faqLanguages — языки ответов в описании правила
+var faqLanguages = []string{"ru", "en", "zh"}
+
+// FAQAdminHandler обрабатывает команду /faq — управление правилами автоответов
+type FAQAdminHandler struct {
+	faq *service.FAQService
+}
+
+// NewFAQAdminHandler создаёт новый обработчик команды /faq
+func NewFAQAdminHandler(faq *service.FAQService) *FAQAdminHandler {
+	return &FAQAdminHandler{faq: faq}
+}
+
+// Command возвращает команду
+func (h *FAQAdminHandler) Command() string {
+	return "faq"
+}
+
+// Permission возвращает разрешение, необходимое для управления автоответами
+func (h *FAQAdminHandler) Permission() domain.Permission {
+	return domain.PermFAQ
+}
+
+// Handle обрабатывает команду /faq [add | test <текст> | ID [on | off | delete | описание правила]]
+func (h *FAQAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+	header, body, _ := strings.Cut(msg.CommandArguments(), "\n")
+	args := strings.Fields(header)
+	if len(args) == 0 {
+		return sendText(bot, chatID, faqListText(h.faq.List()))
+	}
+
+	switch args[0] {
+	case "add":
+		if strings.TrimSpace(body) == "" {
+			return sendHTML(bot, chatID, faqFormatHelp)
+		}
+		rule, err := parseFAQRule(body, domain.FAQRule{Enabled: true})
+		if err == nil {
+			err = h.faq.Create(msg.From.ID, &rule)
+		}
+		return h.sendSaved(bot, chatID, rule, err)
+
+	case "test":
+		text := strings.TrimSpace(strings.TrimPrefix(msg.CommandArguments(), "test"))
+		if text == "" {
+			return sendHTML(bot, chatID, "❌ Укажите текст: <code>/faq test как оформить подписку?</code>")
+		}
+		return sendText(bot, chatID, faqTestText(text, h.faq.Test(text)))
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return sendHTML(bot, chatID, faqFormatHelp)
+	}
+	rule, err := h.faq.Get(id)
+	if errors.Is(err, repository.ErrFAQRuleNotFound) {
+		return sendText(bot, chatID, fmt.Sprintf("❌ Правило #%d не найдено.", id))
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		switch args[1] {
+		case "on", "off":
+			rule, err = h.faq.SetEnabled(msg.From.ID, id, args[1] == "on")
+			return h.sendSaved(bot, chatID, rule, err)
+		case "delete":
+			if err := h.faq.Delete(msg.From.ID, id); err != nil {
+				sendText(bot, chatID, "❌ Не удалось удалить правило.")
+				return err
+			}
+			return sendText(bot, chatID, fmt.Sprintf("🗑 Правило #%d удалено.", id))
+		default:
+			return sendHTML(bot, chatID, faqFormatHelp)
+		}
+	}
+
+	if strings.TrimSpace(body) == "" {
+		return sendText(bot, chatID, faqRuleText(rule))
+	}
+
+	// Описание заменяет правило целиком; включено ли правило, не меняется
+	rule, err = parseFAQRule(body, domain.FAQRule{ID: rule.ID, Enabled: rule.Enabled})
+	if err == nil {
+		err = h.faq.Update(msg.From.ID, rule)
+	}
+	return h.sendSaved(bot, chatID, rule, err)
+}
+
+// sendSaved сообщает результат сохранения правила
+func (h *FAQAdminHandler) sendSaved(bot *tgbotapi.BotAPI, chatID int64, rule domain.FAQRule, err error) error {
+	if errors.Is(err, service.ErrFAQInvalid) {
+		return sendText(bot, chatID, "❌ "+err.Error())
+	}
+	if err != nil {
+		sendText(bot, chatID, "❌ Не удалось сохранить правило.")
+		return err
+	}
+	return sendText(bot, chatID, "✅ Правило сохранено.\n\n"+faqRuleText(rule))
+}
+
+// parseFAQRule разбирает описание правила в формате faqFormatHelp поверх rule
+func parseFAQRule(text string, rule domain.FAQRule) (domain.FAQRule, error) {
+	rule.Answer = domain.LocalizedText{}
+	lang := "" // Язык ответа, который продолжают строки без ключа
+
+	for n, line := range strings.Split(text, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if !found || !isFAQKey(key) {
+			if lang == "" {
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				return domain.FAQRule{}, fmt.Errorf("%w: строка %d: ожидается «ключ: значение»", service.ErrFAQInvalid, n+1)
+			}
+			rule.Answer[lang] += "\n" + line
+			continue
+		}
+
+		lang = ""
+		var err error
+		switch {
+		case key == "name":
+			rule.Name = value
+		case key == "match":
+			rule.Match = domain.FAQMatch(strings.ToLower(value))
+		case key == "priority":
+			rule.Priority, err = strconv.Atoi(value)
+		case key == "pattern":
+			rule.Patterns = append(rule.Patterns, value)
+		case key == "button":
+			label, target, _ := strings.Cut(value, "|")
+			button := domain.FAQButton{Text: domain.LocalizedText{domain.DefaultLanguage: strings.TrimSpace(label)}}
+			target = strings.TrimSpace(target)
+			if strings.Contains(target, "://") {
+				button.URL = target
+			} else {
+				button.Data = target
+			}
+			rule.Buttons = append(rule.Buttons, button)
+		case strings.HasPrefix(key, "button."):
+			if len(rule.Buttons) == 0 {
+				err = errors.New("перевод без кнопки")
+				break
+			}
+			rule.Buttons[len(rule.Buttons)-1].Text[strings.TrimPrefix(key, "button.")] = value
+		default:
+			lang = key
+			rule.Answer[lang] = value
+		}
+		if err != nil {
+			return domain.FAQRule{}, fmt.Errorf("%w: строка %d: %s", service.ErrFAQInvalid, n+1, line)
+		}
+	}
+
+	for lang, answer := range rule.Answer {
+		rule.Answer[lang] = strings.TrimSpace(answer)
+	}
+	return rule, nil
+}
+
+// isFAQKey возвращает true, если key — ключ описания правила
+func isFAQKey(key string) bool {
+	switch key {
+	case "name", "match", "priority", "pattern", "button":
+		return true
+	}
+	for _, lang := range faqLanguages {
+		if key == lang || key == "button."+lang {
+			return true
+		}
+	}
+	return false
+}
+
+// faqListText формирует список правил автоответов
+func faqListText(rules []domain.FAQRule) string {
+	if len(rules) == 0 {
+		return "🤖 Правил автоответов нет.\n\nДобавить: /faq add"
+	}
+
+	text := "🤖 Правила автоответов (в порядке проверки):\n"
+	for _, rule := range rules {
+		status := "✅"
+		if !rule.Enabled {
+			status = "⏸"
+		}
+		text += fmt.Sprintf("\n%s #%d %s · %s · приоритет %d\n%s\n", status, rule.ID, rule.Name, rule.Match, rule.Priority,
+			truncateRunes(strings.Join(rule.Patterns, ", "), 80))
+	}
+	return truncateRunes(text+"\nПодробнее: /faq ID · справка: /faq add", 4000)
+}
+
+// faqRuleText формирует правило в формате faqFormatHelp, чтобы его можно было скопировать и изменить
+func faqRuleText(rule domain.FAQRule) string {
+	status := "включено"
+	if !rule.Enabled {
+		status = "выключено"
+	}
+
+	text := fmt.Sprintf("🤖 Правило #%d (%s)\n\n/faq %d\nname: %s\nmatch: %s\npriority: %d\n",
+		rule.ID, status, rule.ID, rule.Name, rule.Match, rule.Priority)
+	for _, pattern := range rule.Patterns {
+		text += "pattern: " + pattern + "\n"
+	}
+	for _, lang := range faqLanguages {
+		if answer := rule.Answer[lang]; answer != "" {
+			text += lang + ": " + answer + "\n"
+		}
+	}
+	for _, button := range rule.Buttons {
+		target := button.Data
+		if button.URL != "" {
+			target = button.URL
+		}
+		text += fmt.Sprintf("button: %s | %s\n", button.Text[domain.DefaultLanguage], target)
+		for _, lang := range faqLanguages {
+			if label := button.Text[lang]; label != "" && lang != domain.DefaultLanguage {
+				text += fmt.Sprintf("button.%s: %s\n", lang, label)
+			}
+		}
+	}
+	return truncateRunes(text, 4000)
+}
+
+// faqTestText формирует результат проверки текста правилами автоответов
+func faqTestText(text string, results []service.FAQMatchResult) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("🧪 «%s»\n\nНи одно правило не подходит — сработает ответ по умолчанию.", truncateRunes(text, 200))
+	}
+
+	answered := false
+	out := fmt.Sprintf("🧪 «%s»\n\nПодходящие правила:\n", truncateRunes(text, 200))
+	for _, result := range results {
+		mark := "  "
+		switch {
+		case !result.Rule.Enabled:
+			mark = "⏸"
+		case !answered:
+			mark = "👉"
+			answered = true
+		}
+		out += fmt.Sprintf("\n%s #%d %s · приоритет %d · %s «%s» (%.0f%%)", mark, result.Rule.ID, result.Rule.Name,
+			result.Rule.Priority, result.Rule.Match, result.Pattern, result.Score*100)
+	}
+
+	if !answered {
+		return out + "\n\nВсе подходящие правила выключены — сработает ответ по умолчанию."
+	}
+	return out + "\n\n👉 — правило, которое ответит пользователю."
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// FeatureHandler обрабатывает команду /flags — просмотр и изменение флагов функций
+type FeatureHandler struct {
+	features *service.FeatureService
+	users    *repository.UserRepository
+}
+
+// NewFeatureHandler создаёт новый обработчик команды /flags
+func NewFeatureHandler(features *service.FeatureService, users *repository.UserRepository) *FeatureHandler {
+	return &FeatureHandler{features: features, users: users}
+}
+
+// Command возвращает команду
+func (h *FeatureHandler) Command() string {
+	return "flags"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *FeatureHandler) Permission() domain.Permission {
+	return domain.PermFeatures
+}
+
+// Handle обрабатывает команду /flags [имя] [действие] [значение]
+func (h *FeatureHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return sendText(bot, msg.Chat.ID, featuresText(h.features.List()))
+	}
+
+	name := args[0]
+	if len(args) == 1 {
+		flag, exists := h.features.Get(name)
+		if !exists {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Флаг «%s» не найден.", name))
+		}
+		return sendText(bot, msg.Chat.ID, featureText(flag))
+	}
+
+	actorID := msg.From.ID
+	value := ""
+	if len(args) > 2 {
+		value = args[2]
+	}
+
+	var err error
+	switch args[1] {
+	case "on", "off":
+		err = h.features.SetEnabled(actorID, name, args[1] == "on")
+	case "percent":
+		percent, convErr := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if convErr != nil {
+			return h.sendUsage(bot, msg.Chat.ID)
+		}
+		err = h.features.SetPercent(actorID, name, percent)
+	case "allow", "disallow":
+		userID, ok := resolveUserID(h.users, value)
+		if !ok {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Пользователь «%s» не найден.", value))
+		}
+		if args[1] == "allow" {
+			err = h.features.Allow(actorID, name, userID)
+		} else {
+			err = h.features.Disallow(actorID, name, userID)
+		}
+	case "locales":
+		var locales []string
+		if value != "" && value != "all" {
+			locales = strings.Split(value, ",")
+		}
+		err = h.features.SetLocales(actorID, name, locales)
+	case "delete":
+		err = h.features.Delete(actorID, name)
+		if err == nil {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("🗑 Флаг «%s» удалён.", name))
+		}
+	default:
+		return h.sendUsage(bot, msg.Chat.ID)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrInvalidFeatureName),
+		errors.Is(err, service.ErrInvalidPercent),
+		errors.Is(err, service.ErrFeatureNotFound):
+		return sendText(bot, msg.Chat.ID, "❌ "+err.Error())
+	case err != nil:
+		sendText(bot, msg.Chat.ID, "❌ Не удалось сохранить флаг.")
+		return err
+	}
+
+	flag, _ := h.features.Get(name)
+	return sendText(bot, msg.Chat.ID, "✅ Флаг обновлён.\n\n"+featureText(flag))
+}
+
+// sendUsage отправляет подсказку по команде /flags
+func (h *FeatureHandler) sendUsage(bot *tgbotapi.BotAPI, chatID int64) error {
+	return sendText(bot, chatID,
+		"❌ Использование:\n"+
+			"/flags — список флагов\n"+
+			"/flags <имя> — подробности\n"+
+			"/flags <имя> on | off — общий выключатель\n"+
+			"/flags <имя> percent <0-100> — доля пользователей\n"+
+			"/flags <имя> allow | disallow <ID или @username> — список пользователей\n"+
+			"/flags <имя> locales <ru,en | all> — языки интерфейса\n"+
+			"/flags <имя> delete — удалить флаг")
+}
+
+// featuresText формирует краткий список флагов
+func featuresText(flags []domain.FeatureFlag) string {
+	if len(flags) == 0 {
+		return "🚩 Флагов пока нет.\n\nСоздать: /flags <имя> on"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🚩 Флаги функций:\n")
+	for _, flag := range flags {
+		icon := "⚪"
+		if flag.Enabled {
+			icon = "🟢"
+		}
+		sb.WriteString(fmt.Sprintf("\n%s %s — %s", icon, flag.Name, featureRollout(flag)))
+	}
+	sb.WriteString("\n\nПодробнее: /flags <имя>")
+	return sb.String()
+}
+
+// featureText формирует подробное описание флага
+func featureText(flag domain.FeatureFlag) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🚩 Флаг %s\n", flag.Name))
+	if description, ok := domain.KnownFeatures[flag.Name]; ok {
+		sb.WriteString(description + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("\nВключён: %s", onOff(flag.Enabled)))
+	sb.WriteString(fmt.Sprintf("\nРаскатка: %s", featureRollout(flag)))
+
+	if len(flag.Users) > 0 {
+		ids := make([]string, len(flag.Users))
+		for i, id := range flag.Users {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		sb.WriteString("\nВсегда включён для: " + strings.Join(ids, ", "))
+	}
+	if !flag.UpdatedAt.IsZero() {
+		sb.WriteString(fmt.Sprintf("\nИзменён: %s (администратор %d)", flag.UpdatedAt.Format("2006-01-02 15:04"), flag.UpdatedBy))
+	}
+	return sb.String()
+}
+
+// featureRollout описывает, на кого раскатан флаг
+func featureRollout(flag domain.FeatureFlag) string {
+	rollout := fmt.Sprintf("%d%%", flag.Percent)
+	if len(flag.Locales) > 0 {
+		rollout += ", языки: " + strings.Join(flag.Locales, ", ")
+	}
+	if len(flag.Users) > 0 {
+		rollout += fmt.Sprintf(", +%d в списке", len(flag.Users))
+	}
+	return rollout
+}
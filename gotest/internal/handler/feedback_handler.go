@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// feedbackResult — заполненная форма отзыва
+type feedbackResult struct {
+	Rating  int    `form:"rating"`
+	Comment string `form:"comment"`
+	Phone   string `form:"phone"`
+}
+
+// FeedbackHandler обрабатывает команду /feedback — форму отзыва о боте
+// Отзыв получают сотрудники с доступом к пользователям
+type FeedbackHandler struct {
+	form     *Form[feedbackResult]
+	fsm      *FSM
+	access   *service.AccessService
+	settings *repository.SettingsRepository
+}
+
+// NewFeedbackHandler создаёт новый обработчик отзывов и регистрирует форму в fsm
+func NewFeedbackHandler(fsm *FSM, access *service.AccessService, settings *repository.SettingsRepository) *FeedbackHandler {
+	h := &FeedbackHandler{
+		fsm:      fsm,
+		access:   access,
+		settings: settings,
+	}
+
+	ratings := make([]Option, 0, 5)
+	for stars := 5; stars >= 1; stars-- {
+		label := strings.Repeat("⭐", stars)
+		ratings = append(ratings, Option{Value: fmt.Sprint(stars), Label: domain.LocalizedText{"ru": label}})
+	}
+
+	h.form = &Form[feedbackResult]{
+		Name:  "feedback",
+		Title: domain.LocalizedText{"ru": "📝 Ваш отзыв", "en": "📝 Your feedback", "zh": "📝 您的反馈"},
+		Fields: []Field{
+			{
+				Name:    "rating",
+				Type:    FieldChoice,
+				Label:   domain.LocalizedText{"ru": "Оценка", "en": "Rating", "zh": "评分"},
+				Prompt:  domain.LocalizedText{"ru": "Оцените бота:", "en": "How would you rate the bot?", "zh": "请为机器人评分："},
+				Options: ratings,
+			},
+			{
+				Name:       "comment",
+				Type:       FieldText,
+				Label:      domain.LocalizedText{"ru": "Комментарий", "en": "Comment", "zh": "评论"},
+				Prompt:     domain.LocalizedText{"ru": "Что понравилось и что стоит улучшить?", "en": "What did you like and what could be better?", "zh": "您喜欢什么？哪些地方需要改进？"},
+				Validators: []Validator{MinLength(3), MaxLength(1000)},
+			},
+			{
+				Name:     "phone",
+				Type:     FieldPhone,
+				Label:    domain.LocalizedText{"ru": "Телефон", "en": "Phone", "zh": "电话"},
+				Prompt:   domain.LocalizedText{"ru": "Оставьте телефон, если хотите, чтобы с вами связались.", "en": "Leave your phone number if you'd like us to contact you.", "zh": "如果希望我们联系您，请留下电话号码。"},
+				Optional: true,
+			},
+		},
+		OnSubmit: h.submit,
+	}
+	h.form.Register(fsm)
+
+	return h
+}
+
+// Command возвращает команду
+func (h *FeedbackHandler) Command() string {
+	return "feedback"
+}
+
+// Handle обрабатывает команду /feedback — начинает заполнение формы отзыва
+func (h *FeedbackHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	return h.form.Start(h.fsm, bot, msg.Chat.ID, msg.From.ID, h.settings.Get(msg.From.ID).Language)
+}
+
+// submit пересылает отзыв сотрудникам с доступом к пользователям
+func (h *FeedbackHandler) submit(bot *tgbotapi.BotAPI, chatID, userID int64, result feedbackResult) error {
+	text := fmt.Sprintf("📝 Отзыв от пользователя %d\n\nОценка: %s\n%s",
+		userID, strings.Repeat("⭐", result.Rating), result.Comment)
+	if result.Phone != "" {
+		text += "\n\nТелефон: " + result.Phone
+	}
+
+	for staffID, role := range h.access.Staff() {
+		if !role.Has(domain.PermUsersView) {
+			continue
+		}
+		if err := sendText(bot, staffID, text); err != nil {
+			log.Printf("Ошибка отправки отзыва сотруднику %d: %v", staffID, err)
+		}
+	}
+
+	log.Printf("Получен отзыв от пользователя %d (оценка %d)", userID, result.Rating)
+	return nil
+}
@@ -0,0 +1,461 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+)
+
+// FieldType — тип поля формы
+type FieldType string
+
+const (
+	FieldText   FieldType = "text"   // Произвольный текст
+	FieldNumber FieldType = "number" // Число (целое или дробное)
+	FieldPhone  FieldType = "phone"  // Телефон: кнопка «Отправить номер» (request_contact) или ввод вручную
+	FieldChoice FieldType = "choice" // Выбор варианта инлайн-кнопкой
+	FieldDate   FieldType = "date"   // Дата в формате ДД.ММ.ГГГГ
+	FieldPhoto  FieldType = "photo"  // Фотография (сохраняется file_id)
+)
+
+const (
+	// FormDateLayout — формат, в котором пользователь вводит даты
+	FormDateLayout = "02.01.2006"
+	// formValueDateLayout — формат хранения дат в значениях формы
+	formValueDateLayout = "2006-01-02"
+	// formConfirmStep — шаг подтверждения сводки после последнего поля
+	formConfirmStep = "_confirm"
+)
+
+// Тексты форм на языках бота
+var (
+	formSkipButton    = domain.LocalizedText{"ru": "⏭ Пропустить", "en": "⏭ Skip", "zh": "⏭ 跳过"}
+	formPhoneButton   = domain.LocalizedText{"ru": "📱 Отправить номер", "en": "📱 Share phone number", "zh": "📱 发送电话号码"}
+	formPhoneReceived = domain.LocalizedText{"ru": "✅ Номер получен", "en": "✅ Phone number received", "zh": "✅ 已收到电话号码"}
+	formDateHint      = domain.LocalizedText{"ru": "Формат: ДД.ММ.ГГГГ", "en": "Format: DD.MM.YYYY", "zh": "格式：DD.MM.YYYY"}
+	formCancelHint    = domain.LocalizedText{"ru": "Отменить заполнение: /cancel", "en": "To cancel: /cancel", "zh": "取消填写：/cancel"}
+	formConfirmText   = domain.LocalizedText{"ru": "Всё верно?", "en": "Is everything correct?", "zh": "信息正确吗？"}
+	formSubmittedText = domain.LocalizedText{"ru": "✅ Отправлено", "en": "✅ Submitted", "zh": "✅ 已提交"}
+	formDiscardedText = domain.LocalizedText{"ru": "❌ Форма отменена", "en": "❌ Form discarded", "zh": "❌ 表单已取消"}
+
+	errFormText   = domain.LocalizedText{"ru": "Отправьте ответ текстом.", "en": "Please reply with text.", "zh": "请以文字回复。"}
+	errFormNumber = domain.LocalizedText{"ru": "Отправьте число, например 42 или 3,5.", "en": "Please send a number, e.g. 42 or 3.5.", "zh": "请发送数字，例如 42 或 3.5。"}
+	errFormPhone  = domain.LocalizedText{"ru": "Нажмите «📱 Отправить номер» или введите номер в формате +79991234567.", "en": "Tap “📱 Share phone number” or type a number like +14155552671.", "zh": "请点击“📱 发送电话号码”或输入号码，例如 +8613800138000。"}
+	errFormOwn    = domain.LocalizedText{"ru": "Отправьте свой номер телефона, а не чужой контакт.", "en": "Please share your own phone number, not someone else's contact.", "zh": "请发送您自己的电话号码，而不是他人的联系人。"}
+	errFormChoice = domain.LocalizedText{"ru": "Выберите вариант кнопкой под вопросом.", "en": "Please pick an option using the buttons.", "zh": "请使用按钮选择一个选项。"}
+	errFormDate   = domain.LocalizedText{"ru": "Отправьте дату в формате ДД.ММ.ГГГГ, например 31.12.2026.", "en": "Please send a date as DD.MM.YYYY, e.g. 31.12.2026.", "zh": "请按 DD.MM.YYYY 格式发送日期，例如 31.12.2026。"}
+	errFormPhoto  = domain.LocalizedText{"ru": "Отправьте фотографию.", "en": "Please send a photo.", "zh": "请发送照片。"}
+)
+
+// Option — вариант ответа для поля FieldChoice
+type Option struct {
+	Value string               // Значение в результате формы
+	Label domain.LocalizedText // Текст кнопки
+}
+
+// Field — поле формы
+type Field struct {
+	Name       string               // Имя поля; совпадает с тегом form:"<имя>" в результате
+	Type       FieldType            // Тип поля
+	Label      domain.LocalizedText // Название поля в сводке перед подтверждением
+	Prompt     domain.LocalizedText // Вопрос пользователю
+	Optional   bool                 // Поле можно пропустить кнопкой «Пропустить»
+	Options    []Option             // Варианты ответа для FieldChoice
+	Validators []Validator          // Дополнительные проверки значения
+}
+
+// Form — декларативная форма поверх диалогов FSM
+// Поля заполняются по очереди, затем пользователь подтверждает сводку и OnSubmit получает результат типа T
+//
+// T — структура; её поля связываются с полями формы тегом form:"<имя поля>".
+// Поддерживаются string, целые и дробные числа и time.Time (для FieldDate)
+type Form[T any] struct {
+	Name    string               // Имя сценария диалога
+	Title   domain.LocalizedText // Заголовок сводки
+	Fields  []Field
+	Timeout time.Duration // Время ожидания ответа на каждый вопрос (0 — по умолчанию для бота)
+	// OnSubmit получает заполненную и подтверждённую форму
+	OnSubmit func(bot *tgbotapi.BotAPI, chatID, userID int64, result T) error
+}
+
+// formData — данные диалога заполнения формы
+type formData struct {
+	UserID int64             `json:"user_id"`
+	Lang   string            `json:"lang"`
+	Values map[string]string `json:"values"` // Значения полей в нормализованном виде
+}
+
+// Register регистрирует форму как сценарий диалога
+func (f *Form[T]) Register(fsm *FSM) {
+	steps := make(map[string]Step[formData], len(f.Fields)+1)
+	for i := range f.Fields {
+		steps[f.Fields[i].Name] = Step[formData]{
+			Enter:    f.enterField(i),
+			Handle:   f.handleField(i),
+			Callback: f.callbackField(i),
+		}
+	}
+	steps[formConfirmStep] = Step[formData]{
+		Enter:    f.enterConfirm,
+		Callback: f.callbackConfirm,
+	}
+
+	fsm.Register(&Scene[formData]{
+		Name:    f.Name,
+		First:   f.Fields[0].Name,
+		Steps:   steps,
+		Timeout: f.Timeout,
+		OnCancel: func(bot *tgbotapi.BotAPI, chatID int64, data formData) error {
+			reply := tgbotapi.NewMessage(chatID, formDiscardedText.Get(data.Lang))
+			reply.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+			_, err := bot.Send(reply)
+			return err
+		},
+	})
+}
+
+// Start начинает заполнение формы пользователем userID на языке lang
+func (f *Form[T]) Start(fsm *FSM, bot *tgbotapi.BotAPI, chatID, userID int64, lang string) error {
+	return f.StartWith(fsm, bot, chatID, userID, lang, nil)
+}
+
+// StartWith начинает заполнение формы с заранее известными значениями values
+// Значения без поля формы не показываются в сводке, но попадают в результат — например, ID изменяемой записи
+func (f *Form[T]) StartWith(fsm *FSM, bot *tgbotapi.BotAPI, chatID, userID int64, lang string, values map[string]string) error {
+	if values == nil {
+		values = make(map[string]string)
+	}
+	return fsm.Start(bot, chatID, userID, f.Name, formData{
+		UserID: userID,
+		Lang:   lang,
+		Values: values,
+	})
+}
+
+// next возвращает переход к полю после поля с индексом i или к подтверждению
+func (f *Form[T]) next(i int) Transition {
+	if i+1 < len(f.Fields) {
+		return Next(f.Fields[i+1].Name)
+	}
+	return Next(formConfirmStep)
+}
+
+// enterField задаёт вопрос поля с индексом i
+func (f *Form[T]) enterField(i int) func(*tgbotapi.BotAPI, int64, formData) error {
+	field := f.Fields[i]
+	return func(bot *tgbotapi.BotAPI, chatID int64, data formData) error {
+		text := field.Prompt.Get(data.Lang)
+		if field.Type == FieldDate {
+			text += "\n\n" + formDateHint.Get(data.Lang)
+		}
+		if i == 0 {
+			text += "\n\n" + formCancelHint.Get(data.Lang)
+		}
+		reply := tgbotapi.NewMessage(chatID, text)
+
+		switch field.Type {
+		case FieldPhone:
+			// Кнопка запроса контакта есть только в обычной клавиатуре, поэтому «Пропустить» — тоже в ней
+			rows := [][]tgbotapi.KeyboardButton{
+				tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButtonContact(formPhoneButton.Get(data.Lang))),
+			}
+			if field.Optional {
+				rows = append(rows, tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(formSkipButton.Get(data.Lang))))
+			}
+			kb := tgbotapi.NewReplyKeyboard(rows...)
+			kb.OneTimeKeyboard = true
+			reply.ReplyMarkup = kb
+
+		case FieldChoice:
+			builder := keyboard.NewBuilder().AutoWrap(keyboard.DefaultRowWidth)
+			for index, option := range field.Options {
+				builder.Button(option.Label.Get(data.Lang), fmt.Sprintf("dlg_c_%d", index))
+			}
+			if field.Optional {
+				builder.Row().Button(formSkipButton.Get(data.Lang), "dlg_skip")
+			}
+			kb, err := builder.Build()
+			if err != nil {
+				return fmt.Errorf("ошибка клавиатуры поля %s формы %s: %w", field.Name, f.Name, err)
+			}
+			reply.ReplyMarkup = kb
+
+		default:
+			if field.Optional {
+				reply.ReplyMarkup = keyboard.NewBuilder().Button(formSkipButton.Get(data.Lang), "dlg_skip").MustBuild()
+			}
+		}
+
+		_, err := bot.Send(reply)
+		return err
+	}
+}
+
+// handleField принимает ответ на поле с индексом i сообщением
+func (f *Form[T]) handleField(i int) func(*tgbotapi.BotAPI, *tgbotapi.Message, *formData) (Transition, error) {
+	field := f.Fields[i]
+	return func(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, data *formData) (Transition, error) {
+		if field.Type == FieldPhone && field.Optional && msg.Text == formSkipButton.Get(data.Lang) {
+			delete(data.Values, field.Name)
+			return f.next(i), nil
+		}
+
+		value, problem := parseFieldValue(field, msg)
+		if problem == nil {
+			problem = validateField(field, value)
+		}
+		if problem != nil {
+			return Stay(), sendText(bot, msg.Chat.ID, "❌ "+problem.Get(data.Lang))
+		}
+
+		data.Values[field.Name] = value
+		if field.Type == FieldPhone {
+			// Убираем клавиатуру с кнопкой «Отправить номер»
+			return f.next(i), sendFormMessage(bot, msg.Chat.ID, formPhoneReceived.Get(data.Lang), true)
+		}
+		return f.next(i), nil
+	}
+}
+
+// callbackField принимает выбор варианта (dlg_c_<номер>) или пропуск поля (dlg_skip)
+func (f *Form[T]) callbackField(i int) func(*tgbotapi.BotAPI, *tgbotapi.CallbackQuery, string, *formData) (Transition, error) {
+	field := f.Fields[i]
+	return func(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, value string, data *formData) (Transition, error) {
+		var answer string
+		switch {
+		case value == "skip" && field.Optional:
+			delete(data.Values, field.Name)
+			answer = formSkipButton.Get(data.Lang)
+
+		case strings.HasPrefix(value, "c_") && field.Type == FieldChoice:
+			index, err := strconv.Atoi(strings.TrimPrefix(value, "c_"))
+			if err != nil || index < 0 || index >= len(field.Options) {
+				return Stay(), nil
+			}
+			option := field.Options[index]
+			if problem := validateField(field, option.Value); problem != nil {
+				return Stay(), sendText(bot, callback.Message.Chat.ID, "❌ "+problem.Get(data.Lang))
+			}
+			data.Values[field.Name] = option.Value
+			answer = option.Label.Get(data.Lang)
+
+		default:
+			// Кнопка от другого вопроса — ждём ответа на текущий
+			return Stay(), nil
+		}
+
+		// Убираем кнопки и показываем выбранный ответ под вопросом
+		edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID,
+			callback.Message.Text+"\n\n➡️ "+answer)
+		bot.Send(edit)
+		return f.next(i), nil
+	}
+}
+
+// enterConfirm показывает сводку ответов и спрашивает подтверждение
+func (f *Form[T]) enterConfirm(bot *tgbotapi.BotAPI, chatID int64, data formData) error {
+	reply := tgbotapi.NewMessage(chatID, f.summary(data)+"\n\n"+formConfirmText.Get(data.Lang))
+	reply.ReplyMarkup = keyboard.NewConfirmKeyboard("dlg_confirm")
+	_, err := bot.Send(reply)
+	return err
+}
+
+// callbackConfirm отправляет форму (dlg_confirm_yes) или отменяет её (dlg_confirm_no)
+func (f *Form[T]) callbackConfirm(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, value string, data *formData) (Transition, error) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	switch value {
+	case "confirm_yes":
+		result, err := decodeForm[T](data.Values)
+		if err != nil {
+			return Stay(), fmt.Errorf("ошибка разбора формы %s: %w", f.Name, err)
+		}
+		if err := f.OnSubmit(bot, chatID, data.UserID, result); err != nil {
+			return Stay(), err
+		}
+		bot.Send(tgbotapi.NewEditMessageText(chatID, messageID, f.summary(*data)+"\n\n"+formSubmittedText.Get(data.Lang)))
+		return Finish(), nil
+
+	case "confirm_no":
+		bot.Send(tgbotapi.NewEditMessageText(chatID, messageID, f.summary(*data)+"\n\n"+formDiscardedText.Get(data.Lang)))
+		return Finish(), nil
+
+	default:
+		return Stay(), nil
+	}
+}
+
+// summary формирует сводку ответов для подтверждения
+func (f *Form[T]) summary(data formData) string {
+	text := f.Title.Get(data.Lang) + "\n"
+	for _, field := range f.Fields {
+		text += fmt.Sprintf("\n%s: %s", field.Label.Get(data.Lang), displayFieldValue(field, data.Values[field.Name], data.Lang))
+	}
+	return text
+}
+
+// displayFieldValue возвращает значение поля в виде для пользователя
+func displayFieldValue(field Field, value, lang string) string {
+	if value == "" {
+		return "—"
+	}
+
+	switch field.Type {
+	case FieldChoice:
+		for _, option := range field.Options {
+			if option.Value == value {
+				return option.Label.Get(lang)
+			}
+		}
+	case FieldDate:
+		if date, err := time.Parse(formValueDateLayout, value); err == nil {
+			return date.Format(FormDateLayout)
+		}
+	case FieldPhoto:
+		return "📷"
+	}
+	return value
+}
+
+// sendFormMessage отправляет служебное сообщение формы
+// removeKeyboard убирает обычную клавиатуру (например, с кнопкой «Отправить номер»)
+func sendFormMessage(bot *tgbotapi.BotAPI, chatID int64, text string, removeKeyboard bool) error {
+	reply := tgbotapi.NewMessage(chatID, text)
+	if removeKeyboard {
+		reply.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	}
+	_, err := bot.Send(reply)
+	return err
+}
+
+// parseFieldValue разбирает ответ на поле и приводит его к нормализованному виду
+// Второе значение — текст ошибки, если ответ не подходит к типу поля
+func parseFieldValue(field Field, msg *tgbotapi.Message) (string, domain.LocalizedText) {
+	text := strings.TrimSpace(msg.Text)
+
+	switch field.Type {
+	case FieldNumber:
+		number, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+		if err != nil {
+			return "", errFormNumber
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+
+	case FieldPhone:
+		if msg.Contact != nil {
+			if msg.Contact.UserID != 0 && msg.Contact.UserID != msg.From.ID {
+				return "", errFormOwn
+			}
+			text = msg.Contact.PhoneNumber
+		}
+		phone, ok := normalizePhone(text)
+		if !ok {
+			return "", errFormPhone
+		}
+		return phone, nil
+
+	case FieldChoice:
+		return "", errFormChoice
+
+	case FieldDate:
+		date, err := time.Parse(FormDateLayout, text)
+		if err != nil {
+			return "", errFormDate
+		}
+		return date.Format(formValueDateLayout), nil
+
+	case FieldPhoto:
+		if len(msg.Photo) == 0 {
+			return "", errFormPhoto
+		}
+		// Telegram присылает несколько размеров; последний — самый большой
+		return msg.Photo[len(msg.Photo)-1].FileID, nil
+
+	default:
+		if text == "" {
+			return "", errFormText
+		}
+		return text, nil
+	}
+}
+
+// normalizePhone приводит номер телефона к виду +<цифры>
+// Скобки, пробелы и дефисы отбрасываются; номер должен содержать от 10 до 15 цифр
+func normalizePhone(text string) (string, bool) {
+	var digits strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+	if digits.Len() < 10 || digits.Len() > 15 {
+		return "", false
+	}
+	return "+" + digits.String(), true
+}
+
+// validateField проверяет значение всеми проверками поля
+func validateField(field Field, value string) domain.LocalizedText {
+	for _, validate := range field.Validators {
+		if problem := validate(value); problem != nil {
+			return problem
+		}
+	}
+	return nil
+}
+
+// decodeForm заполняет результат типа T значениями формы по тегам form:"<имя поля>"
+func decodeForm[T any](values map[string]string) (T, error) {
+	var result T
+	target := reflect.ValueOf(&result).Elem()
+	if target.Kind() != reflect.Struct {
+		return result, fmt.Errorf("результат формы должен быть структурой, а не %s", target.Type())
+	}
+
+	for i := 0; i < target.NumField(); i++ {
+		name := target.Type().Field(i).Tag.Get("form")
+		value, exists := values[name]
+		if name == "" || !exists {
+			continue
+		}
+
+		field := target.Field(i)
+		switch {
+		case field.Type() == reflect.TypeOf(time.Time{}):
+			date, err := time.Parse(formValueDateLayout, value)
+			if err != nil {
+				return result, fmt.Errorf("поле %s: %w", name, err)
+			}
+			field.Set(reflect.ValueOf(date))
+		case field.Kind() == reflect.String:
+			field.SetString(value)
+		case field.CanInt():
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return result, fmt.Errorf("поле %s: %w", name, err)
+			}
+			field.SetInt(number)
+		case field.CanFloat():
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return result, fmt.Errorf("поле %s: %w", name, err)
+			}
+			field.SetFloat(number)
+		default:
+			return result, fmt.Errorf("поле %s: неподдерживаемый тип %s", name, field.Type())
+		}
+	}
+	return result, nil
+}
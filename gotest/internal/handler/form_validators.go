@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"telegram-bot/internal/domain"
+)
+
+// Validator проверяет значение поля формы в нормализованном виде
+// (числа — с точкой, даты — ГГГГ-ММ-ДД, телефоны — +<цифры>)
+// Возвращает текст ошибки на языках бота или nil, если значение подходит
+type Validator func(value string) domain.LocalizedText
+
+// MinLength требует не меньше n символов
+func MinLength(n int) Validator {
+	return func(value string) domain.LocalizedText {
+		if len([]rune(value)) >= n {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": fmt.Sprintf("Слишком коротко: нужно не меньше %d символов.", n),
+			"en": fmt.Sprintf("Too short: at least %d characters required.", n),
+			"zh": fmt.Sprintf("太短：至少需要 %d 个字符。", n),
+		}
+	}
+}
+
+// MaxLength требует не больше n символов
+func MaxLength(n int) Validator {
+	return func(value string) domain.LocalizedText {
+		if len([]rune(value)) <= n {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": fmt.Sprintf("Слишком длинно: не больше %d символов.", n),
+			"en": fmt.Sprintf("Too long: at most %d characters allowed.", n),
+			"zh": fmt.Sprintf("太长：最多 %d 个字符。", n),
+		}
+	}
+}
+
+// Integer требует целое число
+func Integer() Validator {
+	return func(value string) domain.LocalizedText {
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": "Нужно целое число.",
+			"en": "A whole number is required.",
+			"zh": "需要整数。",
+		}
+	}
+}
+
+// Min требует число не меньше min
+func Min(min float64) Validator {
+	return func(value string) domain.LocalizedText {
+		if number, err := strconv.ParseFloat(value, 64); err != nil || number >= min {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": fmt.Sprintf("Число должно быть не меньше %g.", min),
+			"en": fmt.Sprintf("The number must be at least %g.", min),
+			"zh": fmt.Sprintf("数字不能小于 %g。", min),
+		}
+	}
+}
+
+// Max требует число не больше max
+func Max(max float64) Validator {
+	return func(value string) domain.LocalizedText {
+		if number, err := strconv.ParseFloat(value, 64); err != nil || number <= max {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": fmt.Sprintf("Число должно быть не больше %g.", max),
+			"en": fmt.Sprintf("The number must be at most %g.", max),
+			"zh": fmt.Sprintf("数字不能大于 %g。", max),
+		}
+	}
+}
+
+// Matches требует совпадения с регулярным выражением; message — текст ошибки
+func Matches(pattern *regexp.Regexp, message domain.LocalizedText) Validator {
+	return func(value string) domain.LocalizedText {
+		if pattern.MatchString(value) {
+			return nil
+		}
+		return message
+	}
+}
+
+// NotInPast требует дату не раньше сегодняшней
+func NotInPast() Validator {
+	return func(value string) domain.LocalizedText {
+		date, err := time.Parse(formValueDateLayout, value)
+		if err != nil || !date.Before(time.Now().Truncate(24*time.Hour)) {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": "Дата уже прошла. Укажите сегодняшнюю или будущую дату.",
+			"en": "This date has passed. Please enter today or a future date.",
+			"zh": "该日期已过。请输入今天或将来的日期。",
+		}
+	}
+}
+
+// NotInFuture требует дату не позже сегодняшней (например, дату рождения)
+func NotInFuture() Validator {
+	return func(value string) domain.LocalizedText {
+		date, err := time.Parse(formValueDateLayout, value)
+		if err != nil || !date.After(time.Now()) {
+			return nil
+		}
+		return domain.LocalizedText{
+			"ru": "Дата ещё не наступила.",
+			"en": "This date is in the future.",
+			"zh": "该日期尚未到来。",
+		}
+	}
+}
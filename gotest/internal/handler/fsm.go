@@ -0,0 +1,479 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/service"
+)
+
+// dialogSweepTick — как часто проверяются диалоги с истёкшим временем ожидания ответа
+const dialogSweepTick = 30 * time.Second
+
+const (
+	// dialogCancelledText — ответ на /cancel, если сценарий не задал свой
+	dialogCancelledText = "❌ Действие отменено."
+	// dialogTimeoutText — сообщение об истёкшем времени ожидания, если сценарий не задал своё
+	dialogTimeoutText = "⌛ Время ожидания ответа истекло, действие отменено."
+	// noDialogText — ответ на /cancel, когда отменять нечего
+	noDialogText = "Сейчас нет действия, которое можно отменить."
+)
+
+// ErrUnknownScene — сценарий диалога не зарегистрирован
+var ErrUnknownScene = errors.New("неизвестный сценарий диалога")
+
+// DraftHandler — обработчик, который хранит ввод администратора в собственных черновиках, а не в диалогах FSM
+// /cancel отменяет и такие черновики, чтобы следующее сообщение не ушло в забытый сценарий
+type DraftHandler interface {
+	// CancelDraft молча удаляет черновик пользователя и возвращает true, если он был
+	CancelDraft(userID int64) bool
+}
+
+// Transition — переход, которым шаг сценария отвечает на сообщение пользователя
+type Transition struct {
+	next   string // Следующий шаг ("" — остаться на текущем)
+	finish bool   // Завершить диалог
+}
+
+// Next переходит к шагу step и отправляет его вопрос
+func Next(step string) Transition {
+	return Transition{next: step}
+}
+
+// Stay оставляет диалог на текущем шаге (например, если ответ не прошёл проверку)
+func Stay() Transition {
+	return Transition{}
+}
+
+// Finish завершает диалог
+func Finish() Transition {
+	return Transition{finish: true}
+}
+
+// Step — шаг сценария с данными типа T
+// Изменения data в Handle и Callback сохраняются вместе с переходом
+// Шаг управляет диалогом только переходами: методы FSM из шага вызывать нельзя
+type Step[T any] struct {
+	// Enter отправляет вопрос шага при переходе на него (nil — вопрос задан вызывающим)
+	Enter func(bot *tgbotapi.BotAPI, chatID int64, data T) error
+	// Handle обрабатывает сообщение пользователя (nil — шаг принимает только кнопки)
+	Handle func(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, data *T) (Transition, error)
+	// Callback обрабатывает нажатие кнопки dlg_<value> (nil — шаг принимает только сообщения)
+	Callback func(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, value string, data *T) (Transition, error)
+}
+
+// Scene — сценарий диалога: именованный набор шагов с данными типа T
+// Данные сохраняются в хранилище в JSON, поэтому T должен сериализоваться в JSON
+type Scene[T any] struct {
+	Name    string
+	First   string             // Первый шаг
+	Steps   map[string]Step[T] // Шаги по названиям
+	Timeout time.Duration      // Время ожидания ответа (0 — по умолчанию для бота)
+	PerChat bool               // Один диалог на весь чат вместо отдельного у каждого пользователя
+	// OnCancel сообщает об отмене по /cancel (nil — стандартное сообщение)
+	OnCancel func(bot *tgbotapi.BotAPI, chatID int64, data T) error
+	// OnTimeout сообщает об истёкшем времени ожидания (nil — стандартное сообщение)
+	OnTimeout func(bot *tgbotapi.BotAPI, chatID int64, data T) error
+}
+
+// DialogScene — сценарий, который можно зарегистрировать в FSM
+// Реализуется типом *Scene[T]
+type DialogScene interface {
+	name() string
+	first() string
+	timeout() time.Duration
+	perChat() bool
+	hasStep(step string) bool
+	encode(data any) (json.RawMessage, error)
+	enter(bot *tgbotapi.BotAPI, state domain.DialogState) error
+	handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, state *domain.DialogState) (Transition, error)
+	callback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, value string, state *domain.DialogState) (Transition, error)
+	cancelled(bot *tgbotapi.BotAPI, state domain.DialogState) error
+	expired(bot *tgbotapi.BotAPI, state domain.DialogState) error
+}
+
+// FSM ведёт многошаговые диалоги: передаёт ответы текущему шагу сценария,
+// выполняет переходы, отменяет диалог по /cancel и по истечении времени ожидания
+//
+// Состояние диалогов хранится в DialogService, поэтому диалог продолжается после перезапуска бота
+type FSM struct {
+	dialogs *service.DialogService
+	scenes  map[string]DialogScene
+	drafts  []DraftHandler // Обработчики с собственными черновиками, которые тоже отменяет /cancel
+	mu      sync.Mutex     // Последовательная обработка ответов и истечения времени ожидания
+}
+
+// NewFSM создаёт конечный автомат диалогов
+func NewFSM(dialogs *service.DialogService) *FSM {
+	return &FSM{
+		dialogs: dialogs,
+		scenes:  make(map[string]DialogScene),
+	}
+}
+
+// Register регистрирует сценарий диалога
+func (f *FSM) Register(scene DialogScene) {
+	f.scenes[scene.name()] = scene
+	log.Printf("Зарегистрирован сценарий диалога %s", scene.name())
+}
+
+// AddDrafts подключает обработчик с собственными черновиками: /cancel будет отменять и их
+func (f *FSM) AddDrafts(handler DraftHandler) {
+	f.drafts = append(f.drafts, handler)
+}
+
+// Command возвращает команду отмены диалога
+func (f *FSM) Command() string {
+	return "cancel"
+}
+
+// Prefix возвращает префикс кнопок, отвечающих на шаг диалога
+func (f *FSM) Prefix() string {
+	return "dlg_"
+}
+
+// Start начинает сценарий name и отправляет вопрос первого шага
+// Текущий диалог пользователя (или чата для сценариев PerChat) заменяется новым
+// data — начальные данные; тип должен совпадать с типом данных сценария (nil — пустые данные)
+func (f *FSM) Start(bot *tgbotapi.BotAPI, chatID, userID int64, name string, data any) error {
+	scene, exists := f.scenes[name]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownScene, name)
+	}
+	if !scene.hasStep(scene.first()) {
+		return fmt.Errorf("в сценарии %s нет первого шага %q", name, scene.first())
+	}
+	raw, err := scene.encode(data)
+	if err != nil {
+		return err
+	}
+
+	owner := userID
+	if scene.perChat() {
+		owner = 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, err := f.dialogs.Begin(chatID, owner, name, scene.first(), raw, scene.timeout())
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения диалога: %w", err)
+	}
+	log.Printf("Диалог %s начат в чате %d (пользователь %d)", name, chatID, owner)
+
+	return scene.enter(bot, state)
+}
+
+// Active возвращает сценарий активного диалога пользователя в чате ("" — диалога нет)
+func (f *FSM) Active(chatID, userID int64) string {
+	state, exists := f.dialogs.Current(chatID, userID)
+	if !exists {
+		return ""
+	}
+	return state.Scene
+}
+
+// Cancel молча завершает диалог сценария name, если он активен
+// Используется, когда пользователь ушёл из сценария кнопками
+func (f *FSM) Cancel(chatID, userID int64, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, exists := f.dialogs.Current(chatID, userID)
+	if !exists || state.Scene != name {
+		return nil
+	}
+	return f.dialogs.End(state)
+}
+
+// Handle обрабатывает команду /cancel — отменяет активный диалог и черновики обработчиков
+func (f *FSM) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cancelled := false
+	for _, drafts := range f.drafts {
+		if drafts.CancelDraft(msg.From.ID) {
+			cancelled = true
+		}
+	}
+	if cancelled {
+		log.Printf("Черновики пользователя %d в чате %d отменены", msg.From.ID, msg.Chat.ID)
+	}
+
+	state, exists := f.dialogs.Current(msg.Chat.ID, msg.From.ID)
+	if !exists && cancelled {
+		return sendText(bot, msg.Chat.ID, dialogCancelledText)
+	}
+	if !exists {
+		return sendText(bot, msg.Chat.ID, noDialogText)
+	}
+	if err := f.dialogs.End(state); err != nil {
+		return fmt.Errorf("ошибка завершения диалога: %w", err)
+	}
+	log.Printf("Диалог %s в чате %d отменён пользователем %d", state.Scene, state.ChatID, msg.From.ID)
+
+	scene, exists := f.scenes[state.Scene]
+	if !exists {
+		return sendText(bot, msg.Chat.ID, dialogCancelledText)
+	}
+	return scene.cancelled(bot, state)
+}
+
+// HandleInput передаёт сообщение текущему шагу активного диалога
+func (f *FSM) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, scene, exists := f.current(msg.Chat.ID, msg.From.ID)
+	if !exists {
+		return false, nil
+	}
+
+	transition, err := scene.handle(bot, msg, &state)
+	if err != nil {
+		return true, err
+	}
+	return true, f.apply(bot, scene, state, transition)
+}
+
+// HandleCallback передаёт нажатие кнопки dlg_<значение> текущему шагу активного диалога
+func (f *FSM) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, scene, exists := f.current(callback.Message.Chat.ID, callback.From.ID)
+	if !exists {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "⌛ Этот диалог уже завершён"))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+	transition, err := scene.callback(bot, callback, strings.TrimPrefix(callback.Data, f.Prefix()), &state)
+	if err != nil {
+		return err
+	}
+	return f.apply(bot, scene, state, transition)
+}
+
+// StartTimeouts запускает фоновое завершение диалогов, время ожидания ответа в которых истекло
+// Диалоги, истёкшие пока бот был выключен, завершаются сразу
+func (f *FSM) StartTimeouts(bot *tgbotapi.BotAPI) {
+	go func() {
+		f.expire(bot, time.Now())
+		ticker := time.NewTicker(dialogSweepTick)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			f.expire(bot, now)
+		}
+	}()
+}
+
+// current возвращает активный диалог и его сценарий
+// Диалог сценария, которого больше нет (например, после обновления бота), завершается
+func (f *FSM) current(chatID, userID int64) (domain.DialogState, DialogScene, bool) {
+	state, exists := f.dialogs.Current(chatID, userID)
+	if !exists {
+		return domain.DialogState{}, nil, false
+	}
+
+	scene, exists := f.scenes[state.Scene]
+	if !exists {
+		log.Printf("Диалог неизвестного сценария %s в чате %d завершён", state.Scene, chatID)
+		if err := f.dialogs.End(state); err != nil {
+			log.Printf("Ошибка завершения диалога: %v", err)
+		}
+		return domain.DialogState{}, nil, false
+	}
+	return state, scene, true
+}
+
+// apply выполняет переход и сохраняет состояние диалога
+func (f *FSM) apply(bot *tgbotapi.BotAPI, scene DialogScene, state domain.DialogState, transition Transition) error {
+	switch {
+	case transition.finish:
+		if err := f.dialogs.End(state); err != nil {
+			return fmt.Errorf("ошибка завершения диалога: %w", err)
+		}
+		log.Printf("Диалог %s в чате %d завершён", state.Scene, state.ChatID)
+		return nil
+
+	case transition.next != "":
+		if !scene.hasStep(transition.next) {
+			return fmt.Errorf("в сценарии %s нет шага %q", state.Scene, transition.next)
+		}
+		state.Step = transition.next
+		if err := f.dialogs.Update(state); err != nil {
+			return fmt.Errorf("ошибка сохранения диалога: %w", err)
+		}
+		return scene.enter(bot, state)
+
+	default:
+		if err := f.dialogs.Update(state); err != nil {
+			return fmt.Errorf("ошибка сохранения диалога: %w", err)
+		}
+		return nil
+	}
+}
+
+// expire завершает диалоги с истёкшим временем ожидания и сообщает об этом в чаты
+func (f *FSM) expire(bot *tgbotapi.BotAPI, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states, err := f.dialogs.Expire(now)
+	if err != nil {
+		log.Printf("Ошибка завершения истёкших диалогов: %v", err)
+	}
+
+	for _, state := range states {
+		log.Printf("Время ожидания в диалоге %s в чате %d истекло", state.Scene, state.ChatID)
+		scene, exists := f.scenes[state.Scene]
+		if !exists {
+			continue
+		}
+		if err := scene.expired(bot, state); err != nil {
+			log.Printf("Ошибка уведомления об истёкшем диалоге в чате %d: %v", state.ChatID, err)
+		}
+	}
+}
+
+func (s *Scene[T]) name() string {
+	return s.Name
+}
+
+func (s *Scene[T]) first() string {
+	return s.First
+}
+
+func (s *Scene[T]) timeout() time.Duration {
+	return s.Timeout
+}
+
+func (s *Scene[T]) perChat() bool {
+	return s.PerChat
+}
+
+func (s *Scene[T]) hasStep(step string) bool {
+	_, exists := s.Steps[step]
+	return exists
+}
+
+// encode проверяет тип начальных данных и сериализует их
+func (s *Scene[T]) encode(data any) (json.RawMessage, error) {
+	var typed T
+	if data != nil {
+		var ok bool
+		if typed, ok = data.(T); !ok {
+			return nil, fmt.Errorf("сценарий %s ожидает данные типа %T, получено %T", s.Name, typed, data)
+		}
+	}
+
+	raw, err := json.Marshal(typed)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сериализации данных сценария %s: %w", s.Name, err)
+	}
+	return raw, nil
+}
+
+// decode восстанавливает данные сценария из состояния диалога
+func (s *Scene[T]) decode(state domain.DialogState) (T, error) {
+	var data T
+	if len(state.Data) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(state.Data, &data); err != nil {
+		return data, fmt.Errorf("ошибка разбора данных сценария %s: %w", s.Name, err)
+	}
+	return data, nil
+}
+
+func (s *Scene[T]) enter(bot *tgbotapi.BotAPI, state domain.DialogState) error {
+	step := s.Steps[state.Step]
+	if step.Enter == nil {
+		return nil
+	}
+
+	data, err := s.decode(state)
+	if err != nil {
+		return err
+	}
+	return step.Enter(bot, state.ChatID, data)
+}
+
+func (s *Scene[T]) handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, state *domain.DialogState) (Transition, error) {
+	step := s.Steps[state.Step]
+	if step.Handle == nil {
+		return Stay(), sendText(bot, msg.Chat.ID, "Выберите вариант кнопкой или отправьте /cancel, чтобы отменить действие.")
+	}
+
+	data, err := s.decode(*state)
+	if err != nil {
+		return Stay(), err
+	}
+	transition, err := step.Handle(bot, msg, &data)
+	if err != nil {
+		return Stay(), err
+	}
+	return transition, s.store(state, data)
+}
+
+func (s *Scene[T]) callback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, value string, state *domain.DialogState) (Transition, error) {
+	step := s.Steps[state.Step]
+	if step.Callback == nil {
+		return Stay(), nil
+	}
+
+	data, err := s.decode(*state)
+	if err != nil {
+		return Stay(), err
+	}
+	transition, err := step.Callback(bot, callback, value, &data)
+	if err != nil {
+		return Stay(), err
+	}
+	return transition, s.store(state, data)
+}
+
+func (s *Scene[T]) cancelled(bot *tgbotapi.BotAPI, state domain.DialogState) error {
+	if s.OnCancel == nil {
+		return sendText(bot, state.ChatID, dialogCancelledText)
+	}
+
+	data, err := s.decode(state)
+	if err != nil {
+		return err
+	}
+	return s.OnCancel(bot, state.ChatID, data)
+}
+
+func (s *Scene[T]) expired(bot *tgbotapi.BotAPI, state domain.DialogState) error {
+	if s.OnTimeout == nil {
+		return sendText(bot, state.ChatID, dialogTimeoutText)
+	}
+
+	data, err := s.decode(state)
+	if err != nil {
+		return err
+	}
+	return s.OnTimeout(bot, state.ChatID, data)
+}
+
+// store сохраняет изменённые шагом данные в состояние диалога
+func (s *Scene[T]) store(state *domain.DialogState, data T) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации данных сценария %s: %w", s.Name, err)
+	}
+	state.Data = raw
+	return nil
+}
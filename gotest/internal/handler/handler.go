@@ -1,9 +1,55 @@
 package handler
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
 
 // Handler — интерфейс для обработчиков команд
 type Handler interface {
-    Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error
-    Command() string  // Возвращает команду, которую обрабатывает этот обработчик
-}
\ No newline at end of file
+	Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error
+	Command() string // Возвращает команду, которую обрабатывает этот обработчик
+}
+
+// CallbackHandler — интерфейс для обработчиков нажатий на инлайн-кнопки
+// Обработчик получает все callback-запросы, данные которых начинаются с Prefix()
+// и сам отвечает на callback-запрос
+type CallbackHandler interface {
+	HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error
+	Prefix() string // Возвращает префикс данных callback-запроса
+}
+
+// InputHandler — обработчик ответов пользователя в многошаговых сценариях
+// HandleInput возвращает true, если сообщение было обработано (пользователь
+// находится в сценарии этого обработчика), иначе сообщение обрабатывается дальше
+type InputHandler interface {
+	HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error)
+}
+
+// PollAnswerHandler — обработчик ответов пользователей в неанонимных опросах бота
+// HandlePollAnswer возвращает true, если опрос относится к этому обработчику
+type PollAnswerHandler interface {
+	HandlePollAnswer(bot *tgbotapi.BotAPI, answer *tgbotapi.PollAnswer) (bool, error)
+}
+
+// Protected — обработчик, доступ к которому требует разрешения
+// Диспетчер проверяет разрешение до вызова обработчика
+type Protected interface {
+	Permission() domain.Permission
+}
+
+// sendText отправляет простое текстовое сообщение
+func sendText(bot *tgbotapi.BotAPI, chatID int64, text string) error {
+	reply := tgbotapi.NewMessage(chatID, text)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// sendHTML отправляет текстовое сообщение с HTML-разметкой
+func sendHTML(bot *tgbotapi.BotAPI, chatID int64, text string) error {
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ParseMode = tgbotapi.ModeHTML
+	_, err := bot.Send(reply)
+	return err
+}
@@ -26,7 +26,14 @@ func (h *HelpHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error
 	text := "Это справочная информация.\n\n" +
 		"<b>Доступные команды:</b>\n\n" +
 		"/start - начать работу с ботом\n" +
-		"/info - информация о вашем профиле\n\n" +
+		"/info - информация о вашем профиле\n" +
+		"/courses [запрос] - поиск по каталогу курсов\n" +
+		"/mycourses - мои курсы и прогресс\n" +
+		"/verify &lt;код&gt; - проверить сертификат\n" +
+		"/feedback - оставить отзыв о боте\n" +
+		"/support - написать в поддержку\n" +
+		"/premium - подписка и тарифы\n" +
+		"/cancel - отменить текущее действие\n\n" +
 		"<b>Важно:</b> Если вы нажали на кнопку \"🔽 Скрыть\" и клавиатура исчезла, " +
 		"нажмите /start - начать работу с ботом, и клавиатура снова появится."
 
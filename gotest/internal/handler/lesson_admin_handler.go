@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// lessonStep — шаг создания или изменения урока
+type lessonStep int
+
+const (
+	stepLessonTitle   lessonStep = iota // Ждём название
+	stepLessonContent                   // Ждём текст или медиа с подписью
+	stepLessonDrip                      // Ждём задержку открытия в днях
+)
+
+// lessonDraft — урок, который создаёт или изменяет администратор
+type lessonDraft struct {
+	step   lessonStep
+	lesson domain.Lesson
+	edit   bool // true — меняется одно поле существующего урока, false — создаётся новый урок
+}
+
+// lessonEditSteps сопоставляет поле из callback lsa_edit_<id>_<поле> с шагом ввода
+var lessonEditSteps = map[string]lessonStep{
+	"title": stepLessonTitle,
+	"body":  stepLessonContent,
+	"drip":  stepLessonDrip,
+}
+
+// LessonAdminHandler обрабатывает управление уроками курсов (lsa_*)
+type LessonAdminHandler struct {
+	lessons *service.LessonService
+	courses *service.CourseService
+	mu      sync.Mutex
+	drafts  map[int64]*lessonDraft // Ключ - ID администратора
+}
+
+// NewLessonAdminHandler создаёт новый обработчик управления уроками
+func NewLessonAdminHandler(lessons *service.LessonService, courses *service.CourseService) *LessonAdminHandler {
+	return &LessonAdminHandler{
+		lessons: lessons,
+		courses: courses,
+		drafts:  make(map[int64]*lessonDraft),
+	}
+}
+
+// Prefix возвращает префикс callback-запросов управления уроками
+func (h *LessonAdminHandler) Prefix() string {
+	return "lsa_"
+}
+
+// Permission возвращает разрешение, необходимое для управления уроками
+func (h *LessonAdminHandler) Permission() domain.Permission {
+	return domain.PermCourses
+}
+
+// CancelDraft удаляет черновик урока администратора (по /cancel)
+func (h *LessonAdminHandler) CancelDraft(adminID int64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, exists := h.drafts[adminID]
+	delete(h.drafts, adminID)
+	return exists
+}
+
+// HandleInput принимает ответы администратора при создании или изменении урока
+func (h *LessonAdminHandler) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	h.mu.Lock()
+	draft, exists := h.drafts[msg.From.ID]
+	h.mu.Unlock()
+	if !exists {
+		return false, nil
+	}
+
+	chatID := msg.Chat.ID
+	lesson := draft.lesson // Меняем копию, чтобы неверный ввод не испортил черновик
+
+	switch draft.step {
+	case stepLessonTitle:
+		lesson.Title = parseLocalized(msg.Text)
+	case stepLessonContent:
+		if !lessonContentFrom(msg, &lesson) {
+			return true, sendText(bot, chatID, "❌ Отправьте текст, фото, видео или файл.")
+		}
+	case stepLessonDrip:
+		days, err := strconv.Atoi(strings.TrimSpace(msg.Text))
+		if err != nil {
+			return true, sendText(bot, chatID, "❌ Отправьте число дней, например 3.")
+		}
+		lesson.UnlockAfterDays = days
+	}
+
+	if err := validateLessonStep(lesson, draft.step); err != nil {
+		return true, sendText(bot, chatID, "❌ "+err.Error()+". Попробуйте ещё раз.")
+	}
+	draft.lesson = lesson
+
+	if draft.edit {
+		return true, h.saveEdit(bot, msg.From.ID, chatID, draft)
+	}
+	return true, h.nextStep(bot, msg.From.ID, chatID, draft)
+}
+
+// HandleCallback обрабатывает кнопки управления уроками
+func (h *LessonAdminHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	data := callback.Data
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	adminID := callback.From.ID
+
+	switch data {
+	case "lsa_cancel":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		h.mu.Lock()
+		delete(h.drafts, adminID)
+		h.mu.Unlock()
+		return h.edit(bot, chatID, messageID, "❌ Изменение урока отменено.", nil)
+
+	case "lsa_nodrip":
+		h.mu.Lock()
+		draft, exists := h.drafts[adminID]
+		h.mu.Unlock()
+		if !exists || draft.step != stepLessonDrip {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Черновик урока не найден"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+		draft.lesson.UnlockAfterDays = 0
+		if draft.edit {
+			return h.saveEdit(bot, adminID, chatID, draft)
+		}
+		return h.nextStep(bot, adminID, chatID, draft)
+	}
+
+	// Формат: lsa_<действие>_<id>[_<поле>]
+	// Для list и new id — это ID курса, для остальных действий — ID урока
+	parts := strings.Split(strings.TrimPrefix(data, "lsa_"), "_")
+	if len(parts) < 2 {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	action := parts[0]
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	switch action {
+	case "list":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.showList(bot, chatID, messageID, id)
+
+	case "new":
+		if _, err := h.courses.Get(id); err != nil {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Курс не найден"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		h.mu.Lock()
+		h.drafts[adminID] = &lessonDraft{step: stepLessonTitle, lesson: domain.Lesson{CourseID: id}}
+		h.mu.Unlock()
+		return h.prompt(bot, chatID, stepLessonTitle)
+	}
+
+	lesson, err := h.lessons.Get(id)
+	if errors.Is(err, repository.ErrLessonNotFound) {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Урок не найден"))
+		return err
+	}
+	if err != nil {
+		bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+		return err
+	}
+
+	switch action {
+	case "view":
+		// Просто показываем карточку урока
+
+	case "edit":
+		if len(parts) < 3 {
+			return nil
+		}
+		step, ok := lessonEditSteps[parts[2]]
+		if !ok {
+			return nil
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		h.mu.Lock()
+		h.drafts[adminID] = &lessonDraft{step: step, lesson: lesson, edit: true}
+		h.mu.Unlock()
+		return h.prompt(bot, chatID, step)
+
+	case "up", "down":
+		delta := 1
+		if action == "up" {
+			delta = -1
+		}
+		if err := h.lessons.Move(adminID, id, delta); err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "↕️ Порядок изменён"))
+		return h.showList(bot, chatID, messageID, lesson.CourseID)
+
+	case "preview":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		if err := sendText(bot, chatID, "👀 Так урок увидят пользователи:"); err != nil {
+			return err
+		}
+		return sendLesson(bot, chatID, lesson, "", domain.DefaultLanguage, nil)
+
+	case "del":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		kb := keyboard.NewLessonDeleteKeyboard(id)
+		return h.edit(bot, chatID, messageID,
+			fmt.Sprintf("🗑 Удалить урок «%s»? Это действие нельзя отменить.", lesson.Title.Get(domain.DefaultLanguage)), &kb)
+
+	case "delyes":
+		if err := h.lessons.Delete(adminID, id); err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, "🗑 Урок удалён"))
+		return h.showList(bot, chatID, messageID, lesson.CourseID)
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+	kb := keyboard.NewLessonAdminKeyboard(lesson)
+	return h.edit(bot, chatID, messageID, lessonAdminText(lesson), &kb)
+}
+
+// showList показывает уроки курса
+// Если messageID равен 0, отправляет новое сообщение вместо редактирования
+func (h *LessonAdminHandler) showList(bot *tgbotapi.BotAPI, chatID int64, messageID int, courseID int) error {
+	course, err := h.courses.Get(courseID)
+	if err != nil {
+		sendText(bot, chatID, "❌ Курс не найден.")
+		return err
+	}
+
+	lessons := h.lessons.ByCourse(courseID)
+	text := fmt.Sprintf("📖 Уроки курса «%s» (%d)\n\n⏳ — открывается через N дней после записи. Выберите урок для изменения:",
+		course.Title.Get(domain.DefaultLanguage), len(lessons))
+	kb := keyboard.NewLessonAdminListKeyboard(courseID, lessons)
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ReplyMarkup = kb
+		_, err := bot.Send(reply)
+		return err
+	}
+	return h.edit(bot, chatID, messageID, text, &kb)
+}
+
+// prompt просит администратора ввести значение для шага step
+func (h *LessonAdminHandler) prompt(bot *tgbotapi.BotAPI, chatID int64, step lessonStep) error {
+	var text string
+	kb := keyboard.NewCancelKeyboard("lsa_cancel")
+
+	switch step {
+	case stepLessonTitle:
+		text = "✏️ Отправьте название урока (до 64 символов).\n\n" +
+			"Переводы — отдельными строками:\n<code>Переменные и типы\nen: Variables and Types</code>"
+	case stepLessonContent:
+		text = "📝 Отправьте содержимое урока: текст (до 3500 символов) или фото, видео, файл с подписью (до 900 символов).\n\n" +
+			"Код оформляйте блоками <code>```go ... ```</code>, перевод начинайте со строки <code>en:</code>"
+	case stepLessonDrip:
+		text = "⏳ Через сколько дней после записи открыть урок? Отправьте число от 0 до 365."
+		kb = keyboard.NewLessonDripKeyboard()
+	}
+
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ParseMode = tgbotapi.ModeHTML
+	reply.ReplyMarkup = kb
+	_, err := bot.Send(reply)
+	return err
+}
+
+// nextStep переводит черновик нового урока на следующий шаг
+// После последнего шага урок сохраняется
+func (h *LessonAdminHandler) nextStep(bot *tgbotapi.BotAPI, adminID, chatID int64, draft *lessonDraft) error {
+	if draft.step < stepLessonDrip {
+		draft.step++
+		return h.prompt(bot, chatID, draft.step)
+	}
+
+	lesson := draft.lesson
+	if err := h.lessons.Create(adminID, &lesson); err != nil {
+		if errors.Is(err, service.ErrLessonInvalid) {
+			return sendText(bot, chatID, "❌ "+err.Error())
+		}
+		sendText(bot, chatID, "❌ Не удалось сохранить урок.")
+		return err
+	}
+
+	h.mu.Lock()
+	delete(h.drafts, adminID)
+	h.mu.Unlock()
+
+	reply := tgbotapi.NewMessage(chatID, "✅ Урок создан.\n\n"+lessonAdminText(lesson))
+	reply.ReplyMarkup = keyboard.NewLessonAdminKeyboard(lesson)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// saveEdit сохраняет изменённое поле существующего урока и показывает его карточку
+func (h *LessonAdminHandler) saveEdit(bot *tgbotapi.BotAPI, adminID, chatID int64, draft *lessonDraft) error {
+	h.mu.Lock()
+	delete(h.drafts, adminID)
+	h.mu.Unlock()
+
+	// Берём актуальную версию урока: пока администратор вводил значение, урок могли изменить
+	lesson, err := h.lessons.Get(draft.lesson.ID)
+	if err != nil {
+		sendText(bot, chatID, "❌ Урок не найден.")
+		return err
+	}
+
+	field := ""
+	switch draft.step {
+	case stepLessonTitle:
+		lesson.Title, field = draft.lesson.Title, "title"
+	case stepLessonContent:
+		lesson.Kind, lesson.Body, lesson.MediaFileID = draft.lesson.Kind, draft.lesson.Body, draft.lesson.MediaFileID
+		field = "content"
+	case stepLessonDrip:
+		lesson.UnlockAfterDays, field = draft.lesson.UnlockAfterDays, "drip"
+	}
+
+	if err := h.lessons.Update(adminID, &lesson, field); err != nil {
+		if errors.Is(err, service.ErrLessonInvalid) {
+			return sendText(bot, chatID, "❌ "+err.Error())
+		}
+		sendText(bot, chatID, "❌ Не удалось сохранить урок.")
+		return err
+	}
+
+	reply := tgbotapi.NewMessage(chatID, "✅ Урок обновлён.\n\n"+lessonAdminText(lesson))
+	reply.ReplyMarkup = keyboard.NewLessonAdminKeyboard(lesson)
+	_, err = bot.Send(reply)
+	return err
+}
+
+// edit заменяет текст и клавиатуру сообщения
+func (h *LessonAdminHandler) edit(bot *tgbotapi.BotAPI, chatID int64, messageID int, text string, kb *tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ReplyMarkup = kb
+	_, err := bot.Send(edit)
+	return err
+}
+
+// lessonContentFrom заполняет вид, текст и файл урока из сообщения администратора
+// Возвращает false, если сообщение не содержит текста или поддерживаемого медиа
+func lessonContentFrom(msg *tgbotapi.Message, lesson *domain.Lesson) bool {
+	lesson.MediaFileID = ""
+	lesson.Body = parseLocalized(msg.Caption)
+
+	switch {
+	case len(msg.Photo) > 0:
+		lesson.Kind = domain.LessonPhoto
+		lesson.MediaFileID = msg.Photo[len(msg.Photo)-1].FileID // Самое большое разрешение
+	case msg.Video != nil:
+		lesson.Kind = domain.LessonVideo
+		lesson.MediaFileID = msg.Video.FileID
+	case msg.Document != nil:
+		lesson.Kind = domain.LessonDocument
+		lesson.MediaFileID = msg.Document.FileID
+	case msg.Text != "":
+		lesson.Kind = domain.LessonText
+		lesson.Body = parseLocalized(msg.Text)
+	default:
+		return false
+	}
+	return true
+}
+
+// lessonAdminText формирует карточку урока для администратора
+func lessonAdminText(lesson domain.Lesson) string {
+	drip := "сразу после записи"
+	if lesson.UnlockAfterDays > 0 {
+		drip = fmt.Sprintf("через %d дн. после записи", lesson.UnlockAfterDays)
+	}
+
+	text := fmt.Sprintf("%s Урок #%d %s\n\nКурс: #%d\nПозиция: %d\nВид: %s\nОткрывается: %s\nДлина текста: %d\nТест к уроку: /quiz %d",
+		lesson.Kind.Icon(), lesson.ID, lesson.Title.Get(domain.DefaultLanguage),
+		lesson.CourseID, lesson.Position, lesson.Kind, drip, len([]rune(lesson.Body.Get(domain.DefaultLanguage))), lesson.ID)
+
+	for _, lang := range []string{"en", "zh"} {
+		if title, ok := lesson.Title[lang]; ok {
+			text += fmt.Sprintf("\nНазвание (%s): %s", lang, title)
+		}
+	}
+	return text
+}
+
+// validateLessonStep проверяет только поля, введённые на шаге step
+// Остальные поля черновика на этом шаге ещё могут быть пустыми
+func validateLessonStep(lesson domain.Lesson, step lessonStep) error {
+	check := domain.Lesson{
+		Kind:  domain.LessonText,
+		Title: domain.LocalizedText{domain.DefaultLanguage: "-"},
+		Body:  domain.LocalizedText{domain.DefaultLanguage: "-"},
+	}
+	switch step {
+	case stepLessonTitle:
+		check.Title = lesson.Title
+	case stepLessonContent:
+		check.Kind, check.Body, check.MediaFileID = lesson.Kind, lesson.Body, lesson.MediaFileID
+	case stepLessonDrip:
+		check.UnlockAfterDays = lesson.UnlockAfterDays
+	}
+	return service.ValidateLesson(check)
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// inlineCode — фрагмент `кода` внутри строки урока
+var inlineCode = regexp.MustCompile("`([^`\n]+)`")
+
+// LessonHandler выдаёт уроки записанным на курс пользователям (кнопки les_*)
+type LessonHandler struct {
+	enrollments  *service.EnrollmentService
+	quizzes      *service.QuizService
+	certificates *service.CertificateService
+	features     *service.FeatureService
+	settings     *repository.SettingsRepository
+}
+
+// NewLessonHandler создаёт новый обработчик уроков
+func NewLessonHandler(enrollments *service.EnrollmentService, quizzes *service.QuizService, certificates *service.CertificateService, features *service.FeatureService, settings *repository.SettingsRepository) *LessonHandler {
+	return &LessonHandler{
+		enrollments:  enrollments,
+		quizzes:      quizzes,
+		certificates: certificates,
+		features:     features,
+		settings:     settings,
+	}
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *LessonHandler) Prefix() string {
+	return "les_"
+}
+
+// HandleCallback обрабатывает список уроков, открытие, продолжение и прохождение урока
+// Формат: les_<действие>_<id курса>[_<id урока или страница>]
+func (h *LessonHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	userID := callback.From.ID
+	if !h.features.Enabled(domain.FeatureCourseEnrollment, userID) {
+		_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, enrollmentDisabledText))
+		return err
+	}
+
+	// Список уроков: les_list_<id курса>_p_<страница>, les_list_<id курса>_pick_<страница>
+	if rest, found := strings.CutPrefix(callback.Data, "les_list_"); found {
+		idStr, _, _ := strings.Cut(rest, "_")
+		courseID, err := strconv.Atoi(idStr)
+		page, picker, ok := keyboard.NewLessonsPaginator(courseID, "").Parse(callback.Data)
+		if err != nil || !ok {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+			return err
+		}
+		return h.showList(bot, callback, courseID, page, picker)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(callback.Data, "les_"), "_")
+	action := parts[0]
+	var args []int
+	for _, part := range parts[1:] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+			return err
+		}
+		args = append(args, n)
+	}
+	if len(args) == 0 || (action != "resume" && len(args) < 2) {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	courseID := args[0]
+
+	switch action {
+	case "resume":
+		lessonID, err := h.enrollments.ResumeLesson(userID, courseID)
+		if err != nil {
+			return answerEnrollmentError(bot, callback, err)
+		}
+		return h.open(bot, callback, courseID, lessonID)
+
+	case "open":
+		return h.open(bot, callback, courseID, args[1])
+
+	case "done":
+		return h.complete(bot, callback, courseID, args[1])
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+}
+
+// open отправляет урок новым сообщением и запоминает его для продолжения
+func (h *LessonHandler) open(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, courseID, lessonID int) error {
+	plan, index, err := h.enrollments.OpenLesson(callback.From.ID, courseID, lessonID)
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	step := plan[index]
+	header := fmt.Sprintf("Урок %d из %d. ", step.Number, len(plan))
+	_, quiz := h.quizzes.ForLesson(lessonID)
+	kb := keyboard.NewLessonKeyboard(courseID, plan, index, quiz)
+	return sendLesson(bot, callback.Message.Chat.ID, step.Lesson, header, h.settings.Get(callback.From.ID).Language, &kb)
+}
+
+// showList показывает страницу списка уроков курса
+// picker - показать выбор номера страницы вместо списка
+func (h *LessonHandler) showList(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, courseID, page int, picker bool) error {
+	userID := callback.From.ID
+	progress, err := h.enrollments.Progress(userID, courseID)
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+	plan, err := h.enrollments.Plan(userID, courseID)
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	lang := h.settings.Get(userID).Language
+	paginator := keyboard.NewLessonsPaginator(courseID, lang)
+	if picker {
+		kb, err := paginator.Picker(keyboard.SliceSource(plan), page)
+		if err != nil {
+			return err
+		}
+		_, err = bot.Send(tgbotapi.NewEditMessageReplyMarkup(callback.Message.Chat.ID, callback.Message.MessageID, kb))
+		return err
+	}
+
+	paginator.Header = fmt.Sprintf("📋 %s\n%s\n\n✅ — пройден, 🔒 — откроется позже",
+		progress.Course.Title.Get(lang), progressLine(progress))
+	result, err := paginator.Render(keyboard.SliceSource(plan), page)
+	if err != nil {
+		return err
+	}
+	kb := keyboard.AddLessonsProgressRow(result.Keyboard, courseID)
+
+	// Под медиа-уроком текст не отредактировать — отправляем список новым сообщением
+	if callback.Message.Text == "" {
+		reply := tgbotapi.NewMessage(callback.Message.Chat.ID, result.Text)
+		reply.ReplyMarkup = kb
+		_, err := bot.Send(reply)
+		return err
+	}
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, result.Text)
+	edit.ReplyMarkup = &kb
+	_, err = bot.Send(edit)
+	return err
+}
+
+// complete отмечает урок пройденным и обновляет кнопки под ним
+// Урок с тестом засчитывается только по итогам теста
+// Если пройден последний урок, поздравляет пользователя
+func (h *LessonHandler) complete(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, courseID, lessonID int) error {
+	userID := callback.From.ID
+	if _, quiz := h.quizzes.ForLesson(lessonID); quiz {
+		_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, "📝 Чтобы завершить урок, пройдите тест."))
+		return err
+	}
+	enrollment, _ := h.enrollments.Get(userID, courseID)
+
+	progress, err := h.enrollments.CompleteLesson(userID, courseID, lessonID)
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+	plan, index, err := h.enrollments.OpenLesson(userID, courseID, lessonID)
+	if err != nil {
+		return answerEnrollmentError(bot, callback, err)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, fmt.Sprintf("✅ Урок пройден · %d%%", progress.Percent())))
+
+	kb := keyboard.NewLessonKeyboard(courseID, plan, index, false)
+	edit := tgbotapi.NewEditMessageReplyMarkup(callback.Message.Chat.ID, callback.Message.MessageID, kb)
+	if _, err := bot.Send(edit); err != nil {
+		return err
+	}
+
+	if enrollment.Completed() || !progress.Enrollment.Completed() {
+		return nil
+	}
+	return sendCourseCompleted(bot, callback.Message.Chat.ID, callback.From, progress, h.settings.Get(userID).Language, h.certificates)
+}
+
+// sendCourseCompleted поздравляет пользователя с прохождением курса и отправляет сертификат
+// Ошибка выдачи сертификата не мешает поздравлению: сертификат можно получить позже с экрана курса
+func sendCourseCompleted(bot *tgbotapi.BotAPI, chatID int64, user *tgbotapi.User, progress domain.CourseProgress, lang string, certificates *service.CertificateService) error {
+	if err := sendText(bot, chatID, fmt.Sprintf("🏆 Поздравляем! Вы прошли курс «%s».", progress.Course.Title.Get(lang))); err != nil {
+		return err
+	}
+
+	certificate, err := certificates.Issue(user.ID, progress.Course.ID, certificateName(user))
+	if err != nil {
+		return fmt.Errorf("ошибка выдачи сертификата: %w", err)
+	}
+	return sendCertificate(bot, chatID, certificate, lang, certificates)
+}
+
+// sendLesson отправляет урок на языке lang: текст или медиа с подписью
+// header добавляется перед названием урока (например «Урок 2 из 5. »)
+func sendLesson(bot *tgbotapi.BotAPI, chatID int64, lesson domain.Lesson, header, lang string, kb *tgbotapi.InlineKeyboardMarkup) error {
+	text := fmt.Sprintf("<b>%s</b>", html.EscapeString(header+lesson.Title.Get(lang)))
+	if body := lesson.Body.Get(lang); body != "" {
+		text += "\n\n" + renderLessonBody(body)
+	}
+
+	var msg tgbotapi.Chattable
+	switch lesson.Kind {
+	case domain.LessonPhoto:
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(lesson.MediaFileID))
+		photo.Caption, photo.ParseMode = text, tgbotapi.ModeHTML
+		if kb != nil {
+			photo.ReplyMarkup = kb
+		}
+		msg = photo
+	case domain.LessonVideo:
+		video := tgbotapi.NewVideo(chatID, tgbotapi.FileID(lesson.MediaFileID))
+		video.Caption, video.ParseMode = text, tgbotapi.ModeHTML
+		if kb != nil {
+			video.ReplyMarkup = kb
+		}
+		msg = video
+	case domain.LessonDocument:
+		document := tgbotapi.NewDocument(chatID, tgbotapi.FileID(lesson.MediaFileID))
+		document.Caption, document.ParseMode = text, tgbotapi.ModeHTML
+		if kb != nil {
+			document.ReplyMarkup = kb
+		}
+		msg = document
+	default:
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ParseMode = tgbotapi.ModeHTML
+		if kb != nil {
+			reply.ReplyMarkup = kb
+		}
+		msg = reply
+	}
+
+	_, err := bot.Send(msg)
+	return err
+}
+
+// renderLessonBody переводит текст урока в HTML Telegram
+// Блоки ```go ... ``` становятся блоками кода с подсветкой, `код` — моноширинным текстом
+func renderLessonBody(text string) string {
+	var sb strings.Builder
+	for i, part := range strings.Split(text, "```") {
+		if i%2 == 0 {
+			sb.WriteString(inlineCode.ReplaceAllString(html.EscapeString(part), "<code>$1</code>"))
+			continue
+		}
+
+		// Первая строка блока кода — язык, если она состоит из одного слова
+		lang, code, found := strings.Cut(part, "\n")
+		if !found || strings.ContainsAny(lang, " \t") {
+			lang, code = "", part
+		}
+		code = html.EscapeString(strings.Trim(code, "\n"))
+		if lang != "" {
+			fmt.Fprintf(&sb, `<pre><code class="language-%s">%s</code></pre>`, html.EscapeString(lang), code)
+		} else {
+			fmt.Fprintf(&sb, "<pre>%s</pre>", code)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/service"
+)
+
+// MaintenanceHandler обрабатывает команду /maintenance
+type MaintenanceHandler struct {
+	maintenance *service.MaintenanceService
+}
+
+// NewMaintenanceHandler создаёт новый обработчик команды /maintenance
+func NewMaintenanceHandler(maintenance *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenance: maintenance}
+}
+
+// Command возвращает команду
+func (h *MaintenanceHandler) Command() string {
+	return "maintenance"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *MaintenanceHandler) Permission() domain.Permission {
+	return domain.PermMaintenance
+}
+
+// Handle обрабатывает команду /maintenance [on|off|message <язык> [текст]]
+func (h *MaintenanceHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return sendText(bot, msg.Chat.ID, maintenanceText(h.maintenance.State()))
+	}
+
+	switch args[0] {
+	case "on", "off":
+		enabled := args[0] == "on"
+		if err := h.maintenance.SetEnabled(msg.From.ID, enabled); err != nil {
+			sendText(bot, msg.Chat.ID, "❌ Не удалось сохранить режим обслуживания.")
+			return err
+		}
+		if enabled {
+			return sendText(bot, msg.Chat.ID, "🛠 Режим обслуживания включён. Пользователи получают сообщение о работах, сотрудники работают как обычно.")
+		}
+		return sendText(bot, msg.Chat.ID, "✅ Режим обслуживания выключен.")
+
+	case "message":
+		if len(args) < 2 {
+			break
+		}
+		// Текст берём из исходной строки, чтобы сохранить переносы и пробелы
+		text := strings.TrimSpace(msg.CommandArguments())
+		text = strings.TrimSpace(strings.TrimPrefix(text, args[0]))
+		text = strings.TrimSpace(strings.TrimPrefix(text, args[1]))
+
+		err := h.maintenance.SetMessage(msg.From.ID, args[1], text)
+		if errors.Is(err, service.ErrUnknownLanguage) {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Неизвестный язык «%s». Доступны: ru, en, zh.", args[1]))
+		}
+		if err != nil {
+			sendText(bot, msg.Chat.ID, "❌ Не удалось сохранить текст.")
+			return err
+		}
+		if text == "" {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Для языка %s восстановлен текст по умолчанию.", args[1]))
+		}
+		return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Текст для языка %s сохранён.", args[1]))
+	}
+
+	return sendText(bot, msg.Chat.ID,
+		"❌ Использование:\n"+
+			"/maintenance — текущее состояние\n"+
+			"/maintenance on | off — включить или выключить\n"+
+			"/maintenance message <ru|en|zh> [текст] — текст для пользователей (без текста — по умолчанию)")
+}
+
+// maintenanceText формирует описание текущего состояния режима обслуживания
+func maintenanceText(state domain.Maintenance) string {
+	status := "✅ Режим обслуживания выключен."
+	if state.Enabled {
+		status = "🛠 Режим обслуживания включён."
+	}
+	if !state.ChangedAt.IsZero() {
+		changedBy := fmt.Sprintf("администратор %d", state.ChangedBy)
+		if state.ChangedBy == 0 {
+			changedBy = "конфигурация"
+		}
+		status += fmt.Sprintf("\nИзменён: %s (%s)", state.ChangedAt.Format("2006-01-02 15:04"), changedBy)
+	}
+
+	if len(state.Messages) > 0 {
+		status += "\n\nСвои тексты:"
+		for _, lang := range []string{"ru", "en", "zh"} {
+			if text, ok := state.Messages[lang]; ok {
+				status += fmt.Sprintf("\n%s: %s", lang, text)
+			}
+		}
+	}
+
+	return status + "\n\nУправление: /maintenance on | off | message <язык> [текст]"
+}
@@ -2,19 +2,32 @@ package handler
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"telegram-bot/internal/domain"
 	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/service"
+)
+
+// Ответы на сообщения, для которых не нашлось правила автоответа
+const (
+	FallbackEcho    = "echo"    // Эхо-ответ с подсказкой про меню
+	FallbackSupport = "support" // Предложение написать в поддержку
 )
 
 // MessageHandler обрабатывает обычные текстовые сообщения
-type MessageHandler struct{}
+type MessageHandler struct {
+	faq      *service.FAQService
+	fallback string // FallbackEcho или FallbackSupport
+}
 
 // NewMessageHandler создаёт новый обработчик сообщений
-func NewMessageHandler() *MessageHandler {
-	return &MessageHandler{}
+// fallback - ответ, если ни одно правило автоответа не подошло
+func NewMessageHandler(faq *service.FAQService, fallback string) *MessageHandler {
+	return &MessageHandler{faq: faq, fallback: fallback}
 }
 
 // Handle обрабатывает текстовое сообщение
@@ -39,10 +52,15 @@ func (h *MessageHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, get
 		return h.handleHideKeyboard(bot, chatID)
 
 	default:
-		// Обработка других текстовых сообщений
-		if strings.Contains(strings.ToLower(text), "подпис") {
-			reply := tgbotapi.NewMessage(chatID, "Напишите администратору @Alex152197 — он с радостью вам поможет! 😊")
-			reply.ReplyMarkup = keyboard.NewMainMenuKeyboard()
+		// Обработка других текстовых сообщений правилами автоответов
+		lang := getLanguage(msg.From.ID)
+		if rule, found := h.faq.Match(text); found {
+			return h.handleFAQ(bot, chatID, rule, lang)
+		}
+
+		if h.fallback == FallbackSupport {
+			reply := tgbotapi.NewMessage(chatID, "🤔 Не нашёл ответа на ваш вопрос. Напишите в поддержку — вам помогут!")
+			reply.ReplyMarkup = keyboard.NewSupportOpenKeyboard()
 			_, err := bot.Send(reply)
 			return err
 		}
@@ -56,6 +74,22 @@ func (h *MessageHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, get
 	}
 }
 
+// handleFAQ отправляет автоответ правила на языке lang
+func (h *MessageHandler) handleFAQ(bot *tgbotapi.BotAPI, chatID int64, rule domain.FAQRule, lang string) error {
+	reply := tgbotapi.NewMessage(chatID, rule.Answer.Get(lang))
+	reply.ReplyMarkup = keyboard.NewMainMenuKeyboard()
+
+	kb, err := keyboard.NewFAQAnswerKeyboard(rule.Buttons, lang)
+	if err != nil {
+		log.Printf("Ошибка кнопок правила автоответа #%d: %v", rule.ID, err)
+	} else if kb != nil {
+		reply.ReplyMarkup = kb
+	}
+
+	_, err = bot.Send(reply)
+	return err
+}
+
 // handleProfile обрабатывает запрос профиля
 func (h *MessageHandler) handleProfile(bot *tgbotapi.BotAPI, chatID int64) error {
 	user := bot.Self
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// ordersListLimit — сколько последних заказов показывает /orders
+const ordersListLimit = 20
+
+// chargeAlreadyRefunded — ответ Telegram на refundStarPayment, если Stars по платежу уже вернули
+const chargeAlreadyRefunded = "Bad Request: CHARGE_ALREADY_REFUNDED"
+
+// PaymentAdminHandler обрабатывает команду /orders — просмотр заказов и возврат оплаты
+type PaymentAdminHandler struct {
+	payments *service.PaymentService
+	users    *repository.UserRepository
+	settings *repository.SettingsRepository
+}
+
+// NewPaymentAdminHandler создаёт новый обработчик команды /orders
+func NewPaymentAdminHandler(payments *service.PaymentService, users *repository.UserRepository, settings *repository.SettingsRepository) *PaymentAdminHandler {
+	return &PaymentAdminHandler{payments: payments, users: users, settings: settings}
+}
+
+// Command возвращает команду
+func (h *PaymentAdminHandler) Command() string {
+	return "orders"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *PaymentAdminHandler) Permission() domain.Permission {
+	return domain.PermPayments
+}
+
+// Handle обрабатывает команду /orders [user <пользователь> | ID [refund]]
+func (h *PaymentAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+	args := strings.Fields(msg.CommandArguments())
+
+	switch {
+	case len(args) == 0:
+		return sendText(bot, chatID, ordersListText("🧾 Последние заказы", h.payments.List(0)))
+
+	case args[0] == "user" && len(args) == 2:
+		userID, ok := resolveUserID(h.users, args[1])
+		if !ok {
+			return sendText(bot, chatID, fmt.Sprintf("❌ Пользователь «%s» не найден.", args[1]))
+		}
+		return sendText(bot, chatID, ordersListText(fmt.Sprintf("🧾 Заказы пользователя %d", userID), h.payments.List(userID)))
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil || len(args) > 2 || (len(args) == 2 && args[1] != "refund") {
+		return h.sendUsage(bot, chatID)
+	}
+	order, err := h.payments.Get(id)
+	if errors.Is(err, repository.ErrOrderNotFound) {
+		return sendText(bot, chatID, fmt.Sprintf("❌ Заказ #%d не найден.", id))
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		return sendText(bot, chatID, orderText(order))
+	}
+	return h.refund(bot, msg.From.ID, chatID, order)
+}
+
+// refund возвращает деньги за заказ и отзывает доступ
+// Stars возвращаются через Telegram, оплату у провайдера нужно вернуть в его кабинете
+func (h *PaymentAdminHandler) refund(bot *tgbotapi.BotAPI, actorID, chatID int64, order domain.Order) error {
+	if order.Status != domain.OrderPaid && order.Status != domain.OrderRefunding {
+		return sendText(bot, chatID, "❌ "+service.ErrNotRefundable.Error())
+	}
+
+	note := ""
+	if order.Currency == domain.CurrencyStars {
+		// Возврат сохраняется до запроса к Telegram: если бот упадёт после возврата Stars,
+		// заказ останется «возвращается», и повторная команда его завершит
+		if _, err := h.payments.StartRefund(actorID, order.ID); err != nil {
+			sendText(bot, chatID, "❌ Не удалось начать возврат заказа.")
+			return err
+		}
+
+		_, err := bot.MakeRequest("refundStarPayment", tgbotapi.Params{
+			"user_id":                    strconv.FormatInt(order.UserID, 10),
+			"telegram_payment_charge_id": order.TelegramChargeID,
+		})
+		// Stars уже вернули при прошлой попытке — осталось отметить заказ
+		var apiErr *tgbotapi.Error
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.Message == chargeAlreadyRefunded) {
+			if _, cancelErr := h.payments.CancelRefund(actorID, order.ID, err.Error()); cancelErr != nil {
+				log.Printf("Ошибка отмены возврата заказа #%d: %v", order.ID, cancelErr)
+			}
+			return sendText(bot, chatID, fmt.Sprintf("❌ Telegram не вернул Stars: %v", err))
+		}
+	} else {
+		note = "\n\n⚠️ Верните деньги в кабинете платёжного провайдера, платёж: " + order.ProviderChargeID
+	}
+
+	order, err := h.payments.Refund(actorID, order.ID)
+	if err != nil {
+		sendText(bot, chatID, "❌ Не удалось отметить возврат заказа.")
+		return err
+	}
+
+	h.notify(bot, order)
+	return sendText(bot, chatID, "✅ Заказ возвращён.\n\n"+orderText(order)+note)
+}
+
+// notify сообщает пользователю о возврате оплаты
+func (h *PaymentAdminHandler) notify(bot *tgbotapi.BotAPI, order domain.Order) {
+	lang := h.settings.Get(order.UserID).Language
+	text := domain.LocalizedText{
+		"ru": fmt.Sprintf("↩️ Оплата заказа «%s» (%s) возвращена, доступ закрыт.", order.Title, order.PriceText()),
+		"en": fmt.Sprintf("↩️ Your payment for “%s” (%s) has been refunded and access revoked.", order.Title, order.PriceText()),
+		"zh": fmt.Sprintf("↩️ 订单“%s”（%s）已退款，访问权限已关闭。", order.Title, order.PriceText()),
+	}
+	if order.Kind == domain.OrderPlan {
+		text = domain.LocalizedText{
+			"ru": fmt.Sprintf("↩️ Оплата заказа «%s» (%s) возвращена, оплаченные им дни подписки списаны: /premium", order.Title, order.PriceText()),
+			"en": fmt.Sprintf("↩️ Your payment for “%s” (%s) has been refunded and its subscription days removed: /premium", order.Title, order.PriceText()),
+			"zh": fmt.Sprintf("↩️ 订单“%s”（%s）已退款，相应的订阅天数已扣除：/premium", order.Title, order.PriceText()),
+		}
+	}
+	if err := sendText(bot, order.UserID, text.Get(lang)); err != nil {
+		log.Printf("Ошибка уведомления пользователя %d о возврате: %v", order.UserID, err)
+	}
+}
+
+// sendUsage отправляет подсказку по команде /orders
+func (h *PaymentAdminHandler) sendUsage(bot *tgbotapi.BotAPI, chatID int64) error {
+	return sendText(bot, chatID,
+		"❌ Использование:\n"+
+			"/orders — последние заказы\n"+
+			"/orders user <ID или @username> — заказы пользователя\n"+
+			"/orders <ID> — заказ целиком\n"+
+			"/orders <ID> refund — вернуть оплату и закрыть оплаченный доступ")
+}
+
+// ordersListText формирует список заказов (не больше ordersListLimit)
+func ordersListText(header string, orders []domain.Order) string {
+	if len(orders) == 0 {
+		return header + "\n\nЗаказов нет."
+	}
+
+	text := header + ":\n"
+	for i, order := range orders {
+		if i == ordersListLimit {
+			text += fmt.Sprintf("\n…и ещё %d", len(orders)-ordersListLimit)
+			break
+		}
+		text += fmt.Sprintf("\n#%d %s · %s · %s\nпользователь %d · %s\n", order.ID, order.Status.Title(),
+			truncateRunes(order.Title, 40), order.PriceText(), order.UserID, order.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return truncateRunes(text+"\nПодробнее: /orders ID", 4000)
+}
+
+// orderText описывает заказ для администратора
+func orderText(order domain.Order) string {
+	text := fmt.Sprintf("🧾 Заказ #%d\n\n%s\n%s\nСумма: %s\nПользователь: %d\nСоздан: %s",
+		order.ID, order.Status.Title(), order.Title, order.PriceText(), order.UserID, order.CreatedAt.Format("2006-01-02 15:04"))
+
+	if order.Status != domain.OrderPending {
+		text += fmt.Sprintf("\nОплачен: %s\nПлатёж Telegram: %s", order.PaidAt.Format("2006-01-02 15:04"), order.TelegramChargeID)
+		if order.ProviderChargeID != "" {
+			text += "\nПлатёж провайдера: " + order.ProviderChargeID
+		}
+	}
+	if order.Status == domain.OrderRefunded {
+		text += fmt.Sprintf("\nВозвращён: %s (сотрудником %d)", order.RefundedAt.Format("2006-01-02 15:04"), order.RefundedBy)
+	}
+	switch order.Status {
+	case domain.OrderPaid:
+		text += fmt.Sprintf("\n\nВернуть оплату: /orders %d refund", order.ID)
+	case domain.OrderRefunding:
+		text += fmt.Sprintf("\n\n⚠️ Возврат не завершён (начал сотрудник %d). Повторить: /orders %d refund", order.RefundedBy, order.ID)
+	}
+	return text
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// Ограничения Telegram на счёт
+const (
+	maxInvoiceTitle       = 32
+	maxInvoiceDescription = 255
+)
+
+// paymentsUnavailableText — ответ, когда для валюты товара не настроен платёжный провайдер
+const paymentsUnavailableText = "Оплата временно недоступна."
+
+// PaymentHandler обрабатывает кнопки покупки (pay_*), проверку перед оплатой и успешные платежи
+// Счета в Telegram Stars (XTR) выставляются без провайдера, в остальных валютах нужен токен провайдера
+type PaymentHandler struct {
+	payments      *service.PaymentService
+	courses       *service.CourseService
+	subscriptions *service.SubscriptionService
+	settings      *repository.SettingsRepository
+	providerToken string
+}
+
+// NewPaymentHandler создаёт новый обработчик платежей
+// providerToken - токен платёжного провайдера от @BotFather (пустой — только оплата в Stars)
+func NewPaymentHandler(payments *service.PaymentService, courses *service.CourseService, subscriptions *service.SubscriptionService, settings *repository.SettingsRepository, providerToken string) *PaymentHandler {
+	return &PaymentHandler{
+		payments:      payments,
+		courses:       courses,
+		subscriptions: subscriptions,
+		settings:      settings,
+		providerToken: providerToken,
+	}
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *PaymentHandler) Prefix() string {
+	return "pay_"
+}
+
+// HandleCallback обрабатывает покупку курса (pay_course_ID), плана (pay_plan_ID) и экран планов (pay_plans)
+func (h *PaymentHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	data := strings.TrimPrefix(callback.Data, "pay_")
+
+	var (
+		order       domain.Order
+		description string
+		err         error
+	)
+	switch {
+	case data == "plans":
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		reply, err := premiumMessage(h.subscriptions, h.settings, chatID, userID)
+		if err != nil {
+			return err
+		}
+		_, err = bot.Send(reply)
+		return err
+
+	case strings.HasPrefix(data, "course_"):
+		courseID, convErr := strconv.Atoi(strings.TrimPrefix(data, "course_"))
+		if convErr != nil {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+			return err
+		}
+		order, err = h.payments.OrderCourse(userID, courseID)
+		if err == nil {
+			description = h.courseDescription(courseID, userID)
+		}
+
+	case strings.HasPrefix(data, "plan_"):
+		planID := strings.TrimPrefix(data, "plan_")
+		order, err = h.payments.OrderPlan(userID, planID)
+		if err == nil {
+			description = h.planDescription(planID, userID)
+		}
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	switch {
+	case errors.Is(err, service.ErrAlreadyPurchased),
+		errors.Is(err, service.ErrNotForSale),
+		errors.Is(err, repository.ErrCourseNotFound),
+		errors.Is(err, repository.ErrPlanNotFound):
+		_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, "❌ "+err.Error()))
+		return err
+	case err != nil:
+		bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, "❌ Не удалось оформить заказ"))
+		return err
+	}
+
+	if order.Currency != domain.CurrencyStars && h.providerToken == "" {
+		log.Printf("Заказ #%d в валюте %s: не задан PAYMENT_PROVIDER_TOKEN", order.ID, order.Currency)
+		_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, paymentsUnavailableText))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, "💳 Счёт на оплату"))
+	_, err = bot.Send(h.invoice(chatID, order, description))
+	return err
+}
+
+// invoice формирует счёт на оплату заказа
+func (h *PaymentHandler) invoice(chatID int64, order domain.Order, description string) tgbotapi.InvoiceConfig {
+	// Для Stars провайдер не нужен: Telegram ожидает пустой токен
+	token := h.providerToken
+	if order.Currency == domain.CurrencyStars {
+		token = ""
+	}
+
+	title := truncateRunes(order.Title, maxInvoiceTitle-1)
+	if description == "" {
+		description = order.Title
+	}
+	prices := []tgbotapi.LabeledPrice{{Label: title, Amount: int(order.Amount)}}
+
+	invoice := tgbotapi.NewInvoice(chatID, title, truncateRunes(description, maxInvoiceDescription-1),
+		order.Payload(), token, "", order.Currency, prices)
+	// Без явного пустого списка tgbotapi передаёт null, и Telegram отклоняет счёт
+	invoice.SuggestedTipAmounts = []int{}
+	return invoice
+}
+
+// courseDescription возвращает описание курса для счёта на языке пользователя
+func (h *PaymentHandler) courseDescription(courseID int, userID int64) string {
+	course, err := h.courses.Published(courseID)
+	if err != nil {
+		return ""
+	}
+	return course.Description.Get(h.settings.Get(userID).Language)
+}
+
+// planDescription возвращает описание тарифного плана для счёта: срок и возможности
+func (h *PaymentHandler) planDescription(planID string, userID int64) string {
+	plan, err := h.subscriptions.Plan(planID)
+	if err != nil {
+		return ""
+	}
+
+	text := fmt.Sprintf("%s на %d дн.", plan.Title.Get(h.settings.Get(userID).Language), plan.Days)
+	for _, feature := range plan.Features {
+		text += "\n• " + planFeatureTitle(feature)
+	}
+	return text
+}
+
+// HandlePreCheckout отвечает на pre_checkout_query: подтверждает оплату, если заказ действителен
+// Telegram ждёт ответа не дольше 10 секунд, иначе платёж отменяется
+func (h *PaymentHandler) HandlePreCheckout(bot *tgbotapi.BotAPI, query *tgbotapi.PreCheckoutQuery) error {
+	order, err := h.payments.Checkout(query.From.ID, query.InvoicePayload, query.Currency, int64(query.TotalAmount))
+
+	answer := tgbotapi.PreCheckoutConfig{PreCheckoutQueryID: query.ID, OK: err == nil}
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound),
+		errors.Is(err, service.ErrOrderMismatch),
+		errors.Is(err, service.ErrOrderExpired),
+		errors.Is(err, service.ErrOrderNotPending),
+		errors.Is(err, service.ErrAlreadyPurchased),
+		errors.Is(err, service.ErrNotForSale):
+		log.Printf("Оплата заказа %q пользователем %d отклонена: %v", query.InvoicePayload, query.From.ID, err)
+		answer.ErrorMessage = err.Error()
+		err = nil
+	case err != nil:
+		answer.ErrorMessage = "Не удалось проверить заказ, попробуйте позже."
+	default:
+		log.Printf("Пользователь %d оплачивает заказ #%d: %s", query.From.ID, order.ID, order.PriceText())
+	}
+
+	if _, reqErr := bot.Request(answer); reqErr != nil {
+		return reqErr
+	}
+	return err
+}
+
+// HandleSuccessfulPayment зачисляет успешный платёж и сообщает пользователю о выданном доступе
+func (h *PaymentHandler) HandleSuccessfulPayment(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	payment := msg.SuccessfulPayment
+	order, err := h.payments.Complete(service.Payment{
+		UserID:           msg.From.ID,
+		Payload:          payment.InvoicePayload,
+		Currency:         payment.Currency,
+		Amount:           int64(payment.TotalAmount),
+		TelegramChargeID: payment.TelegramPaymentChargeID,
+		ProviderChargeID: payment.ProviderPaymentChargeID,
+	})
+	if err != nil {
+		sendText(bot, msg.Chat.ID, fmt.Sprintf("⚠️ Оплата получена, но выдать доступ не удалось. "+
+			"Напишите в поддержку (/support) и укажите номер платежа: %s", payment.TelegramPaymentChargeID))
+		return fmt.Errorf("ошибка зачисления платежа %s: %w", payment.TelegramPaymentChargeID, err)
+	}
+
+	lang := h.settings.Get(msg.From.ID).Language
+	var text domain.LocalizedText
+	if order.Kind == domain.OrderCourse {
+		text = domain.LocalizedText{
+			"ru": fmt.Sprintf("✅ Оплата получена! Курс «%s» открыт: /mycourses", order.Title),
+			"en": fmt.Sprintf("✅ Payment received! The course “%s” is unlocked: /mycourses", order.Title),
+			"zh": fmt.Sprintf("✅ 付款成功！课程“%s”已解锁：/mycourses", order.Title),
+		}
+	} else {
+		text = domain.LocalizedText{
+			"ru": "✅ Оплата получена! Подписка оформлена: /premium",
+			"en": "✅ Payment received! Your subscription is active: /premium",
+			"zh": "✅ 付款成功！订阅已开通：/premium",
+		}
+	}
+	return sendText(bot, msg.Chat.ID, text.Get(lang))
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// quizFormatHelp описывает формат теста для команды /quiz
+const quizFormatHelp = "Формат: <code>/quiz ID_урока</code>, затем с новой строки:\n" +
+	"<code>mode: buttons</code> или <code>poll</code> (опросы Telegram)\n" +
+	"<code>pass: 70</code> — проходной балл, %\n" +
+	"<code>attempts: 3</code> — лимит попыток (0 — без ограничений)\n" +
+	"<code>? Вопрос</code>\n" +
+	"<code>+ правильный вариант</code>\n" +
+	"<code>- неправильный вариант</code>\n" +
+	"<code>! пояснение после ответа</code>\n\n" +
+	"Несколько «+» — вопрос с несколькими ответами.\n" +
+	"Удалить тест: <code>/quiz ID_урока delete</code>\n" +
+	"Вернуть попытки: <code>/quiz ID_урока attempts ID_или_@username [N]</code> (без N — все)"
+
+// QuizAdminHandler обрабатывает команду /quiz — просмотр, создание и удаление тестов уроков,
+// а также возврат попыток пользователям
+type QuizAdminHandler struct {
+	quizzes *service.QuizService
+	lessons *service.LessonService
+	users   *repository.UserRepository
+}
+
+// NewQuizAdminHandler создаёт новый обработчик команды /quiz
+func NewQuizAdminHandler(quizzes *service.QuizService, lessons *service.LessonService, users *repository.UserRepository) *QuizAdminHandler {
+	return &QuizAdminHandler{quizzes: quizzes, lessons: lessons, users: users}
+}
+
+// Command возвращает команду
+func (h *QuizAdminHandler) Command() string {
+	return "quiz"
+}
+
+// Permission возвращает разрешение, необходимое для управления тестами
+func (h *QuizAdminHandler) Permission() domain.Permission {
+	return domain.PermCourses
+}
+
+// Handle обрабатывает команду /quiz ID_урока [delete | attempts пользователь [N] | описание теста]
+func (h *QuizAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+	header, body, _ := strings.Cut(msg.CommandArguments(), "\n")
+	args := strings.Fields(header)
+	if len(args) == 0 {
+		return sendHTML(bot, chatID, quizFormatHelp)
+	}
+
+	lessonID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return sendHTML(bot, chatID, "❌ Укажите ID урока.\n\n"+quizFormatHelp)
+	}
+	lesson, err := h.lessons.Get(lessonID)
+	if errors.Is(err, repository.ErrLessonNotFound) {
+		return sendText(bot, chatID, "❌ Урок не найден.")
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 1 && args[1] == "delete" {
+		err := h.quizzes.Delete(msg.From.ID, lessonID)
+		if errors.Is(err, service.ErrQuizNotFound) {
+			return sendText(bot, chatID, "❌ У урока нет теста.")
+		}
+		if err != nil {
+			sendText(bot, chatID, "❌ Не удалось удалить тест.")
+			return err
+		}
+		return sendText(bot, chatID, "🗑 Тест удалён.")
+	}
+
+	if len(args) > 1 && args[1] == "attempts" {
+		return h.returnAttempts(bot, msg.From.ID, chatID, lessonID, args[2:])
+	}
+
+	if strings.TrimSpace(body) == "" {
+		quiz, exists := h.quizzes.ForLesson(lessonID)
+		if !exists {
+			return sendHTML(bot, chatID, fmt.Sprintf("У урока «%s» нет теста.\n\n%s",
+				lesson.Title.Get(domain.DefaultLanguage), quizFormatHelp))
+		}
+		return sendText(bot, chatID, quizAdminText(lesson, quiz))
+	}
+
+	quiz, err := parseQuiz(body)
+	if err == nil {
+		quiz.LessonID = lessonID
+		err = h.quizzes.Save(msg.From.ID, &quiz)
+	}
+	if errors.Is(err, service.ErrQuizInvalid) {
+		return sendText(bot, chatID, "❌ "+err.Error())
+	}
+	if err != nil {
+		sendText(bot, chatID, "❌ Не удалось сохранить тест.")
+		return err
+	}
+	return sendText(bot, chatID, "✅ Тест сохранён.\n\n"+quizAdminText(lesson, quiz))
+}
+
+// returnAttempts возвращает пользователю попытки теста: /quiz ID_урока attempts пользователь [N]
+func (h *QuizAdminHandler) returnAttempts(bot *tgbotapi.BotAPI, actorID, chatID int64, lessonID int, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return sendHTML(bot, chatID, "❌ Использование: <code>/quiz ID_урока attempts ID_или_@username [N]</code>")
+	}
+	userID, ok := resolveUserID(h.users, args[0])
+	if !ok {
+		return sendText(bot, chatID, fmt.Sprintf("❌ Пользователь «%s» не найден.", args[0]))
+	}
+	n := 0
+	if len(args) == 2 {
+		var err error
+		if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+			return sendText(bot, chatID, "❌ Число попыток должно быть положительным.")
+		}
+	}
+
+	result, err := h.quizzes.ReturnAttempts(actorID, userID, lessonID, n)
+	switch {
+	case errors.Is(err, service.ErrQuizNotFound):
+		return sendText(bot, chatID, "❌ У урока нет теста.")
+	case errors.Is(err, service.ErrNotEnrolled):
+		return sendText(bot, chatID, "❌ Пользователь не записан на курс этого урока.")
+	case errors.Is(err, service.ErrQuizInvalid):
+		return sendText(bot, chatID, "❌ "+err.Error())
+	case err != nil:
+		sendText(bot, chatID, "❌ Не удалось вернуть попытки.")
+		return err
+	}
+	return sendText(bot, chatID, fmt.Sprintf("✅ Попытки возвращены. Пользователь %d использовал попыток: %d.", userID, result.Attempts))
+}
+
+// parseQuiz разбирает описание теста в формате quizFormatHelp
+func parseQuiz(text string) (domain.Quiz, error) {
+	quiz := domain.Quiz{
+		Mode:        domain.QuizButtons,
+		PassPercent: 70,
+		MaxAttempts: 3,
+	}
+
+	var question *domain.QuizQuestion
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if key, value, found := strings.Cut(line, ":"); found && question == nil {
+			value = strings.TrimSpace(value)
+			var err error
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "mode":
+				quiz.Mode = domain.QuizMode(value)
+			case "pass":
+				quiz.PassPercent, err = strconv.Atoi(strings.TrimSuffix(value, "%"))
+			case "attempts":
+				quiz.MaxAttempts, err = strconv.Atoi(value)
+			default:
+				err = errors.New("неизвестный параметр")
+			}
+			if err != nil {
+				return domain.Quiz{}, fmt.Errorf("%w: строка %d: %s", service.ErrQuizInvalid, n+1, line)
+			}
+			continue
+		}
+
+		marker, value := line[:1], strings.TrimSpace(line[1:])
+		switch {
+		case marker == "?":
+			quiz.Questions = append(quiz.Questions, domain.QuizQuestion{Text: value})
+			question = &quiz.Questions[len(quiz.Questions)-1]
+		case question == nil:
+			return domain.Quiz{}, fmt.Errorf("%w: строка %d: сначала нужен вопрос «? …»", service.ErrQuizInvalid, n+1)
+		case marker == "+":
+			question.Correct = append(question.Correct, len(question.Options))
+			question.Options = append(question.Options, value)
+		case marker == "-":
+			question.Options = append(question.Options, value)
+		case marker == "!":
+			question.Explanation = value
+		default:
+			return domain.Quiz{}, fmt.Errorf("%w: строка %d: ожидается «?», «+», «-» или «!»", service.ErrQuizInvalid, n+1)
+		}
+	}
+	return quiz, nil
+}
+
+// quizAdminText формирует описание теста для администратора
+func quizAdminText(lesson domain.Lesson, quiz domain.Quiz) string {
+	attempts := "без ограничений"
+	if quiz.MaxAttempts > 0 {
+		attempts = strconv.Itoa(quiz.MaxAttempts)
+	}
+
+	text := fmt.Sprintf("📝 Тест урока #%d «%s»\n\nРежим: %s\nПроходной балл: %d%%\nПопыток: %s\nВопросов: %d\n",
+		lesson.ID, lesson.Title.Get(domain.DefaultLanguage), quiz.Mode, quiz.PassPercent, attempts, len(quiz.Questions))
+
+	for i, question := range quiz.Questions {
+		text += fmt.Sprintf("\n%d. %s\n", i+1, question.Text)
+		for j, option := range question.Options {
+			mark := "  "
+			if slices.Contains(question.Correct, j) {
+				mark = "✓ "
+			}
+			text += fmt.Sprintf("%s%s. %s\n", mark, keyboard.QuizOptionLetter(j), option)
+		}
+	}
+	return truncateRunes(text, 4000)
+}
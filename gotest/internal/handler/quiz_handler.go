@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// QuizHandler проводит тесты в конце уроков (кнопки qz_* и ответы в опросах)
+type QuizHandler struct {
+	quizzes      *service.QuizService
+	certificates *service.CertificateService
+	features     *service.FeatureService
+	settings     *repository.SettingsRepository
+}
+
+// NewQuizHandler создаёт новый обработчик тестов
+func NewQuizHandler(quizzes *service.QuizService, certificates *service.CertificateService, features *service.FeatureService, settings *repository.SettingsRepository) *QuizHandler {
+	return &QuizHandler{
+		quizzes:      quizzes,
+		certificates: certificates,
+		features:     features,
+		settings:     settings,
+	}
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *QuizHandler) Prefix() string {
+	return "qz_"
+}
+
+// HandleCallback обрабатывает начало теста, ответы на вопросы и экран результатов
+// Формат: qz_start_<курс>_<урок>, qz_res_<курс>_<урок>, qz_ans_<вопрос>_<вариант>,
+// qz_tog_<вопрос>_<вариант>, qz_sub_<вопрос>, qz_stop
+func (h *QuizHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	userID := callback.From.ID
+	if !h.features.Enabled(domain.FeatureCourseEnrollment, userID) {
+		_, err := bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, enrollmentDisabledText))
+		return err
+	}
+
+	if callback.Data == "qz_stop" {
+		h.quizzes.Cancel(userID)
+		bot.Request(tgbotapi.NewCallback(callback.ID, "Тест прерван"))
+		edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID,
+			"✖️ Тест прерван. Результат попытки не сохранён — начните тест заново из урока.")
+		_, err := bot.Send(edit)
+		return err
+	}
+
+	parts := strings.Split(strings.TrimPrefix(callback.Data, "qz_"), "_")
+	action := parts[0]
+	var args []int
+	for _, part := range parts[1:] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+			return err
+		}
+		args = append(args, n)
+	}
+	if len(args) == 0 || (action != "sub" && len(args) < 2) {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	switch action {
+	case "start":
+		return h.start(bot, callback, args[0], args[1])
+
+	case "res":
+		return h.showResult(bot, callback, args[1])
+
+	case "tog":
+		session, err := h.quizzes.Toggle(userID, args[0], args[1])
+		if err != nil {
+			return answerQuizError(bot, callback, err)
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageReplyMarkup(callback.Message.Chat.ID, callback.Message.MessageID,
+			keyboard.NewQuizQuestionKeyboard(session))
+		_, err = bot.Send(edit)
+		return err
+
+	case "ans", "sub":
+		var options []int // nil — ответ из отмеченных вариантов
+		if action == "ans" {
+			options = []int{args[1]}
+		}
+		outcome, err := h.quizzes.Answer(userID, args[0], options)
+		if err != nil {
+			return answerQuizError(bot, callback, err)
+		}
+		bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, quizFeedbackText(outcome)))
+		return h.next(bot, callback.Message.Chat.ID, callback.Message.MessageID, callback.From, outcome)
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+}
+
+// HandlePollAnswer принимает ответ на вопрос теста в опросе Telegram
+func (h *QuizHandler) HandlePollAnswer(bot *tgbotapi.BotAPI, answer *tgbotapi.PollAnswer) (bool, error) {
+	userID := answer.User.ID
+	outcome, handled, err := h.quizzes.AnswerPoll(answer.PollID, userID, answer.OptionIDs)
+	if !handled || errors.Is(err, service.ErrQuizNotStarted) {
+		return handled, nil // Ответ на опрос прерванного теста
+	}
+	if err != nil {
+		return true, err
+	}
+
+	// В викторине Telegram сам показывает правильный ответ, в опросе с несколькими ответами — нет
+	if outcome.Question.Multiple() {
+		if err := sendText(bot, outcome.Session.ChatID, quizFeedbackText(outcome)); err != nil {
+			return true, err
+		}
+	}
+	return true, h.next(bot, outcome.Session.ChatID, 0, &answer.User, outcome)
+}
+
+// start начинает попытку и показывает первый вопрос
+func (h *QuizHandler) start(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, courseID, lessonID int) error {
+	chatID := callback.Message.Chat.ID
+	session, err := h.quizzes.Start(callback.From.ID, chatID, courseID, lessonID)
+	if err != nil {
+		return answerQuizError(bot, callback, err)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	quiz := session.Quiz
+	text := fmt.Sprintf("📝 Тест: %d вопр. Проходной балл — %d%%.", len(quiz.Questions), quiz.PassPercent)
+	if left := h.quizzes.Result(callback.From.ID, quiz).AttemptsLeft(quiz); left > 0 {
+		text += fmt.Sprintf("\nОсталось попыток: %d.", left)
+	}
+
+	if quiz.Mode == domain.QuizPoll {
+		reply := tgbotapi.NewMessage(chatID, text+"\n\nОтвечайте в опросах ниже.")
+		reply.ReplyMarkup = keyboard.NewQuizStopKeyboard()
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+		return h.sendPoll(bot, callback.From.ID, session)
+	}
+
+	if err := sendText(bot, chatID, text); err != nil {
+		return err
+	}
+	reply := tgbotapi.NewMessage(chatID, quizQuestionText(session))
+	reply.ReplyMarkup = keyboard.NewQuizQuestionKeyboard(session)
+	_, err = bot.Send(reply)
+	return err
+}
+
+// next показывает следующий вопрос или результаты попытки
+// messageID — сообщение с вопросом в режиме кнопок (0 — отправить новое сообщение)
+func (h *QuizHandler) next(bot *tgbotapi.BotAPI, chatID int64, messageID int, user *tgbotapi.User, outcome service.QuizOutcome) error {
+	session := outcome.Session
+	if !outcome.Finished {
+		if session.Quiz.Mode == domain.QuizPoll {
+			return h.sendPoll(bot, user.ID, session)
+		}
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, quizQuestionText(session))
+		kb := keyboard.NewQuizQuestionKeyboard(session)
+		edit.ReplyMarkup = &kb
+		_, err := bot.Send(edit)
+		return err
+	}
+
+	text := fmt.Sprintf("Правильных ответов: %d из %d (%d%%)\n\n", session.Correct, len(session.Quiz.Questions), session.Score()) +
+		quizResultText(session.Quiz, outcome.Result)
+	kb := keyboard.NewQuizResultKeyboard(session.Quiz, outcome.Result)
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ReplyMarkup = kb
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+	} else {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		edit.ReplyMarkup = &kb
+		if _, err := bot.Send(edit); err != nil {
+			return err
+		}
+	}
+
+	if !outcome.CourseCompleted {
+		return nil
+	}
+	return sendCourseCompleted(bot, chatID, user, outcome.Progress, h.settings.Get(user.ID).Language, h.certificates)
+}
+
+// sendPoll отправляет текущий вопрос попытки опросом Telegram
+// Вопрос с одним ответом — викторина, с несколькими — опрос с выбором нескольких вариантов
+func (h *QuizHandler) sendPoll(bot *tgbotapi.BotAPI, userID int64, session domain.QuizSession) error {
+	question := session.Quiz.Questions[session.Question]
+
+	poll := tgbotapi.NewPoll(session.ChatID, question.Text, question.Options...)
+	poll.IsAnonymous = false // Иначе Telegram не присылает ответы
+	if question.Multiple() {
+		poll.AllowsMultipleAnswers = true
+	} else {
+		poll.Type = "quiz"
+		poll.CorrectOptionID = int64(question.Correct[0])
+		poll.Explanation = question.Explanation
+	}
+
+	sent, err := bot.Send(poll)
+	if err != nil {
+		return err
+	}
+	h.quizzes.BindPoll(sent.Poll.ID, userID, session.Question)
+	return nil
+}
+
+// showResult показывает результаты пользователя по тесту урока
+func (h *QuizHandler) showResult(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, lessonID int) error {
+	quiz, exists := h.quizzes.ForLesson(lessonID)
+	if !exists {
+		return answerQuizError(bot, callback, service.ErrQuizNotFound)
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+
+	result := h.quizzes.Result(callback.From.ID, quiz)
+	reply := tgbotapi.NewMessage(callback.Message.Chat.ID, quizResultText(quiz, result))
+	reply.ReplyMarkup = keyboard.NewQuizResultKeyboard(quiz, result)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// answerQuizError отвечает на callback понятной ошибкой теста
+func answerQuizError(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, err error) error {
+	switch {
+	case errors.Is(err, service.ErrQuizNotFound),
+		errors.Is(err, service.ErrQuizNoAttempts),
+		errors.Is(err, service.ErrQuizNotStarted):
+		_, reqErr := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ "+err.Error()))
+		return reqErr
+	default:
+		return answerEnrollmentError(bot, callback, err)
+	}
+}
+
+// quizQuestionText формирует текст текущего вопроса попытки
+func quizQuestionText(session domain.QuizSession) string {
+	question := session.Quiz.Questions[session.Question]
+
+	text := fmt.Sprintf("📝 Вопрос %d из %d\n\n%s\n", session.Question+1, len(session.Quiz.Questions), question.Text)
+	for i, option := range question.Options {
+		text += fmt.Sprintf("\n%s. %s", keyboard.QuizOptionLetter(i), option)
+	}
+	if question.Multiple() {
+		text += "\n\nОтметьте все правильные варианты и нажмите «Ответить»."
+	}
+	return text
+}
+
+// quizFeedbackText формирует ответ на вопрос: верно или нет, правильные варианты и пояснение
+func quizFeedbackText(outcome service.QuizOutcome) string {
+	text := "✅ Верно!"
+	if !outcome.Correct {
+		letters := make([]string, len(outcome.Question.Correct))
+		for i, option := range outcome.Question.Correct {
+			letters[i] = keyboard.QuizOptionLetter(option)
+		}
+		text = fmt.Sprintf("❌ Неверно. Правильный ответ: %s", strings.Join(letters, ", "))
+	}
+	if outcome.Question.Explanation != "" {
+		text += "\n\n" + outcome.Question.Explanation
+	}
+	return truncateRunes(text, 199) // Лимит текста уведомления на callback — 200 символов
+}
+
+// quizResultText формирует экран результатов пользователя по тесту
+func quizResultText(quiz domain.Quiz, result domain.QuizResult) string {
+	if result.Attempts == 0 {
+		return "📊 Тест ещё не пройден."
+	}
+
+	attempts := strconv.Itoa(result.Attempts)
+	if quiz.MaxAttempts > 0 {
+		attempts += fmt.Sprintf(" из %d", quiz.MaxAttempts)
+	}
+	text := fmt.Sprintf("📊 Результаты теста\n\nПоследняя попытка: %d%%\nЛучший результат: %d%%\nПроходной балл: %d%%\nПопыток: %s\n\n",
+		result.LastScore, result.BestScore, quiz.PassPercent, attempts)
+
+	switch left := result.AttemptsLeft(quiz); {
+	case result.Passed:
+		text += "✅ Тест пройден — урок засчитан."
+	case left == 0:
+		text += "❌ Тест не пройден, попытки закончились — урок не засчитан. Если нужна ещё попытка, напишите в поддержку: /support"
+	case left > 0:
+		text += fmt.Sprintf("❌ Тест не пройден. Осталось попыток: %d.", left)
+	default:
+		text += "❌ Тест не пройден. Попробуйте ещё раз."
+	}
+	return text
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/service"
+)
+
+// GrantRoleHandler обрабатывает команду /grant <user_id> <роль>
+type GrantRoleHandler struct {
+	access *service.AccessService
+}
+
+// NewGrantRoleHandler создаёт новый обработчик команды /grant
+func NewGrantRoleHandler(access *service.AccessService) *GrantRoleHandler {
+	return &GrantRoleHandler{access: access}
+}
+
+// Command возвращает команду
+func (h *GrantRoleHandler) Command() string {
+	return "grant"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *GrantRoleHandler) Permission() domain.Permission {
+	return domain.PermRolesManage
+}
+
+// Handle обрабатывает команду /grant
+func (h *GrantRoleHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) != 2 {
+		return sendText(bot, msg.Chat.ID,
+			"❌ Использование: /grant <user_id> <роль>\n"+
+				"Роли: admin, moderator, support, user")
+	}
+
+	targetID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return sendText(bot, msg.Chat.ID, "❌ Неверный ID пользователя.")
+	}
+
+	role, ok := domain.ParseRole(args[1])
+	if !ok {
+		return sendText(bot, msg.Chat.ID, "❌ Неизвестная роль. Роли: admin, moderator, support, user")
+	}
+
+	if err := h.access.Grant(msg.From.ID, targetID, role); err != nil {
+		return sendRoleError(bot, msg.Chat.ID, err)
+	}
+
+	return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Пользователю %d выдана роль %s.", targetID, role))
+}
+
+// RevokeRoleHandler обрабатывает команду /revoke <user_id>
+type RevokeRoleHandler struct {
+	access *service.AccessService
+}
+
+// NewRevokeRoleHandler создаёт новый обработчик команды /revoke
+func NewRevokeRoleHandler(access *service.AccessService) *RevokeRoleHandler {
+	return &RevokeRoleHandler{access: access}
+}
+
+// Command возвращает команду
+func (h *RevokeRoleHandler) Command() string {
+	return "revoke"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *RevokeRoleHandler) Permission() domain.Permission {
+	return domain.PermRolesManage
+}
+
+// Handle обрабатывает команду /revoke
+func (h *RevokeRoleHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	targetID, err := strconv.ParseInt(strings.TrimSpace(msg.CommandArguments()), 10, 64)
+	if err != nil {
+		return sendText(bot, msg.Chat.ID, "❌ Использование: /revoke <user_id>")
+	}
+
+	if err := h.access.Revoke(msg.From.ID, targetID); err != nil {
+		return sendRoleError(bot, msg.Chat.ID, err)
+	}
+
+	return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Роль пользователя %d отозвана.", targetID))
+}
+
+// RolesHandler обрабатывает команду /roles — список сотрудников с ролями
+type RolesHandler struct {
+	access *service.AccessService
+}
+
+// NewRolesHandler создаёт новый обработчик команды /roles
+func NewRolesHandler(access *service.AccessService) *RolesHandler {
+	return &RolesHandler{access: access}
+}
+
+// Command возвращает команду
+func (h *RolesHandler) Command() string {
+	return "roles"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *RolesHandler) Permission() domain.Permission {
+	return domain.PermRolesView
+}
+
+// Handle обрабатывает команду /roles
+func (h *RolesHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	staff := h.access.Staff()
+
+	// Сортируем: сначала старшие роли, внутри роли — по ID
+	ids := make([]int64, 0, len(staff))
+	for id := range staff {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if staff[ids[i]] != staff[ids[j]] {
+			return staff[ids[i]].Level() > staff[ids[j]].Level()
+		}
+		return ids[i] < ids[j]
+	})
+
+	text := "<b>👥 Роли пользователей:</b>\n\n"
+	for _, id := range ids {
+		text += fmt.Sprintf("<code>%d</code> — %s\n", id, staff[id])
+	}
+	if len(ids) == 0 {
+		text += "Ролей пока нет."
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ParseMode = tgbotapi.ModeHTML
+	_, err := bot.Send(reply)
+	return err
+}
+
+// sendRoleError отправляет понятное сообщение об ошибке изменения роли
+func sendRoleError(bot *tgbotapi.BotAPI, chatID int64, err error) error {
+	switch {
+	case errors.Is(err, service.ErrOwnerRole):
+		return sendText(bot, chatID, "❌ Роль владельца задаётся только через ADMIN_IDS.")
+	case errors.Is(err, service.ErrForbidden):
+		return sendText(bot, chatID, "❌ Можно управлять только ролями ниже вашей.")
+	default:
+		return err
+	}
+}
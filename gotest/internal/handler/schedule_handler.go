@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/service"
+)
+
+// ScheduleHandler обрабатывает команду /schedules и кнопки управления запланированными рассылками (sch_*)
+type ScheduleHandler struct {
+	scheduler *service.SchedulerService
+}
+
+// NewScheduleHandler создаёт новый обработчик запланированных рассылок
+func NewScheduleHandler(scheduler *service.SchedulerService) *ScheduleHandler {
+	return &ScheduleHandler{scheduler: scheduler}
+}
+
+// Command возвращает команду
+func (h *ScheduleHandler) Command() string {
+	return "schedules"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *ScheduleHandler) Prefix() string {
+	return "sch_"
+}
+
+// Permission возвращает разрешение, необходимое для управления рассылками
+func (h *ScheduleHandler) Permission() domain.Permission {
+	return domain.PermBroadcast
+}
+
+// Handle обрабатывает команду /schedules — показывает список запланированных рассылок
+func (h *ScheduleHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	jobs := h.scheduler.List()
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, schedulesText(jobs))
+	reply.ParseMode = tgbotapi.ModeHTML
+	reply.ReplyMarkup = keyboard.NewSchedulesKeyboard(jobs)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// HandleCallback обрабатывает кнопки паузы, возобновления и отмены
+func (h *ScheduleHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	data := callback.Data
+	callbackText := ""
+
+	if data != "sch_list" {
+		action, idStr, _ := strings.Cut(strings.TrimPrefix(data, "sch_"), "_")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+			return err
+		}
+
+		switch action {
+		case "pause":
+			err = h.scheduler.Pause(callback.From.ID, id)
+			callbackText = fmt.Sprintf("⏸ Рассылка #%d приостановлена", id)
+		case "resume":
+			err = h.scheduler.Resume(callback.From.ID, id)
+			callbackText = fmt.Sprintf("▶️ Рассылка #%d возобновлена", id)
+		case "cancel":
+			err = h.scheduler.Cancel(callback.From.ID, id)
+			callbackText = fmt.Sprintf("🗑 Рассылка #%d отменена", id)
+		default:
+			err = service.ErrScheduleNotFound
+		}
+
+		if errors.Is(err, service.ErrScheduleNotFound) {
+			callbackText = "❌ Рассылка не найдена или уже завершена"
+		} else if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, callbackText))
+
+	jobs := h.scheduler.List()
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, schedulesText(jobs))
+	edit.ParseMode = tgbotapi.ModeHTML
+	kb := keyboard.NewSchedulesKeyboard(jobs)
+	edit.ReplyMarkup = &kb
+	_, err := bot.Send(edit)
+	return err
+}
+
+// schedulesText формирует список запланированных рассылок
+func schedulesText(jobs []domain.ScheduledBroadcast) string {
+	text := "<b>🕒 Запланированные рассылки</b>\n\n"
+	if len(jobs) == 0 {
+		return text + "Запланированных рассылок нет.\nЗапланировать можно при создании рассылки: /broadcast"
+	}
+
+	for _, job := range jobs {
+		status := "активна"
+		if job.Status == domain.SchedulePaused {
+			status = "⏸ на паузе"
+		}
+
+		schedule := "разово"
+		if job.Recurring() {
+			schedule = fmt.Sprintf("cron <code>%s</code>", job.Cron)
+		}
+
+		text += fmt.Sprintf("<b>#%d</b> — %s, %s\n", job.ID, schedule, status)
+		text += fmt.Sprintf("Аудитория: %s\n", segmentTitle(job.Segment))
+		text += fmt.Sprintf("Следующий запуск: %s (%s)\n",
+			job.NextRunAt.In(scheduleLocation(job)).Format("2006-01-02 15:04"), job.TimeZone)
+		if job.Runs > 0 {
+			text += fmt.Sprintf("Запусков: %d\n", job.Runs)
+		}
+		text += "\n"
+	}
+	return text
+}
+
+// scheduleLocation возвращает часовой пояс рассылки (UTC, если пояс неизвестен)
+func scheduleLocation(job domain.ScheduledBroadcast) *time.Location {
+	location, err := time.LoadLocation(job.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// SubscriptionAdminHandler обрабатывает команду /subscription — ручная выдача, продление и отзыв подписок
+type SubscriptionAdminHandler struct {
+	subscriptions *service.SubscriptionService
+	users         *repository.UserRepository
+	settings      *repository.SettingsRepository
+}
+
+// NewSubscriptionAdminHandler создаёт новый обработчик команды /subscription
+func NewSubscriptionAdminHandler(subscriptions *service.SubscriptionService, users *repository.UserRepository, settings *repository.SettingsRepository) *SubscriptionAdminHandler {
+	return &SubscriptionAdminHandler{subscriptions: subscriptions, users: users, settings: settings}
+}
+
+// Command возвращает команду
+func (h *SubscriptionAdminHandler) Command() string {
+	return "subscription"
+}
+
+// Permission возвращает разрешение, необходимое для команды
+func (h *SubscriptionAdminHandler) Permission() domain.Permission {
+	return domain.PermSubscriptions
+}
+
+// Handle обрабатывает команду /subscription <пользователь> [grant <план> [дней] | extend <дней> | revoke]
+func (h *SubscriptionAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	chatID := msg.Chat.ID
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		return h.sendUsage(bot, chatID)
+	}
+
+	userID, ok := resolveUserID(h.users, args[0])
+	if !ok {
+		return sendText(bot, chatID, fmt.Sprintf("❌ Пользователь «%s» не найден.", args[0]))
+	}
+	if len(args) == 1 {
+		return sendText(bot, chatID, h.statusText(userID))
+	}
+
+	actorID := msg.From.ID
+	var err error
+	switch {
+	case args[1] == "grant" && (len(args) == 3 || len(args) == 4):
+		days := 0
+		if len(args) == 4 {
+			if days, err = strconv.Atoi(args[3]); err != nil {
+				return h.sendUsage(bot, chatID)
+			}
+		}
+		_, err = h.subscriptions.Grant(actorID, userID, args[2], days)
+
+	case args[1] == "extend" && len(args) == 3:
+		days, convErr := strconv.Atoi(args[2])
+		if convErr != nil {
+			return h.sendUsage(bot, chatID)
+		}
+		_, err = h.subscriptions.Extend(actorID, userID, days)
+
+	case args[1] == "revoke" && len(args) == 2:
+		err = h.subscriptions.Revoke(actorID, userID)
+
+	default:
+		return h.sendUsage(bot, chatID)
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrPlanNotFound),
+		errors.Is(err, service.ErrNoSubscription),
+		errors.Is(err, service.ErrInvalidDays):
+		return sendText(bot, chatID, "❌ "+err.Error())
+	case err != nil:
+		sendText(bot, chatID, "❌ Не удалось изменить подписку.")
+		return err
+	}
+
+	if args[1] != "revoke" {
+		h.notify(bot, userID)
+	}
+	return sendText(bot, chatID, "✅ Подписка обновлена.\n\n"+h.statusText(userID))
+}
+
+// notify сообщает пользователю о выданной или продлённой подписке
+func (h *SubscriptionAdminHandler) notify(bot *tgbotapi.BotAPI, userID int64) {
+	subscription, plan, _ := h.subscriptions.Get(userID)
+	lang := h.settings.Get(userID).Language
+	text := domain.LocalizedText{
+		"ru": fmt.Sprintf("💎 Вам выдана подписка «%s» до %s. Подробнее: /premium", plan.Title.Get(lang), subscription.ExpiresAt.Format("2006-01-02")),
+		"en": fmt.Sprintf("💎 You've been granted the “%s” subscription until %s. Details: /premium", plan.Title.Get(lang), subscription.ExpiresAt.Format("2006-01-02")),
+		"zh": fmt.Sprintf("💎 您已获得“%s”订阅，有效期至 %s。详情：/premium", plan.Title.Get(lang), subscription.ExpiresAt.Format("2006-01-02")),
+	}
+	if err := sendText(bot, userID, text.Get(lang)); err != nil {
+		log.Printf("Ошибка уведомления пользователя %d о подписке: %v", userID, err)
+	}
+}
+
+// statusText описывает подписку пользователя для администратора
+func (h *SubscriptionAdminHandler) statusText(userID int64) string {
+	text := fmt.Sprintf("💎 Подписка пользователя %d\n\n", userID)
+
+	subscription, plan, exists := h.subscriptions.Get(userID)
+	if !exists {
+		return text + "Подписки нет."
+	}
+
+	text += subscriptionStatusText(subscription, plan, domain.DefaultLanguage, time.Now())
+	text += fmt.Sprintf("\n\nОформлена: %s", subscription.StartedAt.Format("2006-01-02 15:04"))
+	if subscription.GrantedBy != 0 {
+		text += fmt.Sprintf(" (выдана сотрудником %d)", subscription.GrantedBy)
+	}
+	return text
+}
+
+// sendUsage отправляет подсказку по команде /subscription
+func (h *SubscriptionAdminHandler) sendUsage(bot *tgbotapi.BotAPI, chatID int64) error {
+	var plans []string
+	for _, plan := range h.subscriptions.Plans(false) {
+		plans = append(plans, fmt.Sprintf("%s (%d дн.)", plan.ID, plan.Days))
+	}
+
+	return sendText(bot, chatID,
+		"❌ Использование:\n"+
+			"/subscription <ID или @username> — подписка пользователя\n"+
+			"/subscription <пользователь> grant <план> [дней] — выдать подписку с сегодняшнего дня\n"+
+			"/subscription <пользователь> extend <дней> — продлить подписку\n"+
+			"/subscription <пользователь> revoke — отозвать подписку\n\n"+
+			"Планы: "+strings.Join(plans, ", "))
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// subscriptionReminderTick — как часто проверяются подписки, о которых пора напомнить
+const subscriptionReminderTick = time.Hour
+
+// SubscriptionHandler обрабатывает команду /premium — подписка пользователя и тарифные планы
+// Также отправляет напоминания об окончании подписок
+type SubscriptionHandler struct {
+	subscriptions *service.SubscriptionService
+	settings      *repository.SettingsRepository
+}
+
+// NewSubscriptionHandler создаёт новый обработчик подписок
+func NewSubscriptionHandler(subscriptions *service.SubscriptionService, settings *repository.SettingsRepository) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptions: subscriptions, settings: settings}
+}
+
+// Command возвращает команду
+func (h *SubscriptionHandler) Command() string {
+	return "premium"
+}
+
+// Handle обрабатывает команду /premium — показывает подписку пользователя и доступные планы
+func (h *SubscriptionHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	reply, err := premiumMessage(h.subscriptions, h.settings, msg.Chat.ID, msg.From.ID)
+	if err != nil {
+		return err
+	}
+	_, err = bot.Send(reply)
+	return err
+}
+
+// premiumMessage формирует экран подписки пользователя userID с кнопками оплаты планов
+func premiumMessage(subscriptions *service.SubscriptionService, settings *repository.SettingsRepository, chatID, userID int64) (tgbotapi.MessageConfig, error) {
+	lang := settings.Get(userID).Language
+
+	text := "💎 Подписка\n\n"
+	subscription, plan, exists := subscriptions.Get(userID)
+	if exists {
+		text += subscriptionStatusText(subscription, plan, lang, time.Now())
+	} else {
+		text += "У вас нет подписки."
+	}
+
+	plans := subscriptions.Plans(true)
+	kb, err := keyboard.NewPlansKeyboard(plans, lang)
+	if err != nil {
+		return tgbotapi.MessageConfig{}, fmt.Errorf("ошибка построения клавиатуры планов: %w", err)
+	}
+
+	reply := tgbotapi.NewMessage(chatID, text+"\n\n"+plansText(plans, lang))
+	if kb != nil {
+		reply.ReplyMarkup = kb
+	}
+	return reply, nil
+}
+
+// StartReminders запускает фоновую отправку напоминаний о скором окончании подписок
+func (h *SubscriptionHandler) StartReminders(bot *tgbotapi.BotAPI) {
+	go func() {
+		h.remind(bot, time.Now())
+		ticker := time.NewTicker(subscriptionReminderTick)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			h.remind(bot, now)
+		}
+	}()
+}
+
+// remind отправляет напоминания, которые пора отправить в момент now
+func (h *SubscriptionHandler) remind(bot *tgbotapi.BotAPI, now time.Time) {
+	for _, reminder := range h.subscriptions.Remind(now) {
+		userID := reminder.Subscription.UserID
+		lang := h.settings.Get(userID).Language
+		if err := sendText(bot, userID, subscriptionReminderText(reminder, lang)); err != nil {
+			log.Printf("Ошибка отправки напоминания о подписке пользователю %d: %v", userID, err)
+		}
+	}
+}
+
+// subscriptionStatusText описывает подписку пользователя
+func subscriptionStatusText(subscription domain.Subscription, plan domain.Plan, lang string, now time.Time) string {
+	if !subscription.Active(now) {
+		return fmt.Sprintf("Подписка «%s» закончилась %s.", plan.Title.Get(lang), subscription.ExpiresAt.Format("2006-01-02"))
+	}
+
+	text := fmt.Sprintf("✅ План «%s» до %s (осталось дней: %d)",
+		plan.Title.Get(lang), subscription.ExpiresAt.Format("2006-01-02"), subscription.DaysLeft(now))
+	for _, feature := range plan.Features {
+		text += "\n• " + planFeatureTitle(feature)
+	}
+	return text
+}
+
+// plansText формирует список тарифных планов
+func plansText(plans []domain.Plan, lang string) string {
+	if len(plans) == 0 {
+		return "Тарифных планов пока нет."
+	}
+
+	text := "Тарифные планы:"
+	for _, plan := range plans {
+		text += fmt.Sprintf("\n\n%s — %s за %d дн.", plan.Title.Get(lang), domain.FormatPrice(plan.Price, plan.Currency), plan.Days)
+		for _, feature := range plan.Features {
+			text += "\n• " + planFeatureTitle(feature)
+		}
+	}
+	return text
+}
+
+// planFeatureTitle возвращает описание возможности плана
+func planFeatureTitle(feature string) string {
+	if title, exists := domain.KnownPlanFeatures[feature]; exists {
+		return title
+	}
+	return feature
+}
+
+// subscriptionReminderText формирует напоминание об окончании подписки на языке lang
+func subscriptionReminderText(reminder service.SubscriptionReminder, lang string) string {
+	title := reminder.Plan.Title.Get(lang)
+	expires := reminder.Subscription.ExpiresAt.Format("2006-01-02")
+
+	var text domain.LocalizedText
+	if reminder.DaysLeft == 0 {
+		text = domain.LocalizedText{
+			"ru": fmt.Sprintf("⌛ Подписка «%s» закончилась. Продлить: /premium", title),
+			"en": fmt.Sprintf("⌛ Your “%s” subscription has expired. Renew: /premium", title),
+			"zh": fmt.Sprintf("⌛ 您的“%s”订阅已到期。续订：/premium", title),
+		}
+	} else {
+		text = domain.LocalizedText{
+			"ru": fmt.Sprintf("⏰ Подписка «%s» закончится %s (осталось дней: %d). Продлить: /premium", title, expires, reminder.DaysLeft),
+			"en": fmt.Sprintf("⏰ Your “%s” subscription expires on %s (%d days left). Renew: /premium", title, expires, reminder.DaysLeft),
+			"zh": fmt.Sprintf("⏰ 您的“%s”订阅将于 %s 到期（剩余 %d 天）。续订：/premium", title, expires, reminder.DaysLeft),
+		}
+	}
+	return text.Get(lang)
+}
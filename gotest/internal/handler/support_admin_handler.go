@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+const (
+	ticketsPerPage      = 8  // Обращений на странице списка
+	ticketPreviewLength = 80 // Длина последнего сообщения в списке обращений
+	ticketHistoryLimit  = 4000
+)
+
+// SupportAdminHandler обрабатывает команду /tickets, кнопки обращений (tkt_*)
+// и ответы сотрудников реплаем на пересланные сообщения пользователей
+type SupportAdminHandler struct {
+	support *service.SupportService
+	relay   *SupportRelay
+	access  *service.AccessService
+	fsm     *FSM
+	mu      sync.Mutex
+	filters map[int64]domain.TicketStatus // Ключ - ID сотрудника; нет ключа — открытые обращения
+}
+
+// NewSupportAdminHandler создаёт новый обработчик обращений для поддержки
+func NewSupportAdminHandler(support *service.SupportService, relay *SupportRelay, access *service.AccessService, fsm *FSM) *SupportAdminHandler {
+	return &SupportAdminHandler{
+		support: support,
+		relay:   relay,
+		access:  access,
+		fsm:     fsm,
+		filters: make(map[int64]domain.TicketStatus),
+	}
+}
+
+// Command возвращает команду
+func (h *SupportAdminHandler) Command() string {
+	return "tickets"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *SupportAdminHandler) Prefix() string {
+	return "tkt_"
+}
+
+// Permission возвращает разрешение, необходимое для команды и кнопок
+func (h *SupportAdminHandler) Permission() domain.Permission {
+	return domain.PermSupport
+}
+
+// Handle обрабатывает команду /tickets [open|pending|closed|all|<номер>]
+func (h *SupportAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	arg := strings.TrimSpace(msg.CommandArguments())
+
+	if id, err := strconv.Atoi(strings.TrimPrefix(arg, "#")); err == nil {
+		ticket, err := h.support.Get(id)
+		if err != nil {
+			return sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Обращение #%d не найдено.", id))
+		}
+		reply := tgbotapi.NewMessage(msg.Chat.ID, ticketCardText(ticket))
+		reply.ReplyMarkup = keyboard.NewTicketKeyboard(ticket, true)
+		_, err = bot.Send(reply)
+		return err
+	}
+
+	status := domain.TicketOpen
+	switch arg {
+	case "", string(domain.TicketOpen):
+	case string(domain.TicketPending), string(domain.TicketClosed):
+		status = domain.TicketStatus(arg)
+	case "all":
+		status = ""
+	default:
+		return sendText(bot, msg.Chat.ID, "Использование: /tickets [open|pending|closed|all] или /tickets <номер>")
+	}
+
+	h.mu.Lock()
+	h.filters[msg.From.ID] = status
+	h.mu.Unlock()
+
+	return h.showList(bot, msg.From.ID, msg.Chat.ID, 0, 0)
+}
+
+// HandleCallback обрабатывает кнопки обращений
+// Формат: tkt_list_p_<страница>, tkt_list_pick_<страница>, tkt_st_<статус|all>,
+// tkt_view_<ID>, tkt_take_<ID>, tkt_close_<ID>, tkt_reopen_<ID>, tkt_hist_<ID>
+func (h *SupportAdminHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	staffID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	action, arg, _ := strings.Cut(strings.TrimPrefix(callback.Data, "tkt_"), "_")
+
+	switch action {
+	case "list":
+		page, picker, ok := ticketsPaginator("").Parse(callback.Data)
+		if !ok {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка навигации"))
+			return err
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		if picker {
+			kb, err := ticketsPaginator("").Picker(keyboard.SliceSource(h.tickets(staffID)), page)
+			if err != nil {
+				return err
+			}
+			_, err = bot.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, kb))
+			return err
+		}
+		return h.showList(bot, staffID, chatID, messageID, page)
+
+	case "st":
+		status := domain.TicketStatus(arg)
+		if arg == "all" {
+			status = ""
+		}
+		h.mu.Lock()
+		h.filters[staffID] = status
+		h.mu.Unlock()
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return h.showList(bot, staffID, chatID, messageID, 0)
+	}
+
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	var ticket domain.Ticket
+	callbackText := ""
+	switch action {
+	case "view":
+		ticket, err = h.support.Get(id)
+
+	case "take":
+		ticket, err = h.support.Assign(staffID, id, staffID)
+		callbackText = "🙋 Обращение назначено на вас"
+
+	case "close":
+		ticket, err = h.support.Close(staffID, id)
+		if err == nil {
+			h.notifyUser(bot, ticket, fmt.Sprintf("✅ Обращение #%d закрыто поддержкой.\nЕсли вопрос остался, напишите в /support.", ticket.ID))
+		}
+		callbackText = "✅ Обращение закрыто"
+
+	case "reopen":
+		ticket, err = h.support.Reopen(staffID, id)
+		callbackText = "♻️ Обращение открыто снова"
+
+	case "hist":
+		ticket, err := h.support.Get(id)
+		if err != nil {
+			return h.answerError(bot, callback, err)
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return sendText(bot, chatID, ticketHistoryText(ticket))
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	if err != nil {
+		return h.answerError(bot, callback, err)
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, callbackText))
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, ticketCardText(ticket))
+	kb := keyboard.NewTicketKeyboard(ticket, !h.relay.IsDesk(chatID))
+	edit.ReplyMarkup = &kb
+	_, err = bot.Send(edit)
+	return err
+}
+
+// HandleInput пересылает пользователю ответ сотрудника реплаем на сообщение обращения
+// Остальные сообщения в чате поддержки не обрабатываются, чтобы бот не отвечал на переписку сотрудников
+func (h *SupportAdminHandler) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	desk := h.relay.IsDesk(msg.Chat.ID)
+	if msg.ReplyToMessage == nil || !h.access.Can(msg.From.ID, domain.PermSupport) {
+		return desk, nil
+	}
+
+	ticket, exists := h.support.ByMessage(msg.Chat.ID, msg.ReplyToMessage.MessageID)
+	if !exists || ticket.UserID == msg.From.ID {
+		return desk, nil
+	}
+
+	ticket, err := h.support.Reply(msg.From.ID, ticket.ID, ticketMessageText(msg))
+	if errors.Is(err, service.ErrTicketClosed) {
+		return true, sendText(bot, msg.Chat.ID,
+			fmt.Sprintf("❌ Обращение #%d закрыто. Чтобы ответить, откройте его снова: /tickets %d", ticket.ID, ticket.ID))
+	}
+	if err != nil {
+		return true, err
+	}
+
+	if err := h.relay.ToUser(bot, ticket, msg); err != nil {
+		sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Не удалось доставить ответ по обращению #%d: пользователь мог заблокировать бота.", ticket.ID))
+		return true, err
+	}
+	return true, nil
+}
+
+// showList показывает страницу списка обращений с последним фильтром сотрудника
+// messageID — сообщение для изменения (0 — отправить новое сообщение)
+func (h *SupportAdminHandler) showList(bot *tgbotapi.BotAPI, staffID, chatID int64, messageID, page int) error {
+	h.mu.Lock()
+	status, exists := h.filters[staffID]
+	h.mu.Unlock()
+	if !exists {
+		status = domain.TicketOpen
+	}
+
+	view, err := ticketsPaginator(status).Render(keyboard.SliceSource(h.tickets(staffID)), page)
+	if err != nil {
+		return err
+	}
+	kb := keyboard.AddTicketStatusRows(view.Keyboard, status)
+
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, view.Text)
+		reply.ReplyMarkup = kb
+		_, err = bot.Send(reply)
+		return err
+	}
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, view.Text)
+	edit.ReplyMarkup = &kb
+	_, err = bot.Send(edit)
+	return err
+}
+
+// tickets возвращает обращения по последнему фильтру сотрудника
+func (h *SupportAdminHandler) tickets(staffID int64) []domain.Ticket {
+	h.mu.Lock()
+	status, exists := h.filters[staffID]
+	h.mu.Unlock()
+	if !exists {
+		status = domain.TicketOpen
+	}
+	return h.support.List(status)
+}
+
+// notifyUser сообщает пользователю об изменении обращения и завершает переписку с поддержкой
+func (h *SupportAdminHandler) notifyUser(bot *tgbotapi.BotAPI, ticket domain.Ticket, text string) {
+	h.fsm.Cancel(ticket.ChatID, ticket.UserID, supportScene)
+	sendText(bot, ticket.ChatID, text)
+}
+
+// answerError отвечает на callback понятной ошибкой обращения
+func (h *SupportAdminHandler) answerError(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, err error) error {
+	if errors.Is(err, repository.ErrTicketNotFound) || errors.Is(err, service.ErrTicketClosed) {
+		_, reqErr := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ "+err.Error()))
+		return reqErr
+	}
+	bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+	return err
+}
+
+// ticketsPaginator создаёт постраничный список обращений (кнопки tkt_list_p_*, tkt_list_pick_*)
+func ticketsPaginator(status domain.TicketStatus) keyboard.Paginator[domain.Ticket] {
+	title := "все"
+	if status != "" {
+		title = status.Title()
+	}
+
+	return keyboard.Paginator[domain.Ticket]{
+		Namespace: "tkt_list",
+		PerPage:   ticketsPerPage,
+		Header:    fmt.Sprintf("🆘 Обращения: %s", title),
+		Empty:     fmt.Sprintf("🆘 Обращения: %s\n\nОбращений нет.", title),
+		Item: func(_ int, ticket domain.Ticket) string {
+			last := ""
+			if len(ticket.Messages) > 0 {
+				last = ticket.Messages[len(ticket.Messages)-1].Text
+			}
+			return fmt.Sprintf("#%d · %s · %s\n%s", ticket.ID, ticket.Status.Title(), ticket.UserName,
+				truncateRunes(last, ticketPreviewLength))
+		},
+		Button: func(_ int, ticket domain.Ticket) tgbotapi.InlineKeyboardButton {
+			return tgbotapi.NewInlineKeyboardButtonData(
+				truncateRunes(fmt.Sprintf("#%d · %s", ticket.ID, ticket.UserName), 40),
+				fmt.Sprintf("tkt_view_%d", ticket.ID))
+		},
+	}
+}
+
+// ticketHistoryText формирует историю переписки по обращению
+// Если история не помещается в сообщение, показываются последние сообщения
+func ticketHistoryText(ticket domain.Ticket) string {
+	header := fmt.Sprintf("📜 История обращения #%d (%s)\n", ticket.ID, ticket.UserName)
+
+	var entries []string
+	length := len([]rune(header))
+	for i := len(ticket.Messages) - 1; i >= 0; i-- {
+		message := ticket.Messages[i]
+		author := "👤 Пользователь"
+		if message.FromSupport {
+			author = fmt.Sprintf("🛟 Поддержка (%d)", message.AuthorID)
+		}
+		entry := fmt.Sprintf("\n%s · %s\n%s\n", author, message.At.Format("2006-01-02 15:04"), message.Text)
+
+		length += len([]rune(entry))
+		if length > ticketHistoryLimit {
+			entries = append(entries, "\n…")
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	text := header
+	for i := len(entries) - 1; i >= 0; i-- {
+		text += entries[i]
+	}
+	return text
+}
@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/service"
+)
+
+// supportScene — сценарий переписки с поддержкой: сообщения пользователя пересылаются в обращение
+const supportScene = "support"
+
+// SupportRelay пересылает сообщения обращений между пользователями и поддержкой
+//
+// Сообщения пользователей копируются в чат поддержки (или в тему форума этого чата).
+// Если чат поддержки не задан, их получают в личные сообщения сотрудники с разрешением поддержки.
+// Каждое скопированное сообщение связывается с обращением, поэтому ответ реплаем находит обращение
+type SupportRelay struct {
+	support *service.SupportService
+	access  *service.AccessService
+	chatID  int64 // Чат поддержки (0 — личные сообщения сотрудникам)
+	topicID int   // Тема форума в чате поддержки (0 — без темы)
+}
+
+// NewSupportRelay создаёт пересылку обращений
+// chatID - чат поддержки (0 — личные сообщения сотрудникам); topicID - тема форума (0 — без темы)
+func NewSupportRelay(support *service.SupportService, access *service.AccessService, chatID int64, topicID int) *SupportRelay {
+	return &SupportRelay{
+		support: support,
+		access:  access,
+		chatID:  chatID,
+		topicID: topicID,
+	}
+}
+
+// IsDesk возвращает true, если chatID — чат поддержки
+func (r *SupportRelay) IsDesk(chatID int64) bool {
+	return r.chatID != 0 && chatID == r.chatID
+}
+
+// ToSupport копирует сообщение пользователя в поддержку
+// Для нового обращения перед сообщением отправляется карточка с данными пользователя
+func (r *SupportRelay) ToSupport(bot *tgbotapi.BotAPI, ticket domain.Ticket, msg *tgbotapi.Message, created bool) error {
+	var errs []error
+	for _, chatID := range r.targets() {
+		if created {
+			cardID, err := r.send(bot, chatID, ticketCardText(ticket), keyboard.NewTicketKeyboard(ticket, false))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, r.support.Link(chatID, cardID, ticket.ID))
+		}
+
+		messageID, err := r.copy(bot, chatID, msg.Chat.ID, msg.MessageID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, r.support.Link(chatID, messageID, ticket.ID))
+	}
+	return errors.Join(errs...)
+}
+
+// ToUser копирует ответ сотрудника пользователю
+// Ответ связывается с обращением, чтобы пользователь мог ответить на него реплаем
+func (r *SupportRelay) ToUser(bot *tgbotapi.BotAPI, ticket domain.Ticket, msg *tgbotapi.Message) error {
+	header := tgbotapi.NewMessage(ticket.ChatID, fmt.Sprintf("💬 Ответ поддержки по обращению #%d:", ticket.ID))
+	if _, err := bot.Send(header); err != nil {
+		return fmt.Errorf("ошибка отправки ответа пользователю %d: %w", ticket.UserID, err)
+	}
+
+	copied, err := bot.CopyMessage(tgbotapi.NewCopyMessage(ticket.ChatID, msg.Chat.ID, msg.MessageID))
+	if err != nil {
+		return fmt.Errorf("ошибка отправки ответа пользователю %d: %w", ticket.UserID, err)
+	}
+	return r.support.Link(ticket.ChatID, copied.MessageID, ticket.ID)
+}
+
+// Notify отправляет служебное сообщение об обращении в поддержку
+func (r *SupportRelay) Notify(bot *tgbotapi.BotAPI, ticket domain.Ticket, text string) {
+	for _, chatID := range r.targets() {
+		messageID, err := r.send(bot, chatID, text, nil)
+		if err != nil {
+			log.Printf("Ошибка уведомления поддержки в чате %d: %v", chatID, err)
+			continue
+		}
+		if err := r.support.Link(chatID, messageID, ticket.ID); err != nil {
+			log.Printf("Ошибка связи сообщения с обращением #%d: %v", ticket.ID, err)
+		}
+	}
+}
+
+// targets возвращает чаты, в которые пересылаются обращения
+func (r *SupportRelay) targets() []int64 {
+	if r.chatID != 0 {
+		return []int64{r.chatID}
+	}
+
+	var chats []int64
+	for staffID, role := range r.access.Staff() {
+		if role.Has(domain.PermSupport) {
+			chats = append(chats, staffID)
+		}
+	}
+	return chats
+}
+
+// send отправляет текст в чат поддержки и возвращает ID сообщения
+// markup - инлайн-клавиатура (nil — без клавиатуры)
+func (r *SupportRelay) send(bot *tgbotapi.BotAPI, chatID int64, text string, markup any) (int, error) {
+	if !r.inTopic(chatID) {
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ReplyMarkup = markup
+		sent, err := bot.Send(reply)
+		return sent.MessageID, err
+	}
+
+	// tgbotapi v5.5.1 не знает message_thread_id, поэтому в тему форума пишем запросом напрямую
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params.AddNonZero("message_thread_id", r.topicID)
+	params.AddNonEmpty("text", text)
+	if err := params.AddInterface("reply_markup", markup); err != nil {
+		return 0, err
+	}
+	return r.request(bot, "sendMessage", params)
+}
+
+// copy копирует сообщение в чат поддержки и возвращает ID копии
+func (r *SupportRelay) copy(bot *tgbotapi.BotAPI, chatID, fromChatID int64, messageID int) (int, error) {
+	if !r.inTopic(chatID) {
+		copied, err := bot.CopyMessage(tgbotapi.NewCopyMessage(chatID, fromChatID, messageID))
+		return copied.MessageID, err
+	}
+
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params.AddNonZero("message_thread_id", r.topicID)
+	params.AddNonZero64("from_chat_id", fromChatID)
+	params.AddNonZero("message_id", messageID)
+	return r.request(bot, "copyMessage", params)
+}
+
+// inTopic возвращает true, если сообщения в chatID отправляются в тему форума
+func (r *SupportRelay) inTopic(chatID int64) bool {
+	return r.topicID != 0 && r.IsDesk(chatID)
+}
+
+// request выполняет запрос к Bot API и возвращает ID отправленного сообщения
+func (r *SupportRelay) request(bot *tgbotapi.BotAPI, endpoint string, params tgbotapi.Params) (int, error) {
+	resp, err := bot.MakeRequest(endpoint, params)
+	if err != nil {
+		return 0, err
+	}
+
+	var sent tgbotapi.MessageID
+	if err := json.Unmarshal(resp.Result, &sent); err != nil {
+		return 0, fmt.Errorf("ошибка разбора ответа %s: %w", endpoint, err)
+	}
+	return sent.MessageID, nil
+}
+
+// supportDialog — данные сценария переписки с поддержкой
+type supportDialog struct {
+	UserID int64 `json:"user_id"`
+}
+
+// SupportHandler обрабатывает команду /support и переписку пользователя с поддержкой (кнопки sup_*)
+type SupportHandler struct {
+	support *service.SupportService
+	relay   *SupportRelay
+	fsm     *FSM
+}
+
+// NewSupportHandler создаёт новый обработчик обращений в поддержку
+// и регистрирует в fsm сценарий переписки с поддержкой
+func NewSupportHandler(support *service.SupportService, relay *SupportRelay, fsm *FSM) *SupportHandler {
+	h := &SupportHandler{
+		support: support,
+		relay:   relay,
+		fsm:     fsm,
+	}
+	fsm.Register(&Scene[supportDialog]{
+		Name:  supportScene,
+		First: "relay",
+		Steps: map[string]Step[supportDialog]{
+			"relay": {Enter: h.enterRelay, Handle: h.handleRelay, Callback: h.callbackRelay},
+		},
+		Timeout: 30 * time.Minute,
+		// Обращение остаётся открытым: ответы поддержки по-прежнему придут в чат
+		OnTimeout: func(*tgbotapi.BotAPI, int64, supportDialog) error { return nil },
+		OnCancel: func(bot *tgbotapi.BotAPI, chatID int64, _ supportDialog) error {
+			return sendText(bot, chatID, supportPausedText)
+		},
+	})
+	return h
+}
+
+// supportPausedText — сообщение о выходе из переписки с поддержкой
+const supportPausedText = "Сообщения больше не пересылаются в поддержку. Ответ придёт в этот чат.\n" +
+	"Чтобы дополнить обращение, ответьте реплаем на ответ поддержки или отправьте /support."
+
+// Command возвращает команду
+func (h *SupportHandler) Command() string {
+	return "support"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *SupportHandler) Prefix() string {
+	return "sup_"
+}
+
+// Handle обрабатывает команду /support — начинает переписку с поддержкой
+func (h *SupportHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	return h.fsm.Start(bot, msg.Chat.ID, msg.From.ID, supportScene, supportDialog{UserID: msg.From.ID})
+}
+
+// HandleCallback обрабатывает кнопку «Написать в поддержку» (sup_open)
+func (h *SupportHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	if callback.Data != "sup_open" {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+	return h.fsm.Start(bot, callback.Message.Chat.ID, callback.From.ID, supportScene, supportDialog{UserID: callback.From.ID})
+}
+
+// HandleInput пересылает в поддержку ответ пользователя реплаем на сообщение поддержки
+// Так пользователь дополняет обращение, не начиная переписку заново
+func (h *SupportHandler) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	if msg.ReplyToMessage == nil || h.relay.IsDesk(msg.Chat.ID) {
+		return false, nil
+	}
+	ticket, exists := h.support.ByMessage(msg.Chat.ID, msg.ReplyToMessage.MessageID)
+	if !exists || ticket.UserID != msg.From.ID {
+		return false, nil
+	}
+
+	return true, h.forward(bot, msg)
+}
+
+// enterRelay приглашает описать проблему или дополнить открытое обращение
+func (h *SupportHandler) enterRelay(bot *tgbotapi.BotAPI, chatID int64, data supportDialog) error {
+	ticket, hasTicket := h.support.Active(data.UserID)
+
+	text := "🆘 Опишите проблему — сообщения будут переданы в поддержку.\n" +
+		"Можно прикрепить скриншот или файл. Когда закончите, нажмите «✅ Готово»."
+	if hasTicket {
+		text = fmt.Sprintf("🆘 Обращение #%d: %s\n\n", ticket.ID, ticket.Status.Title()) +
+			"Напишите, что хотите добавить, — сообщения будут переданы в поддержку."
+	}
+
+	reply := tgbotapi.NewMessage(chatID, text)
+	reply.ReplyMarkup = keyboard.NewSupportDialogKeyboard(hasTicket)
+	_, err := bot.Send(reply)
+	return err
+}
+
+// handleRelay пересылает сообщение пользователя в поддержку
+func (h *SupportHandler) handleRelay(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, _ *supportDialog) (Transition, error) {
+	return Stay(), h.forward(bot, msg)
+}
+
+// callbackRelay обрабатывает кнопки «Готово» (dlg_done) и «Закрыть обращение» (dlg_close)
+func (h *SupportHandler) callbackRelay(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, value string, _ *supportDialog) (Transition, error) {
+	chatID := callback.Message.Chat.ID
+	switch value {
+	case "done":
+		return Finish(), sendText(bot, chatID, supportPausedText)
+
+	case "close":
+		ticket, exists := h.support.Active(callback.From.ID)
+		if !exists {
+			return Finish(), sendText(bot, chatID, "У вас нет открытых обращений.")
+		}
+		ticket, err := h.support.Close(callback.From.ID, ticket.ID)
+		if err != nil {
+			return Stay(), err
+		}
+		h.relay.Notify(bot, ticket, fmt.Sprintf("🔒 Пользователь закрыл обращение #%d", ticket.ID))
+		return Finish(), sendText(bot, chatID, fmt.Sprintf("🔒 Обращение #%d закрыто. Спасибо!", ticket.ID))
+
+	default:
+		return Stay(), nil
+	}
+}
+
+// forward добавляет сообщение пользователя в обращение (создавая его при необходимости) и пересылает в поддержку
+func (h *SupportHandler) forward(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	ticket, created, err := h.support.Open(msg.From.ID, msg.Chat.ID, supportUserName(msg.From), ticketMessageText(msg))
+	if err != nil {
+		return err
+	}
+
+	if err := h.relay.ToSupport(bot, ticket, msg, created); err != nil {
+		log.Printf("Ошибка пересылки обращения #%d в поддержку: %v", ticket.ID, err)
+		return sendText(bot, msg.Chat.ID, "❌ Не удалось передать сообщение в поддержку. Попробуйте позже.")
+	}
+
+	if created {
+		return sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Обращение #%d создано. Ответ поддержки придёт в этот чат.", ticket.ID))
+	}
+	return nil
+}
+
+// supportUserName возвращает имя пользователя для карточки обращения: имя и @username
+func supportUserName(user *tgbotapi.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if user.UserName != "" {
+		name = strings.TrimSpace(name + " @" + user.UserName)
+	}
+	if name == "" {
+		return "без имени"
+	}
+	return name
+}
+
+// ticketMessageText возвращает текст сообщения для истории обращения
+// Для вложений без подписи сохраняется тип вложения
+func ticketMessageText(msg *tgbotapi.Message) string {
+	text := msg.Text
+	if text == "" {
+		text = msg.Caption
+	}
+
+	attachment := ""
+	switch {
+	case len(msg.Photo) > 0:
+		attachment = "[фото]"
+	case msg.Document != nil:
+		attachment = "[файл]"
+	case msg.Video != nil:
+		attachment = "[видео]"
+	case msg.Voice != nil:
+		attachment = "[голосовое сообщение]"
+	case msg.Sticker != nil:
+		attachment = "[стикер]"
+	}
+
+	switch {
+	case attachment == "":
+		return text
+	case text == "":
+		return attachment
+	default:
+		return attachment + " " + text
+	}
+}
+
+// ticketCardText формирует карточку обращения для поддержки
+func ticketCardText(ticket domain.Ticket) string {
+	assignee := "не назначен"
+	if ticket.AssigneeID != 0 {
+		assignee = fmt.Sprint(ticket.AssigneeID)
+	}
+
+	return fmt.Sprintf("🆘 Обращение #%d\n\n"+
+		"Статус: %s\n"+
+		"Пользователь: %s (ID %d)\n"+
+		"Ответственный: %s\n"+
+		"Создано: %s\n"+
+		"Сообщений: %d\n\n"+
+		"Чтобы ответить пользователю, ответьте реплаем на его сообщение или эту карточку.",
+		ticket.ID, ticket.Status.Title(), ticket.UserName, ticket.UserID,
+		assignee, ticket.CreatedAt.Format("2006-01-02 15:04"), len(ticket.Messages))
+}
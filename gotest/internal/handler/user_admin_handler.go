@@ -0,0 +1,411 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/keyboard"
+	"telegram-bot/internal/middleware"
+	"telegram-bot/internal/repository"
+	"telegram-bot/internal/service"
+)
+
+// userAdminInputKind — чего ждёт админ-панель пользователей от администратора
+type userAdminInputKind int
+
+const (
+	inputUserSearch  userAdminInputKind = iota // ID или @username для поиска
+	inputUserMessage                           // Текст личного сообщения пользователю
+)
+
+// userAdminInput — ожидаемый ответ администратора
+type userAdminInput struct {
+	kind   userAdminInputKind
+	target int64 // Получатель личного сообщения
+}
+
+// UserAdminHandler обрабатывает команду /user и кнопки управления пользователями (usr_*)
+type UserAdminHandler struct {
+	users    *repository.UserRepository
+	settings *repository.SettingsRepository
+	access   *service.AccessService
+	bans     *service.BanService
+	audit    *service.AuditService
+	mu       sync.Mutex
+	inputs   map[int64]userAdminInput // Ключ - ID администратора
+}
+
+// NewUserAdminHandler создаёт новый обработчик управления пользователями
+func NewUserAdminHandler(
+	users *repository.UserRepository,
+	settings *repository.SettingsRepository,
+	access *service.AccessService,
+	bans *service.BanService,
+	audit *service.AuditService,
+) *UserAdminHandler {
+	return &UserAdminHandler{
+		users:    users,
+		settings: settings,
+		access:   access,
+		bans:     bans,
+		audit:    audit,
+		inputs:   make(map[int64]userAdminInput),
+	}
+}
+
+// Command возвращает команду
+func (h *UserAdminHandler) Command() string {
+	return "user"
+}
+
+// Prefix возвращает префикс callback-запросов
+func (h *UserAdminHandler) Prefix() string {
+	return "usr_"
+}
+
+// Permission возвращает разрешение, необходимое для просмотра пользователей
+func (h *UserAdminHandler) Permission() domain.Permission {
+	return domain.PermUsersView
+}
+
+// Handle обрабатывает команду /user [ID или @username]
+// Без аргумента показывает список всех пользователей
+func (h *UserAdminHandler) Handle(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	query := strings.TrimSpace(msg.CommandArguments())
+	if query == "" {
+		return h.showList(bot, msg.Chat.ID, 0, 0)
+	}
+	return h.search(bot, msg.Chat.ID, query)
+}
+
+// CancelDraft удаляет ожидание поискового запроса или личного сообщения от администратора (по /cancel)
+func (h *UserAdminHandler) CancelDraft(adminID int64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, exists := h.inputs[adminID]
+	delete(h.inputs, adminID)
+	return exists
+}
+
+// HandleInput принимает поисковый запрос или текст личного сообщения
+func (h *UserAdminHandler) HandleInput(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	h.mu.Lock()
+	input, exists := h.inputs[msg.From.ID]
+	delete(h.inputs, msg.From.ID)
+	h.mu.Unlock()
+	if !exists {
+		return false, nil
+	}
+
+	switch input.kind {
+	case inputUserSearch:
+		return true, h.search(bot, msg.Chat.ID, strings.TrimSpace(msg.Text))
+
+	case inputUserMessage:
+		if msg.Text == "" {
+			return true, sendText(bot, msg.Chat.ID, "❌ Можно отправить только текст. Нажмите «✉️ Написать» ещё раз.")
+		}
+		// Права могли отозвать, пока администратор набирал сообщение
+		if !middleware.RequirePermission(bot, msg, h.access, domain.PermUsersWrite) {
+			return true, nil
+		}
+
+		direct := tgbotapi.NewMessage(input.target, "✉️ Сообщение от администратора:\n\n"+msg.Text)
+		_, err := bot.Send(direct)
+		h.audit.Record(msg.From.ID, domain.AuditUserMessage, input.target, map[string]string{"text": msg.Text}, err)
+		if err != nil {
+			return true, sendText(bot, msg.Chat.ID, fmt.Sprintf("❌ Не удалось отправить сообщение пользователю %d: %v", input.target, err))
+		}
+		return true, sendText(bot, msg.Chat.ID, fmt.Sprintf("✅ Сообщение отправлено пользователю %d.", input.target))
+	}
+
+	return false, nil
+}
+
+// HandleCallback обрабатывает кнопки управления пользователем
+func (h *UserAdminHandler) HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery) error {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	adminID := callback.From.ID
+
+	if callback.Data == "usr_search" {
+		h.mu.Lock()
+		h.inputs[adminID] = userAdminInput{kind: inputUserSearch}
+		h.mu.Unlock()
+
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		reply := tgbotapi.NewMessage(chatID, "🔍 Отправьте ID пользователя или @username:")
+		reply.ReplyMarkup = keyboard.NewUserSearchKeyboard()
+		_, err := bot.Send(reply)
+		return err
+	}
+
+	// Список пользователей: usr_list_p_<страница>, usr_list_pick_<страница>
+	if page, picker, ok := userListPaginator().Parse(callback.Data); ok {
+		// Кнопка списка отменяет ожидание поискового запроса
+		h.mu.Lock()
+		delete(h.inputs, adminID)
+		h.mu.Unlock()
+
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		if picker {
+			kb, err := userListPaginator().Picker(keyboard.SliceSource(h.usersNewestFirst()), page)
+			if err != nil {
+				return err
+			}
+			_, err = bot.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, kb))
+			return err
+		}
+		return h.showList(bot, chatID, messageID, page)
+	}
+
+	// Формат: usr_<действие>_<user_id>[_<роль>]
+	parts := strings.Split(strings.TrimPrefix(callback.Data, "usr_"), "_")
+	if len(parts) < 2 {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+	action := parts[0]
+	targetID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	callbackText := ""
+	switch action {
+	case "view":
+		// Просто обновляем карточку
+
+	case "ban":
+		if !middleware.RequireCallbackPermission(bot, callback, h.access, domain.PermUsersBan) {
+			return nil
+		}
+		if _, err := h.bans.Ban(adminID, targetID, "через админ-панель", 0); err != nil {
+			return answerActionError(bot, callback, err)
+		}
+		callbackText = "🚫 Пользователь заблокирован"
+
+	case "unban":
+		if !middleware.RequireCallbackPermission(bot, callback, h.access, domain.PermUsersBan) {
+			return nil
+		}
+		if err := h.bans.Unban(adminID, targetID); err != nil {
+			return answerActionError(bot, callback, err)
+		}
+		callbackText = "✅ Пользователь разблокирован"
+
+	case "reset":
+		if !middleware.RequireCallbackPermission(bot, callback, h.access, domain.PermUsersManage) {
+			return nil
+		}
+		err := h.settings.Reset(targetID)
+		h.audit.Record(adminID, domain.AuditUserReset, targetID, nil, err)
+		if err != nil {
+			bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Ошибка"))
+			return err
+		}
+		callbackText = "♻️ Настройки сброшены"
+
+	case "role":
+		if !middleware.RequireCallbackPermission(bot, callback, h.access, domain.PermRolesManage) {
+			return nil
+		}
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("🎖 Выберите роль для пользователя %d:", targetID))
+		kb := keyboard.NewUserRoleKeyboard(targetID)
+		edit.ReplyMarkup = &kb
+		_, err := bot.Send(edit)
+		return err
+
+	case "setrole":
+		if !middleware.RequireCallbackPermission(bot, callback, h.access, domain.PermRolesManage) {
+			return nil
+		}
+		role, ok := domain.Role(""), false
+		if len(parts) == 3 {
+			role, ok = domain.ParseRole(parts[2])
+		}
+		if !ok {
+			_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная роль"))
+			return err
+		}
+		if err := h.access.Grant(adminID, targetID, role); err != nil {
+			return answerActionError(bot, callback, err)
+		}
+		callbackText = fmt.Sprintf("🎖 Роль изменена на %s", role)
+
+	case "msg":
+		if !middleware.RequireCallbackPermission(bot, callback, h.access, domain.PermUsersWrite) {
+			return nil
+		}
+		h.mu.Lock()
+		h.inputs[adminID] = userAdminInput{kind: inputUserMessage, target: targetID}
+		h.mu.Unlock()
+
+		bot.Request(tgbotapi.NewCallback(callback.ID, ""))
+		return sendText(bot, chatID, fmt.Sprintf("✉️ Отправьте текст сообщения для пользователя %d:", targetID))
+
+	default:
+		_, err := bot.Request(tgbotapi.NewCallback(callback.ID, "❌ Неизвестная команда"))
+		return err
+	}
+
+	bot.Request(tgbotapi.NewCallback(callback.ID, callbackText))
+	return h.showUser(bot, chatID, messageID, targetID)
+}
+
+// showList показывает страницу списка пользователей, начиная с новых
+// Если messageID равен 0, отправляет новое сообщение вместо редактирования
+func (h *UserAdminHandler) showList(bot *tgbotapi.BotAPI, chatID int64, messageID int, page int) error {
+	view, err := userListPaginator().Render(keyboard.SliceSource(h.usersNewestFirst()), page)
+	if err != nil {
+		return err
+	}
+
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, view.Text)
+		reply.ReplyMarkup = view.Keyboard
+		_, err = bot.Send(reply)
+		return err
+	}
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, view.Text)
+	edit.ReplyMarkup = &view.Keyboard
+	_, err = bot.Send(edit)
+	return err
+}
+
+// usersNewestFirst возвращает пользователей бота, начиная с последних зарегистрированных
+func (h *UserAdminHandler) usersNewestFirst() []domain.User {
+	users := h.users.All()
+	slices.Reverse(users)
+	return users
+}
+
+// userListPaginator создаёт постраничный список пользователей (кнопки usr_list_*)
+func userListPaginator() keyboard.Paginator[domain.User] {
+	return keyboard.Paginator[domain.User]{
+		Namespace: "usr_list",
+		PerPage:   10,
+		Header:    "👥 Пользователи (сначала новые)\nНайти по ID или @username: /user <запрос>",
+		Empty:     "👥 Пользователей пока нет.",
+		Separator: "\n",
+		Item: func(i int, user domain.User) string {
+			line := fmt.Sprintf("%d. %s", i+1, userDisplayName(user))
+			if user.Username != "" {
+				line += " @" + user.Username
+			}
+			return line + fmt.Sprintf(" · %d · с %s", user.ID, user.CreatedAt.Format("02.01.2006"))
+		},
+		Button: func(i int, user domain.User) tgbotapi.InlineKeyboardButton {
+			return tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", i+1, userDisplayName(user)), fmt.Sprintf("usr_view_%d", user.ID))
+		},
+	}
+}
+
+// userDisplayName возвращает имя пользователя для списков
+func userDisplayName(user domain.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return "без имени"
+}
+
+// search ищет пользователя по ID или @username и показывает его карточку
+func (h *UserAdminHandler) search(bot *tgbotapi.BotAPI, chatID int64, query string) error {
+	userID, ok := resolveUserID(h.users, query)
+	if !ok || h.users.GetByID(userID) == nil {
+		return sendText(bot, chatID, fmt.Sprintf("❌ Пользователь «%s» не найден среди пользователей бота.", query))
+	}
+	return h.showUser(bot, chatID, 0, userID)
+}
+
+// showUser показывает карточку пользователя с кнопками действий
+// Если messageID равен 0, отправляет новое сообщение вместо редактирования
+func (h *UserAdminHandler) showUser(bot *tgbotapi.BotAPI, chatID int64, messageID int, userID int64) error {
+	user := h.users.GetByID(userID)
+	if user == nil {
+		user = &domain.User{ID: userID}
+	}
+	settings := h.settings.Get(userID)
+	ban, banned := h.bans.Get(userID)
+
+	text := "<b>👤 Пользователь</b>\n\n"
+	text += fmt.Sprintf("<b>ID:</b> <code>%d</code>\n", user.ID)
+	text += fmt.Sprintf("<b>Имя:</b> %s\n", html.EscapeString(strings.TrimSpace(user.FirstName+" "+user.LastName)))
+	if user.Username != "" {
+		text += fmt.Sprintf("<b>Username:</b> @%s\n", html.EscapeString(user.Username))
+	}
+	text += fmt.Sprintf("<b>Язык Telegram:</b> %s\n", user.LanguageCode)
+	text += fmt.Sprintf("<b>Роль:</b> %s\n\n", h.access.RoleOf(userID))
+
+	text += "<b>⚙️ Настройки</b>\n"
+	text += fmt.Sprintf("Язык интерфейса: %s\n", settings.Language)
+	text += fmt.Sprintf("Уведомления: %s\n\n", onOff(settings.NotificationsEnabled))
+
+	text += "<b>📈 Активность</b>\n"
+	if !user.CreatedAt.IsZero() {
+		text += fmt.Sprintf("Первый визит: %s\n", user.CreatedAt.Format("2006-01-02 15:04"))
+		text += fmt.Sprintf("Последняя активность: %s\n\n", user.LastSeenAt.Format("2006-01-02 15:04"))
+	} else {
+		text += "Пользователь ещё не писал боту\n\n"
+	}
+
+	if banned {
+		text += fmt.Sprintf("🚫 <b>Заблокирован</b> %s администратором <code>%d</code>\nСрок: %s\nПричина: %s",
+			ban.CreatedAt.Format("2006-01-02 15:04"), ban.BannedBy, banTerm(ban), html.EscapeString(ban.Reason))
+	} else {
+		text += "✅ Не заблокирован"
+	}
+
+	kb := keyboard.NewUserAdminKeyboard(userID, banned)
+	if messageID == 0 {
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.ParseMode = tgbotapi.ModeHTML
+		reply.ReplyMarkup = kb
+		_, err := bot.Send(reply)
+		return err
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.ReplyMarkup = &kb
+	_, err := bot.Send(edit)
+	return err
+}
+
+// answerActionError отвечает на callback понятным сообщением об ошибке действия
+func answerActionError(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, err error) error {
+	text := "❌ Ошибка"
+	switch {
+	case errors.Is(err, service.ErrOwnerRole):
+		text = "❌ Роль владельца задаётся только через ADMIN_IDS"
+	case errors.Is(err, service.ErrForbidden):
+		text = "❌ Нельзя выполнить действие над пользователем с ролью не ниже вашей"
+	case errors.Is(err, service.ErrNotBanned):
+		text = "❌ Пользователь не заблокирован"
+	default:
+		bot.Request(tgbotapi.NewCallback(callback.ID, text))
+		return err
+	}
+
+	_, reqErr := bot.Request(tgbotapi.NewCallback(callback.ID, text))
+	return reqErr
+}
+
+// onOff возвращает «Вкл» или «Выкл»
+func onOff(enabled bool) string {
+	if enabled {
+		return "Вкл"
+	}
+	return "Выкл"
+}
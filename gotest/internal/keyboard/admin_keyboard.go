@@ -0,0 +1,267 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// NewAdminPanelKeyboard создаёт inline-клавиатуру админ-панели
+func NewAdminPanelKeyboard() tgbotapi.InlineKeyboardMarkup {
+	btnStats := tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить статистику", "admin_stats")
+	btnBroadcast := tgbotapi.NewInlineKeyboardButtonData("📣 Рассылка", "bc_new")
+	btnSchedules := tgbotapi.NewInlineKeyboardButtonData("🕒 Запланированные", "sch_list")
+	btnUsers := tgbotapi.NewInlineKeyboardButtonData("👤 Пользователи", "usr_search")
+	btnCourses := tgbotapi.NewInlineKeyboardButtonData("📚 Курсы", "crs_list")
+	btnTickets := tgbotapi.NewInlineKeyboardButtonData("🆘 Обращения", "tkt_list_p_0")
+
+	row1 := tgbotapi.NewInlineKeyboardRow(btnStats)
+	row2 := tgbotapi.NewInlineKeyboardRow(btnBroadcast, btnSchedules)
+	row3 := tgbotapi.NewInlineKeyboardRow(btnUsers, btnCourses)
+	row4 := tgbotapi.NewInlineKeyboardRow(btnTickets)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(row1, row2, row3, row4)
+
+	return keyboard
+}
+
+// NewCancelKeyboard создаёт клавиатуру с одной кнопкой «Отмена»
+// data - данные callback-запроса кнопки
+func NewCancelKeyboard(data string) tgbotapi.InlineKeyboardMarkup {
+	btnCancel := tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", data)
+	row := tgbotapi.NewInlineKeyboardRow(btnCancel)
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+// NewBroadcastButtonsKeyboard создаёт клавиатуру шага добавления кнопок к рассылке
+func NewBroadcastButtonsKeyboard() tgbotapi.InlineKeyboardMarkup {
+	btnSkip := tgbotapi.NewInlineKeyboardButtonData("➡️ Без кнопок", "bc_nobuttons")
+	btnCancel := tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "bc_cancel")
+
+	row := tgbotapi.NewInlineKeyboardRow(btnSkip, btnCancel)
+	return tgbotapi.NewInlineKeyboardMarkup(row)
+}
+
+// NewBroadcastSegmentKeyboard создаёт клавиатуру выбора аудитории рассылки
+// withCourse - показывать ли сегмент «записанные на курс»
+func NewBroadcastSegmentKeyboard(withCourse bool) tgbotapi.InlineKeyboardMarkup {
+	btnAll := tgbotapi.NewInlineKeyboardButtonData("👥 Все с уведомлениями", "bc_seg_all")
+	btnLang := tgbotapi.NewInlineKeyboardButtonData("🌐 По языку", "bc_seg_lang")
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(btnAll),
+		tgbotapi.NewInlineKeyboardRow(btnLang),
+	}
+
+	if withCourse {
+		btnCourse := tgbotapi.NewInlineKeyboardButtonData("📚 Записанные на курс", "bc_seg_course")
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnCourse))
+	}
+
+	btnCancel := tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "bc_cancel")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnCancel))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewBroadcastLanguageKeyboard создаёт клавиатуру выбора языка аудитории рассылки
+func NewBroadcastLanguageKeyboard() tgbotapi.InlineKeyboardMarkup {
+	btnRu := tgbotapi.NewInlineKeyboardButtonData("🇷🇺 Русский", "bc_lang_ru")
+	btnEn := tgbotapi.NewInlineKeyboardButtonData("🇬🇧 English", "bc_lang_en")
+	btnZh := tgbotapi.NewInlineKeyboardButtonData("🇨🇳 中文", "bc_lang_zh")
+	btnCancel := tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "bc_cancel")
+
+	row1 := tgbotapi.NewInlineKeyboardRow(btnRu, btnEn, btnZh)
+	row2 := tgbotapi.NewInlineKeyboardRow(btnCancel)
+	return tgbotapi.NewInlineKeyboardMarkup(row1, row2)
+}
+
+// NewBroadcastConfirmKeyboard создаёт клавиатуру подтверждения рассылки:
+// отправить сейчас, отменить или запланировать
+func NewBroadcastConfirmKeyboard() tgbotapi.InlineKeyboardMarkup {
+	keyboard := NewConfirmKeyboard("bc_send")
+
+	btnSchedule := tgbotapi.NewInlineKeyboardButtonData("🕒 Запланировать", "bc_schedule")
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(btnSchedule))
+
+	return keyboard
+}
+
+// NewSchedulesKeyboard создаёт клавиатуру управления запланированными рассылками
+// Для каждой рассылки — ряд с кнопками паузы (или возобновления) и отмены
+func NewSchedulesKeyboard(jobs []domain.ScheduledBroadcast) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, job := range jobs {
+		var btnToggle tgbotapi.InlineKeyboardButton
+		if job.Status == domain.SchedulePaused {
+			btnToggle = tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("▶️ #%d", job.ID), fmt.Sprintf("sch_resume_%d", job.ID))
+		} else {
+			btnToggle = tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⏸ #%d", job.ID), fmt.Sprintf("sch_pause_%d", job.ID))
+		}
+		btnCancel := tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🗑 #%d", job.ID), fmt.Sprintf("sch_cancel_%d", job.ID))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnToggle, btnCancel))
+	}
+
+	btnRefresh := tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить", "sch_list")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnRefresh))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewUserSearchKeyboard создаёт клавиатуру под запросом поиска пользователя
+func NewUserSearchKeyboard() tgbotapi.InlineKeyboardMarkup {
+	btnList := tgbotapi.NewInlineKeyboardButtonData("📋 Все пользователи", "usr_list_p_0")
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnList))
+}
+
+// NewUserAdminKeyboard создаёт клавиатуру действий администратора с пользователем
+// banned - заблокирован ли пользователь (показываем «Разблокировать» вместо «Заблокировать»)
+func NewUserAdminKeyboard(userID int64, banned bool) tgbotapi.InlineKeyboardMarkup {
+	var btnBan tgbotapi.InlineKeyboardButton
+	if banned {
+		btnBan = tgbotapi.NewInlineKeyboardButtonData("✅ Разблокировать", fmt.Sprintf("usr_unban_%d", userID))
+	} else {
+		btnBan = tgbotapi.NewInlineKeyboardButtonData("🚫 Заблокировать", fmt.Sprintf("usr_ban_%d", userID))
+	}
+	btnReset := tgbotapi.NewInlineKeyboardButtonData("♻️ Сбросить настройки", fmt.Sprintf("usr_reset_%d", userID))
+	btnRole := tgbotapi.NewInlineKeyboardButtonData("🎖 Роль", fmt.Sprintf("usr_role_%d", userID))
+	btnMessage := tgbotapi.NewInlineKeyboardButtonData("✉️ Написать", fmt.Sprintf("usr_msg_%d", userID))
+	btnRefresh := tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить", fmt.Sprintf("usr_view_%d", userID))
+
+	row1 := tgbotapi.NewInlineKeyboardRow(btnBan, btnReset)
+	row2 := tgbotapi.NewInlineKeyboardRow(btnRole, btnMessage)
+	row3 := tgbotapi.NewInlineKeyboardRow(btnRefresh)
+	return tgbotapi.NewInlineKeyboardMarkup(row1, row2, row3)
+}
+
+// NewUserRoleKeyboard создаёт клавиатуру выбора роли для пользователя
+func NewUserRoleKeyboard(userID int64) tgbotapi.InlineKeyboardMarkup {
+	var buttons []tgbotapi.InlineKeyboardButton
+	for _, role := range domain.Roles {
+		if role == domain.RoleOwner {
+			continue // Роль владельца задаётся только через ADMIN_IDS
+		}
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(string(role), fmt.Sprintf("usr_setrole_%d_%s", userID, role)))
+	}
+
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К пользователю", fmt.Sprintf("usr_view_%d", userID))
+
+	row1 := tgbotapi.NewInlineKeyboardRow(buttons...)
+	row2 := tgbotapi.NewInlineKeyboardRow(btnBack)
+	return tgbotapi.NewInlineKeyboardMarkup(row1, row2)
+}
+
+// NewCourseAdminListKeyboard создаёт клавиатуру списка курсов для администратора
+// Неопубликованные курсы отмечаются значком 🙈
+func NewCourseAdminListKeyboard(courses []domain.Course) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, course := range courses {
+		icon := "👁"
+		if !course.Published {
+			icon = "🙈"
+		}
+		text := fmt.Sprintf("%s %s", icon, course.Title.Get(domain.DefaultLanguage))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, fmt.Sprintf("crs_view_%d", course.ID)),
+		))
+	}
+
+	btnNew := tgbotapi.NewInlineKeyboardButtonData("➕ Новый курс", "crs_new")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnNew))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewCourseAdminKeyboard создаёт клавиатуру управления курсом
+func NewCourseAdminKeyboard(course domain.Course) tgbotapi.InlineKeyboardMarkup {
+	id := course.ID
+
+	btnTitle := tgbotapi.NewInlineKeyboardButtonData("✏️ Название", fmt.Sprintf("crs_edit_%d_title", id))
+	btnDescription := tgbotapi.NewInlineKeyboardButtonData("✏️ Описание", fmt.Sprintf("crs_edit_%d_desc", id))
+	btnCategory := tgbotapi.NewInlineKeyboardButtonData("🏷 Категория", fmt.Sprintf("crs_edit_%d_cat", id))
+	btnCover := tgbotapi.NewInlineKeyboardButtonData("🖼 Обложка", fmt.Sprintf("crs_edit_%d_cover", id))
+	btnPrice := tgbotapi.NewInlineKeyboardButtonData("💳 Цена", fmt.Sprintf("crs_edit_%d_price", id))
+	btnLessons := tgbotapi.NewInlineKeyboardButtonData("📖 Уроки", fmt.Sprintf("lsa_list_%d", id))
+
+	var btnPublish tgbotapi.InlineKeyboardButton
+	if course.Published {
+		btnPublish = tgbotapi.NewInlineKeyboardButtonData("🙈 Снять с публикации", fmt.Sprintf("crs_unpub_%d", id))
+	} else {
+		btnPublish = tgbotapi.NewInlineKeyboardButtonData("👁 Опубликовать", fmt.Sprintf("crs_pub_%d", id))
+	}
+
+	btnUp := tgbotapi.NewInlineKeyboardButtonData("⬆️", fmt.Sprintf("crs_up_%d", id))
+	btnDown := tgbotapi.NewInlineKeyboardButtonData("⬇️", fmt.Sprintf("crs_down_%d", id))
+	btnPreview := tgbotapi.NewInlineKeyboardButtonData("👀 Предпросмотр", fmt.Sprintf("crs_preview_%d", id))
+	btnDelete := tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить", fmt.Sprintf("crs_del_%d", id))
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К списку", "crs_list")
+
+	row1 := tgbotapi.NewInlineKeyboardRow(btnTitle, btnDescription)
+	row2 := tgbotapi.NewInlineKeyboardRow(btnCategory, btnCover, btnPrice)
+	row3 := tgbotapi.NewInlineKeyboardRow(btnLessons, btnPublish)
+	row4 := tgbotapi.NewInlineKeyboardRow(btnUp, btnDown, btnPreview)
+	row5 := tgbotapi.NewInlineKeyboardRow(btnDelete, btnBack)
+	return tgbotapi.NewInlineKeyboardMarkup(row1, row2, row3, row4, row5)
+}
+
+// NewCourseDeleteKeyboard создаёт клавиатуру подтверждения удаления курса
+func NewCourseDeleteKeyboard(courseID int) tgbotapi.InlineKeyboardMarkup {
+	btnYes := tgbotapi.NewInlineKeyboardButtonData("🗑 Да, удалить", fmt.Sprintf("crs_delyes_%d", courseID))
+	btnNo := tgbotapi.NewInlineKeyboardButtonData("❌ Нет", fmt.Sprintf("crs_view_%d", courseID))
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnYes, btnNo))
+}
+
+// NewLessonAdminListKeyboard создаёт клавиатуру списка уроков курса для администратора
+// Уроки с отложенным открытием отмечаются значком ⏳
+func NewLessonAdminListKeyboard(courseID int, lessons []domain.Lesson) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, lesson := range lessons {
+		text := fmt.Sprintf("%d. %s %s", i+1, lesson.Kind.Icon(), lesson.Title.Get(domain.DefaultLanguage))
+		if lesson.UnlockAfterDays > 0 {
+			text += fmt.Sprintf(" ⏳%dд", lesson.UnlockAfterDays)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, fmt.Sprintf("lsa_view_%d", lesson.ID)),
+		))
+	}
+
+	btnNew := tgbotapi.NewInlineKeyboardButtonData("➕ Новый урок", fmt.Sprintf("lsa_new_%d", courseID))
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К курсу", fmt.Sprintf("crs_view_%d", courseID))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnNew, btnBack))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewLessonAdminKeyboard создаёт клавиатуру управления уроком
+func NewLessonAdminKeyboard(lesson domain.Lesson) tgbotapi.InlineKeyboardMarkup {
+	id := lesson.ID
+
+	btnTitle := tgbotapi.NewInlineKeyboardButtonData("✏️ Название", fmt.Sprintf("lsa_edit_%d_title", id))
+	btnBody := tgbotapi.NewInlineKeyboardButtonData("✏️ Содержимое", fmt.Sprintf("lsa_edit_%d_body", id))
+	btnDrip := tgbotapi.NewInlineKeyboardButtonData("⏳ Открытие", fmt.Sprintf("lsa_edit_%d_drip", id))
+	btnUp := tgbotapi.NewInlineKeyboardButtonData("⬆️", fmt.Sprintf("lsa_up_%d", id))
+	btnDown := tgbotapi.NewInlineKeyboardButtonData("⬇️", fmt.Sprintf("lsa_down_%d", id))
+	btnPreview := tgbotapi.NewInlineKeyboardButtonData("👀 Предпросмотр", fmt.Sprintf("lsa_preview_%d", id))
+	btnDelete := tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить", fmt.Sprintf("lsa_del_%d", id))
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К урокам", fmt.Sprintf("lsa_list_%d", lesson.CourseID))
+
+	row1 := tgbotapi.NewInlineKeyboardRow(btnTitle, btnBody, btnDrip)
+	row2 := tgbotapi.NewInlineKeyboardRow(btnUp, btnDown, btnPreview)
+	row3 := tgbotapi.NewInlineKeyboardRow(btnDelete, btnBack)
+	return tgbotapi.NewInlineKeyboardMarkup(row1, row2, row3)
+}
+
+// NewLessonDeleteKeyboard создаёт клавиатуру подтверждения удаления урока
+func NewLessonDeleteKeyboard(lessonID int) tgbotapi.InlineKeyboardMarkup {
+	btnYes := tgbotapi.NewInlineKeyboardButtonData("🗑 Да, удалить", fmt.Sprintf("lsa_delyes_%d", lessonID))
+	btnNo := tgbotapi.NewInlineKeyboardButtonData("❌ Нет", fmt.Sprintf("lsa_view_%d", lessonID))
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnYes, btnNo))
+}
+
+// NewLessonDripKeyboard создаёт клавиатуру шага выбора задержки открытия урока
+func NewLessonDripKeyboard() tgbotapi.InlineKeyboardMarkup {
+	btnNow := tgbotapi.NewInlineKeyboardButtonData("🔓 Сразу после записи", "lsa_nodrip")
+	btnCancel := tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "lsa_cancel")
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnNow, btnCancel))
+}
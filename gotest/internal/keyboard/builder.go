@@ -0,0 +1,370 @@
+package keyboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// Ограничения Telegram для инлайн-клавиатур
+const (
+	MaxButtonsPerRow = 8   // Кнопок в одном ряду
+	MaxButtons       = 100 // Кнопок во всей клавиатуре
+	MaxCallbackData  = 64  // Байт в callback_data
+	MaxInlineQuery   = 256 // Символов в switch_inline_query
+	MaxCopyText      = 256 // Символов в copy_text
+)
+
+// DefaultRowWidth — ширина ряда в буквах для AutoWrap, если её не задали
+const DefaultRowWidth = 30
+
+// ErrInvalidKeyboard — клавиатура нарушает ограничения Telegram
+var ErrInvalidKeyboard = errors.New("некорректная клавиатура")
+
+// FooterButton — стандартная кнопка нижнего ряда клавиатуры
+type FooterButton int
+
+const (
+	FooterBack  FooterButton = iota // ⬅️ — вернуться к предыдущему экрану (nav_back)
+	FooterHome                      // 🏠 Меню — главное меню (menu_menu)
+	FooterClose                     // ✖️ Закрыть — удалить сообщение (nav_close)
+)
+
+// WebAppInfo — мини-приложение, открываемое кнопкой
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+// CopyTextButton — текст, который кнопка копирует в буфер обмена
+type CopyTextButton struct {
+	Text string `json:"text"`
+}
+
+// Button — кнопка инлайн-клавиатуры
+// Дополняет tgbotapi.InlineKeyboardButton полями Bot API, которых нет в библиотеке
+type Button struct {
+	tgbotapi.InlineKeyboardButton
+	WebApp   *WebAppInfo     `json:"web_app,omitempty"`
+	CopyText *CopyTextButton `json:"copy_text,omitempty"`
+}
+
+// extended возвращает true, если кнопку нельзя выразить типами tgbotapi
+func (b Button) extended() bool {
+	return b.WebApp != nil || b.CopyText != nil
+}
+
+// Markup — инлайн-клавиатура с кнопками мини-приложений и копирования текста
+// Подходит как ReplyMarkup новых сообщений (NewMessage, NewPhoto, NewDocument...)
+type Markup struct {
+	InlineKeyboard [][]Button `json:"inline_keyboard"`
+}
+
+// Builder собирает инлайн-клавиатуру цепочкой вызовов
+//
+//	kb, err := keyboard.NewBuilder().Columns(2).
+//		Button("👤 Профиль", "menu_profile").
+//		Button("⚙️ Настройки", "menu_settings").
+//		URL("🌐 Сайт", "https://example.com").
+//		Footer(keyboard.FooterBack, keyboard.FooterClose).
+//		Build()
+//
+// Кнопки добавляются в текущий ряд, пока он не заполнится по правилам раскладки:
+// Columns — фиксированное число кнопок в ряду, MaxPerRow — не больше N кнопок,
+// AutoWrap — перенос по суммарной ширине подписей. Row начинает новый ряд явно.
+// Первая ошибка запоминается и возвращается из Build.
+type Builder struct {
+	rows      [][]Button
+	footer    []Button
+	columns   int
+	maxPerRow int
+	maxWidth  int
+	breakRow  bool // Следующая кнопка начнёт новый ряд
+	err       error
+}
+
+// NewBuilder создаёт пустой построитель клавиатуры
+func NewBuilder() *Builder {
+	return &Builder{maxPerRow: MaxButtonsPerRow, breakRow: true}
+}
+
+// From создаёт построитель, продолжающий готовую клавиатуру
+// Новые кнопки начинаются с нового ряда
+func From(keyboard tgbotapi.InlineKeyboardMarkup) *Builder {
+	b := NewBuilder()
+	for _, row := range keyboard.InlineKeyboard {
+		buttons := make([]Button, 0, len(row))
+		for _, btn := range row {
+			buttons = append(buttons, Button{InlineKeyboardButton: btn})
+		}
+		b.rows = append(b.rows, buttons)
+	}
+	return b
+}
+
+// Columns раскладывает следующие кнопки по n в ряд
+func (b *Builder) Columns(n int) *Builder {
+	if n < 1 || n > MaxButtonsPerRow {
+		return b.fail(fmt.Errorf("%w: в ряду может быть от 1 до %d кнопок, задано %d", ErrInvalidKeyboard, MaxButtonsPerRow, n))
+	}
+	b.columns = n
+	return b
+}
+
+// MaxPerRow ограничивает число кнопок в ряду, не заставляя заполнять ряд до конца
+func (b *Builder) MaxPerRow(n int) *Builder {
+	if n < 1 || n > MaxButtonsPerRow {
+		return b.fail(fmt.Errorf("%w: в ряду может быть от 1 до %d кнопок, задано %d", ErrInvalidKeyboard, MaxButtonsPerRow, n))
+	}
+	b.maxPerRow = n
+	return b
+}
+
+// AutoWrap переносит кнопку в новый ряд, если суммарная ширина подписей превысит width
+// Ширина считается в буквах, эмодзи и иероглифы — за две; width <= 0 — DefaultRowWidth
+func (b *Builder) AutoWrap(width int) *Builder {
+	if width <= 0 {
+		width = DefaultRowWidth
+	}
+	b.maxWidth = width
+	return b
+}
+
+// Row начинает новый ряд: следующая кнопка встанет в начало ряда
+func (b *Builder) Row() *Builder {
+	b.breakRow = true
+	return b
+}
+
+// Button добавляет кнопку с callback-данными
+func (b *Builder) Button(text, data string) *Builder {
+	return b.Add(tgbotapi.NewInlineKeyboardButtonData(text, data))
+}
+
+// ButtonIf добавляет кнопку с callback-данными, только если cond истинно
+func (b *Builder) ButtonIf(cond bool, text, data string) *Builder {
+	if !cond {
+		return b
+	}
+	return b.Button(text, data)
+}
+
+// URL добавляет кнопку-ссылку (http, https или tg://)
+func (b *Builder) URL(text, url string) *Builder {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "tg://") {
+		return b.fail(fmt.Errorf("%w: ссылка кнопки «%s» должна начинаться с http://, https:// или tg://", ErrInvalidKeyboard, text))
+	}
+	return b.Add(tgbotapi.NewInlineKeyboardButtonURL(text, url))
+}
+
+// WebApp добавляет кнопку, открывающую мини-приложение по https-ссылке
+// Работает только в личных чатах; клавиатуру с ней можно получить только через Markup
+func (b *Builder) WebApp(text, url string) *Builder {
+	if !strings.HasPrefix(url, "https://") {
+		return b.fail(fmt.Errorf("%w: мини-приложение кнопки «%s» должно открываться по https://", ErrInvalidKeyboard, text))
+	}
+	return b.add(Button{InlineKeyboardButton: tgbotapi.InlineKeyboardButton{Text: text}, WebApp: &WebAppInfo{URL: url}})
+}
+
+// SwitchInline добавляет кнопку выбора чата для инлайн-запроса query
+func (b *Builder) SwitchInline(text, query string) *Builder {
+	if utf8.RuneCountInString(query) > MaxInlineQuery {
+		return b.fail(fmt.Errorf("%w: инлайн-запрос кнопки «%s» длиннее %d символов", ErrInvalidKeyboard, text, MaxInlineQuery))
+	}
+	return b.Add(tgbotapi.NewInlineKeyboardButtonSwitch(text, query))
+}
+
+// SwitchInlineCurrent добавляет кнопку инлайн-запроса query в текущем чате
+func (b *Builder) SwitchInlineCurrent(text, query string) *Builder {
+	if utf8.RuneCountInString(query) > MaxInlineQuery {
+		return b.fail(fmt.Errorf("%w: инлайн-запрос кнопки «%s» длиннее %d символов", ErrInvalidKeyboard, text, MaxInlineQuery))
+	}
+	return b.Add(tgbotapi.InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query})
+}
+
+// CopyText добавляет кнопку, копирующую copy в буфер обмена
+// Клавиатуру с ней можно получить только через Markup
+func (b *Builder) CopyText(text, copy string) *Builder {
+	if n := utf8.RuneCountInString(copy); n == 0 || n > MaxCopyText {
+		return b.fail(fmt.Errorf("%w: текст для копирования кнопки «%s» должен быть от 1 до %d символов", ErrInvalidKeyboard, text, MaxCopyText))
+	}
+	return b.add(Button{InlineKeyboardButton: tgbotapi.InlineKeyboardButton{Text: text}, CopyText: &CopyTextButton{Text: copy}})
+}
+
+// Add добавляет готовую кнопку tgbotapi
+func (b *Builder) Add(button tgbotapi.InlineKeyboardButton) *Builder {
+	return b.add(Button{InlineKeyboardButton: button})
+}
+
+// When вызывает fn для добавления кнопок, только если cond истинно
+func (b *Builder) When(cond bool, fn func(b *Builder)) *Builder {
+	if cond {
+		fn(b)
+	}
+	return b
+}
+
+// WhenEnabled вызывает fn для добавления кнопок, только если флаг feature включён
+// Если gate не задан, кнопки не добавляются (как в RowIf)
+func (b *Builder) WhenEnabled(gate domain.FeatureGate, feature string, fn func(b *Builder)) *Builder {
+	return b.When(gate != nil && gate(feature), fn)
+}
+
+// Footer задаёт стандартный нижний ряд: назад, главное меню, закрыть
+// Нижний ряд всегда последний, сколько бы кнопок ни добавили после Footer
+func (b *Builder) Footer(buttons ...FooterButton) *Builder {
+	b.footer = b.footer[:0]
+	for _, kind := range buttons {
+		var btn tgbotapi.InlineKeyboardButton
+		switch kind {
+		case FooterBack:
+			btn = tgbotapi.NewInlineKeyboardButtonData("⬅️", "nav_back")
+		case FooterHome:
+			btn = tgbotapi.NewInlineKeyboardButtonData("🏠 Меню", "menu_menu")
+		case FooterClose:
+			btn = tgbotapi.NewInlineKeyboardButtonData("✖️ Закрыть", "nav_close")
+		default:
+			return b.fail(fmt.Errorf("%w: неизвестная кнопка нижнего ряда %d", ErrInvalidKeyboard, kind))
+		}
+		b.footer = append(b.footer, Button{InlineKeyboardButton: btn})
+	}
+	return b
+}
+
+// Markup собирает клавиатуру с любыми кнопками и проверяет ограничения Telegram
+func (b *Builder) Markup() (Markup, error) {
+	rows := b.rows
+	if len(b.footer) > 0 {
+		rows = append(rows[:len(rows):len(rows)], b.footer)
+	}
+	if err := b.validate(rows); err != nil {
+		return Markup{}, err
+	}
+
+	// Пустой, а не nil срез: клавиатура без кнопок должна уходить в Telegram как []
+	markup := Markup{InlineKeyboard: [][]Button{}}
+	for _, row := range rows {
+		markup.InlineKeyboard = append(markup.InlineKeyboard, append([]Button(nil), row...))
+	}
+	return markup, nil
+}
+
+// Build собирает клавиатуру tgbotapi и проверяет ограничения Telegram
+// Кнопки мини-приложений и копирования текста в tgbotapi не выражаются — для них используйте Markup
+func (b *Builder) Build() (tgbotapi.InlineKeyboardMarkup, error) {
+	markup, err := b.Markup()
+	if err != nil {
+		return tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(markup.InlineKeyboard))
+	for _, row := range markup.InlineKeyboard {
+		buttons := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, btn := range row {
+			if btn.extended() {
+				return tgbotapi.InlineKeyboardMarkup{}, fmt.Errorf("%w: кнопку «%s» можно отправить только через Markup", ErrInvalidKeyboard, btn.Text)
+			}
+			buttons = append(buttons, btn.InlineKeyboardButton)
+		}
+		rows = append(rows, buttons)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), nil
+}
+
+// MustBuild собирает клавиатуру и паникует при ошибке
+// Для клавиатур, собранных из констант, где ошибка — опечатка в коде
+func (b *Builder) MustBuild() tgbotapi.InlineKeyboardMarkup {
+	keyboard, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return keyboard
+}
+
+// add ставит кнопку в текущий ряд или начинает новый по правилам раскладки
+func (b *Builder) add(button Button) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	last := len(b.rows) - 1
+	if b.breakRow || last < 0 || b.rowFull(b.rows[last], button) {
+		b.rows = append(b.rows, nil)
+		last++
+		b.breakRow = false
+	}
+	b.rows[last] = append(b.rows[last], button)
+	return b
+}
+
+// rowFull возвращает true, если кнопка не помещается в ряд row
+func (b *Builder) rowFull(row []Button, button Button) bool {
+	if len(row) >= b.maxPerRow || (b.columns > 0 && len(row) >= b.columns) {
+		return true
+	}
+	if b.maxWidth > 0 {
+		width := labelWidth(button.Text)
+		for _, btn := range row {
+			width += labelWidth(btn.Text)
+		}
+		return width > b.maxWidth
+	}
+	return false
+}
+
+// validate проверяет клавиатуру на ограничения Telegram
+func (b *Builder) validate(rows [][]Button) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	total := 0
+	for _, row := range rows {
+		if len(row) > MaxButtonsPerRow {
+			return fmt.Errorf("%w: в ряду %d кнопок, максимум %d", ErrInvalidKeyboard, len(row), MaxButtonsPerRow)
+		}
+		for _, btn := range row {
+			total++
+			if strings.TrimSpace(btn.Text) == "" {
+				return fmt.Errorf("%w: у кнопки нет подписи", ErrInvalidKeyboard)
+			}
+			if btn.CallbackData != nil && (len(*btn.CallbackData) == 0 || len(*btn.CallbackData) > MaxCallbackData) {
+				return fmt.Errorf("%w: данные кнопки «%s» должны быть от 1 до %d байт", ErrInvalidKeyboard, btn.Text, MaxCallbackData)
+			}
+		}
+	}
+	if total > MaxButtons {
+		return fmt.Errorf("%w: %d кнопок, максимум %d", ErrInvalidKeyboard, total, MaxButtons)
+	}
+	return nil
+}
+
+// fail запоминает первую ошибку построения
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// labelWidth оценивает ширину подписи кнопки в буквах
+// Эмодзи и иероглифы занимают примерно две буквы, модификаторы эмодзи — ноль
+func labelWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case r == '\uFE0F' || r == '\u200D' || unicode.Is(unicode.Mn, r):
+			// Селектор варианта и соединитель эмодзи ширины не добавляют
+		case r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) || unicode.Is(unicode.Han, r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,66 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// NewMyCoursesKeyboard создаёт клавиатуру списка «Мои курсы»
+// Каждый курс — кнопка с процентом прохождения
+func NewMyCoursesKeyboard(courses []domain.CourseProgress, lang string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, progress := range courses {
+		mark := fmt.Sprintf("%d%%", progress.Percent())
+		if progress.Enrollment.Completed() {
+			mark = "✅"
+		}
+		btnText := fmt.Sprintf("%s · %s", progress.Course.Title.Get(lang), mark)
+		btn := tgbotapi.NewInlineKeyboardButtonData(btnText, fmt.Sprintf("enr_view_%d", progress.Course.ID))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btn))
+	}
+
+	btnCatalog := tgbotapi.NewInlineKeyboardButtonData("📚 Каталог курсов", "menu_courses")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnCatalog))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewEnrollmentKeyboard создаёт клавиатуру экрана прогресса по курсу
+// Кнопки уроков показываются, если в курсе есть уроки; кнопка сертификата — после прохождения курса
+func NewEnrollmentKeyboard(courseID int, hasLessons, completed bool) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if completed {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📜 Сертификат", fmt.Sprintf("enr_cert_%d", courseID)),
+		))
+	}
+	if hasLessons {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("▶️ Продолжить", fmt.Sprintf("les_resume_%d", courseID)),
+			tgbotapi.NewInlineKeyboardButtonData("📋 Уроки", LessonsPageData(courseID, 0)),
+		))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📖 О курсе", fmt.Sprintf("course_%d", courseID)),
+			tgbotapi.NewInlineKeyboardButtonData("🚪 Отписаться", fmt.Sprintf("enr_leave_%d", courseID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬅️ Мои курсы", "enr_my"),
+		),
+	)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewUnenrollConfirmKeyboard создаёт клавиатуру подтверждения отписки от курса
+func NewUnenrollConfirmKeyboard(courseID int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Да, отписаться", fmt.Sprintf("enr_leaveyes_%d", courseID)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", fmt.Sprintf("enr_view_%d", courseID)),
+		),
+	)
+}
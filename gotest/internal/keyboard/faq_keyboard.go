@@ -0,0 +1,30 @@
+package keyboard
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// NewFAQAnswerKeyboard создаёт клавиатуру кнопок автоответа на языке lang — по одной кнопке в ряд
+// Возвращает nil, если у правила нет кнопок
+func NewFAQAnswerKeyboard(buttons []domain.FAQButton, lang string) (*tgbotapi.InlineKeyboardMarkup, error) {
+	if len(buttons) == 0 {
+		return nil, nil
+	}
+
+	b := NewBuilder().Columns(1)
+	for _, button := range buttons {
+		if button.URL != "" {
+			b.URL(button.Text.Get(lang), button.URL)
+		} else {
+			b.Button(button.Text.Get(lang), button.Data)
+		}
+	}
+
+	kb, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &kb, nil
+}
@@ -0,0 +1,28 @@
+package keyboard
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// RowIf возвращает ряд кнопок, если флаг feature включён для пользователя
+// Если флаг выключен или gate не задан — возвращает nil
+func RowIf(gate domain.FeatureGate, feature string, buttons ...tgbotapi.InlineKeyboardButton) []tgbotapi.InlineKeyboardButton {
+	if gate == nil || !gate(feature) {
+		return nil
+	}
+	return tgbotapi.NewInlineKeyboardRow(buttons...)
+}
+
+// NewGatedKeyboard создаёт инлайн-клавиатуру, пропуская пустые ряды
+// Используется вместе с RowIf для кнопок, скрытых флагами функций
+func NewGatedKeyboard(rows ...[]tgbotapi.InlineKeyboardButton) tgbotapi.InlineKeyboardMarkup {
+	visible := make([][]tgbotapi.InlineKeyboardButton, 0, len(rows))
+	for _, row := range rows {
+		if len(row) > 0 {
+			visible = append(visible, row)
+		}
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(visible...)
+}
@@ -2,23 +2,22 @@ package keyboard
 
 import (
 	"fmt"
+	"log"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
 )
 
 // AddBackButton добавляет кнопку "назад" (⬅️) в правый нижний угол клавиатуры
 // Возвращает новую клавиатуру с добавленной кнопкой "назад"
+// Если клавиатура нарушает ограничения Telegram, она возвращается без изменений
 func AddBackButton(keyboard tgbotapi.InlineKeyboardMarkup) tgbotapi.InlineKeyboardMarkup {
-	// Создаём кнопку "назад"
-	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️", "nav_back")
-
-	// Добавляем кнопку "назад" в отдельный ряд (правый нижний угол)
-	backRow := tgbotapi.NewInlineKeyboardRow(btnBack)
-
-	// Добавляем новый ряд к существующим рядам
-	rows := append(keyboard.InlineKeyboard, backRow)
-	newKeyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
-
+	newKeyboard, err := From(keyboard).Footer(FooterBack).Build()
+	if err != nil {
+		log.Printf("Ошибка добавления кнопки «назад»: %v", err)
+		return keyboard
+	}
 	return newKeyboard
 }
 
@@ -84,93 +83,72 @@ func NewLanguageInlineKeyboard(currentLang string) tgbotapi.InlineKeyboardMarkup
 }
 
 // NewMainMenuInlineKeyboard создаёт inline-клавиатуру для главного меню
-// С кнопками: Профиль, Настройки, Меню, Курсы
+// С кнопками: Профиль, Настройки, Меню, Курсы (по две в ряд)
 func NewMainMenuInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
-	btnProfile := tgbotapi.NewInlineKeyboardButtonData("👤 Профиль", "menu_profile")
-	btnSettings := tgbotapi.NewInlineKeyboardButtonData("⚙️ Настройки", "menu_settings")
-	btnMenu := tgbotapi.NewInlineKeyboardButtonData("📋 Меню", "menu_menu")
-	btnCourses := tgbotapi.NewInlineKeyboardButtonData("📚 Курсы", "menu_courses")
-
-	// Первый ряд - Профиль и Настройки
-	row1 := tgbotapi.NewInlineKeyboardRow(btnProfile, btnSettings)
-	// Второй ряд - Меню и Курсы
-	row2 := tgbotapi.NewInlineKeyboardRow(btnMenu, btnCourses)
-
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(row1, row2)
-	return keyboard
-}
-
-// Course представляет курс
-type Course struct {
-	ID          int
-	Title       string
-	Description string
+	return NewBuilder().Columns(2).
+		Button("👤 Профиль", "menu_profile").
+		Button("⚙️ Настройки", "menu_settings").
+		Button("📋 Меню", "menu_menu").
+		Button("📚 Курсы", "menu_courses").
+		Button("🆘 Поддержка", "sup_open").
+		MustBuild()
 }
 
-// NewCoursesKeyboard создаёт inline-клавиатуру для пагинации курсов
-// courses - список всех курсов
-// currentPage - текущая страница (начинается с 0)
-// itemsPerPage - количество курсов на странице
-func NewCoursesKeyboard(courses []Course, currentPage, itemsPerPage int) tgbotapi.InlineKeyboardMarkup {
-	totalPages := (len(courses) + itemsPerPage - 1) / itemsPerPage // Округление вверх
-	if totalPages == 0 {
-		totalPages = 1
-	}
-
-	// Ограничиваем currentPage в допустимых пределах
-	if currentPage < 0 {
-		currentPage = 0
-	}
-	if currentPage >= totalPages {
-		currentPage = totalPages - 1
-	}
-
-	// Вычисляем индексы для текущей страницы
-	startIdx := currentPage * itemsPerPage
-	endIdx := startIdx + itemsPerPage
-	if endIdx > len(courses) {
-		endIdx = len(courses)
-	}
-
-	// Создаём кнопки для курсов на текущей странице
-	var rows [][]tgbotapi.InlineKeyboardButton
-	for i := startIdx; i < endIdx; i++ {
-		course := courses[i]
-		btnText := fmt.Sprintf("%d. %s", i+1, course.Title)
-		btn := tgbotapi.NewInlineKeyboardButtonData(btnText, fmt.Sprintf("course_%d", course.ID))
-		row := tgbotapi.NewInlineKeyboardRow(btn)
-		rows = append(rows, row)
-	}
-
-	// Создаём кнопки навигации (⬅️ Назад / Вперёд ➡️)
-	var navRow []tgbotapi.InlineKeyboardButton
-
-	// Кнопка "Назад" (⬅️)
-	if currentPage > 0 {
-		btnPrev := tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", fmt.Sprintf("courses_page_%d", currentPage-1))
-		navRow = append(navRow, btnPrev)
+// CoursesPerPage — количество курсов на странице каталога
+const CoursesPerPage = 3
+
+// NewCoursesPaginator создаёт постраничный список каталога курсов (кнопки courses_*)
+// lang - язык названий и описаний курсов
+func NewCoursesPaginator(lang string) Paginator[domain.Course] {
+	return Paginator[domain.Course]{
+		Namespace: "courses",
+		PerPage:   CoursesPerPage,
+		Header:    "📚 Доступные курсы:",
+		Empty:     "📚 Курсов пока нет.",
+		Item: func(i int, course domain.Course) string {
+			return fmt.Sprintf("%d. %s\n%s", i+1, course.Title.Get(lang), course.Description.Get(lang))
+		},
+		Button: CourseButton(lang),
 	}
+}
 
-	// Информация о странице (если есть несколько страниц)
-	if totalPages > 1 {
-		pageInfo := tgbotapi.NewInlineKeyboardButtonData(
-			fmt.Sprintf("%d/%d", currentPage+1, totalPages),
-			"courses_info",
-		)
-		navRow = append(navRow, pageInfo)
+// CourseButton возвращает построитель кнопок, открывающих курс
+// Номер в названии кнопки — позиция курса в списке
+func CourseButton(lang string) func(int, domain.Course) tgbotapi.InlineKeyboardButton {
+	return func(i int, course domain.Course) tgbotapi.InlineKeyboardButton {
+		btnText := fmt.Sprintf("%d. %s", i+1, course.Title.Get(lang))
+		return tgbotapi.NewInlineKeyboardButtonData(btnText, fmt.Sprintf("course_%d", course.ID))
 	}
+}
 
-	// Кнопка "Вперёд" (➡️)
-	if currentPage < totalPages-1 {
-		btnNext := tgbotapi.NewInlineKeyboardButtonData("Вперёд ➡️", fmt.Sprintf("courses_page_%d", currentPage+1))
-		navRow = append(navRow, btnNext)
-	}
+// AddCatalogSearchRow добавляет к клавиатуре каталога кнопки поиска и фильтров
+func AddCatalogSearchRow(keyboard tgbotapi.InlineKeyboardMarkup) tgbotapi.InlineKeyboardMarkup {
+	btnSearch := tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск", "srch_ask")
+	btnFilters := tgbotapi.NewInlineKeyboardButtonData("🎛 Фильтры", "srch_show")
 
-	// Добавляем ряд навигации, если есть кнопки
-	if len(navRow) > 0 {
-		rows = append(rows, tgbotapi.NewInlineKeyboardRow(navRow...))
-	}
+	rows := append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(btnSearch, btnFilters))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
 
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
-	return keyboard
+// NewCourseDetailsKeyboard создаёт клавиатуру экрана курса
+// Если у курса есть обложка, добавляется кнопка её просмотра
+// Кнопка записи (или перехода к прогрессу для записанных) видна при включённом флаге записи на курсы
+// locked - платный курс недоступен пользователю: вместо записи предлагается покупка или подписка
+func NewCourseDetailsKeyboard(course domain.Course, gate domain.FeatureGate, enrolled, locked bool) tgbotapi.InlineKeyboardMarkup {
+	return NewBuilder().MaxPerRow(1).
+		WhenEnabled(gate, domain.FeatureCourseEnrollment, func(b *Builder) {
+			switch {
+			case enrolled:
+				b.Button("🎓 Мой прогресс", fmt.Sprintf("enr_view_%d", course.ID))
+			case locked:
+				b.Button("💳 Купить за "+course.PriceText(), fmt.Sprintf("pay_course_%d", course.ID))
+				b.Button("💎 Подписка", "pay_plans")
+			default:
+				b.Button("✅ Записаться", fmt.Sprintf("enr_join_%d", course.ID))
+			}
+		}).
+		ButtonIf(course.CoverFileID != "", "🖼 Обложка", fmt.Sprintf("course_cover_%d", course.ID)).
+		Button("⬅️ К списку курсов", "menu_courses").
+		Footer(FooterBack).
+		MustBuild()
 }
@@ -0,0 +1,94 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// LessonsPerPage — количество уроков на странице списка уроков
+const LessonsPerPage = 5
+
+// NewLessonsPaginator создаёт постраничный список уроков курса (кнопки les_list_<id курса>_*)
+// ✅ — урок пройден, 🔒 — ещё закрыт по расписанию
+func NewLessonsPaginator(courseID int, lang string) Paginator[domain.LessonStep] {
+	return Paginator[domain.LessonStep]{
+		Namespace: fmt.Sprintf("les_list_%d", courseID),
+		PerPage:   LessonsPerPage,
+		Empty:     "В курсе пока нет уроков.",
+		Separator: "\n",
+		Item: func(i int, step domain.LessonStep) string {
+			line := fmt.Sprintf("%s %d. %s", lessonStepIcon(step), step.Number, step.Lesson.Title.Get(lang))
+			if step.Locked {
+				line += " — откроется " + step.UnlockAt.Format("02.01")
+			}
+			return line
+		},
+		Button: func(i int, step domain.LessonStep) tgbotapi.InlineKeyboardButton {
+			btnText := fmt.Sprintf("%s %d. %s", lessonStepIcon(step), step.Number, step.Lesson.Title.Get(lang))
+			return tgbotapi.NewInlineKeyboardButtonData(btnText, fmt.Sprintf("les_open_%d_%d", courseID, step.Lesson.ID))
+		},
+	}
+}
+
+// LessonsPageData возвращает callback-данные страницы page списка уроков курса
+func LessonsPageData(courseID, page int) string {
+	return NewLessonsPaginator(courseID, "").PageData(page)
+}
+
+// AddLessonsProgressRow добавляет к списку уроков кнопку перехода к прогрессу по курсу
+func AddLessonsProgressRow(keyboard tgbotapi.InlineKeyboardMarkup, courseID int) tgbotapi.InlineKeyboardMarkup {
+	return From(keyboard).Button("🎓 Мой прогресс", fmt.Sprintf("enr_view_%d", courseID)).MustBuild()
+}
+
+// NewLessonKeyboard создаёт клавиатуру под уроком plan[index]
+// Пока урок не пройден, главная кнопка отмечает его пройденным (или начинает тест, если quiz равен true);
+// затем — открывает следующий урок
+func NewLessonKeyboard(courseID int, plan []domain.LessonStep, index int, quiz bool) tgbotapi.InlineKeyboardMarkup {
+	step := plan[index]
+
+	var main tgbotapi.InlineKeyboardButton
+	switch {
+	case !step.Completed && quiz:
+		main = tgbotapi.NewInlineKeyboardButtonData("📝 Пройти тест", fmt.Sprintf("qz_start_%d_%d", courseID, step.Lesson.ID))
+	case !step.Completed:
+		main = tgbotapi.NewInlineKeyboardButtonData("✅ Урок пройден", fmt.Sprintf("les_done_%d_%d", courseID, step.Lesson.ID))
+	case index == len(plan)-1:
+		main = tgbotapi.NewInlineKeyboardButtonData("🎓 Мой прогресс", fmt.Sprintf("enr_view_%d", courseID))
+	case plan[index+1].Locked:
+		text := fmt.Sprintf("🔒 Следующий урок — %s", plan[index+1].UnlockAt.Format("02.01"))
+		main = tgbotapi.NewInlineKeyboardButtonData(text, LessonsPageData(courseID, (index+1)/LessonsPerPage))
+	default:
+		main = tgbotapi.NewInlineKeyboardButtonData("Следующий урок ➡️", fmt.Sprintf("les_open_%d_%d", courseID, plan[index+1].Lesson.ID))
+	}
+
+	var navRow []tgbotapi.InlineKeyboardButton
+	if index > 0 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️ Предыдущий", fmt.Sprintf("les_open_%d_%d", courseID, plan[index-1].Lesson.ID)))
+	}
+	navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("📋 Все уроки", LessonsPageData(courseID, index/LessonsPerPage)))
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(main),
+		tgbotapi.NewInlineKeyboardRow(navRow...),
+	}
+	if quiz && step.Completed {
+		btnResult := tgbotapi.NewInlineKeyboardButtonData("📊 Результаты теста", fmt.Sprintf("qz_res_%d_%d", courseID, step.Lesson.ID))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnResult))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// lessonStepIcon возвращает значок состояния урока для пользователя
+func lessonStepIcon(step domain.LessonStep) string {
+	switch {
+	case step.Completed:
+		return "✅"
+	case step.Locked:
+		return "🔒"
+	default:
+		return step.Lesson.Kind.Icon()
+	}
+}
@@ -0,0 +1,216 @@
+package keyboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// pickerMaxPages — сколько страниц помещается в выбор номера страницы (5 рядов по 5 кнопок)
+const pickerMaxPages = 25
+
+// Source — источник элементов постраничного списка
+// Возвращает элементы items[offset:offset+limit] и общее число элементов
+type Source[T any] func(offset, limit int) (items []T, total int, err error)
+
+// SliceSource создаёт источник из готового среза
+func SliceSource[T any](items []T) Source[T] {
+	return func(offset, limit int) ([]T, int, error) {
+		start := min(offset, len(items))
+		end := min(start+limit, len(items))
+		return items[start:end], len(items), nil
+	}
+}
+
+// Paginator — постраничный список любой коллекции: текст страницы и клавиатура навигации
+//
+// Callback-данные кнопок:
+//
+//	<Namespace>_p_<страница>    — перейти на страницу (с нуля)
+//	<Namespace>_pick_<страница> — выбрать номер страницы (кнопка «2/7»)
+//
+// Namespace должен быть коротким: данные кнопки ограничены 64 байтами
+type Paginator[T any] struct {
+	Namespace string                                                // Префикс callback-данных, например "courses"
+	PerPage   int                                                   // Элементов на странице
+	Header    string                                                // Текст над элементами
+	Empty     string                                                // Текст, если элементов нет
+	Separator string                                                // Разделитель элементов (по умолчанию пустая строка между ними)
+	Item      func(index int, item T) string                        // Текст элемента; index — номер в коллекции с нуля
+	Button    func(index int, item T) tgbotapi.InlineKeyboardButton // Кнопка элемента (nil — без кнопок)
+}
+
+// Page — страница, собранная Paginator
+type Page[T any] struct {
+	Items    []T                           // Элементы страницы
+	Number   int                           // Номер страницы с нуля (после ограничения пределами)
+	Pages    int                           // Всего страниц (не меньше 1)
+	Total    int                           // Всего элементов
+	Text     string                        // Текст страницы
+	Keyboard tgbotapi.InlineKeyboardMarkup // Кнопки элементов и ряд навигации
+}
+
+// Render собирает страницу page из источника
+// Номер страницы ограничивается допустимыми пределами, поэтому устаревшие кнопки не ломают список
+func (p Paginator[T]) Render(source Source[T], page int) (Page[T], error) {
+	_, total, err := source(0, 0)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	current, start, end, pages := pageBounds(total, page, p.PerPage)
+
+	items, total, err := source(start, end-start)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	result := Page[T]{Items: items, Number: current, Pages: pages, Total: total}
+	result.Text = p.text(items, start)
+
+	// Пустой, а не nil срез: клавиатура без кнопок должна уходить в Telegram как []
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	if p.Button != nil {
+		for i, item := range items {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(p.Button(start+i, item)))
+		}
+	}
+	if navRow := p.navRow(current, pages); len(navRow) > 0 {
+		rows = append(rows, navRow)
+	}
+	result.Keyboard = tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	return result, nil
+}
+
+// Picker создаёт клавиатуру выбора номера страницы вокруг текущей
+// Текущая страница отмечена точками, последняя кнопка возвращает к ней
+func (p Paginator[T]) Picker(source Source[T], page int) (tgbotapi.InlineKeyboardMarkup, error) {
+	_, total, err := source(0, 0)
+	if err != nil {
+		return tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	current, _, _, pages := pageBounds(total, page, p.PerPage)
+
+	// Окно из pickerMaxPages страниц, в середине которого текущая
+	first := max(0, min(current-pickerMaxPages/2, pages-pickerMaxPages))
+	last := min(pages, first+pickerMaxPages)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for n := first; n < last; n++ {
+		label := strconv.Itoa(n + 1)
+		if n == current {
+			label = "· " + label + " ·"
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, p.PageData(n)))
+		if len(row) == 5 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", p.PageData(current))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnBack))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), nil
+}
+
+// PageData возвращает callback-данные перехода на страницу page
+func (p Paginator[T]) PageData(page int) string {
+	return fmt.Sprintf("%s_p_%d", p.Namespace, page)
+}
+
+// Parse разбирает callback-данные кнопок навигации
+// picker — нажата кнопка выбора страницы; ok — данные принадлежат этому списку
+func (p Paginator[T]) Parse(data string) (page int, picker, ok bool) {
+	rest, found := strings.CutPrefix(data, p.Namespace+"_")
+	if !found {
+		return 0, false, false
+	}
+	action, number, _ := strings.Cut(rest, "_")
+	page, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, false, false
+	}
+
+	switch action {
+	case "p":
+		return page, false, true
+	case "pick":
+		return page, true, true
+	default:
+		return 0, false, false
+	}
+}
+
+// text собирает текст страницы: заголовок и элементы
+func (p Paginator[T]) text(items []T, start int) string {
+	if len(items) == 0 {
+		if p.Empty != "" {
+			return p.Empty
+		}
+		return p.Header
+	}
+
+	separator := p.Separator
+	if separator == "" {
+		separator = "\n\n"
+	}
+
+	parts := make([]string, 0, len(items))
+	for i, item := range items {
+		parts = append(parts, p.Item(start+i, item))
+	}
+
+	text := strings.Join(parts, separator)
+	if p.Header != "" {
+		text = p.Header + "\n\n" + text
+	}
+	return text
+}
+
+// navRow создаёт ряд навигации ⏮ ⬅️ 2/7 ➡️ ⏭
+// Переходы к первой и последней странице появляются, когда до них больше одного шага
+func (p Paginator[T]) navRow(page, pages int) []tgbotapi.InlineKeyboardButton {
+	if pages <= 1 {
+		return nil
+	}
+
+	var navRow []tgbotapi.InlineKeyboardButton
+	if page > 1 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⏮", p.PageData(0)))
+	}
+	if page > 0 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️", p.PageData(page-1)))
+	}
+
+	pageInfo := fmt.Sprintf("%d/%d", page+1, pages)
+	navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(pageInfo, fmt.Sprintf("%s_pick_%d", p.Namespace, page)))
+
+	if page < pages-1 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("➡️", p.PageData(page+1)))
+	}
+	if page < pages-2 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⏭", p.PageData(pages-1)))
+	}
+	return navRow
+}
+
+// pageBounds ограничивает номер страницы допустимыми пределами
+// и возвращает индексы элементов на ней: items[start:end]
+func pageBounds(total, page, perPage int) (current, start, end, pages int) {
+	pages = (total + perPage - 1) / perPage // Округление вверх
+	if pages == 0 {
+		pages = 1
+	}
+
+	current = min(max(page, 0), pages-1)
+	start = current * perPage
+	end = min(start+perPage, total)
+	return current, start, end, pages
+}
@@ -0,0 +1,29 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// NewPlansKeyboard создаёт клавиатуру оплаты тарифных планов на языке lang — по одной кнопке в ряд
+// Возвращает nil, если планов нет
+func NewPlansKeyboard(plans []domain.Plan, lang string) (*tgbotapi.InlineKeyboardMarkup, error) {
+	if len(plans) == 0 {
+		return nil, nil
+	}
+
+	b := NewBuilder().Columns(1)
+	for _, plan := range plans {
+		b.Button(fmt.Sprintf("💳 %s — %s", plan.Title.Get(lang), domain.FormatPrice(plan.Price, plan.Currency)),
+			"pay_plan_"+plan.ID)
+	}
+
+	kb, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &kb, nil
+}
@@ -0,0 +1,70 @@
+package keyboard
+
+import (
+	"fmt"
+	"slices"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// QuizOptionLetter возвращает букву варианта ответа: A, B, C...
+func QuizOptionLetter(option int) string {
+	return string(rune('A' + option))
+}
+
+// NewQuizQuestionKeyboard создаёт клавиатуру текущего вопроса попытки
+// В вопросе с одним ответом нажатие сразу отправляет ответ,
+// с несколькими — отмечает вариант; ответ отправляется кнопкой «Ответить»
+func NewQuizQuestionKeyboard(session domain.QuizSession) tgbotapi.InlineKeyboardMarkup {
+	index := session.Question
+	question := session.Quiz.Questions[index]
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, option := range question.Options {
+		text := fmt.Sprintf("%s. %s", QuizOptionLetter(i), option)
+		data := fmt.Sprintf("qz_ans_%d_%d", index, i)
+		if question.Multiple() {
+			mark := "⬜"
+			if slices.Contains(session.Selected, i) {
+				mark = "☑️"
+			}
+			text = mark + " " + text
+			data = fmt.Sprintf("qz_tog_%d_%d", index, i)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text, data)))
+	}
+
+	if question.Multiple() {
+		btnSubmit := tgbotapi.NewInlineKeyboardButtonData("✅ Ответить", fmt.Sprintf("qz_sub_%d", index))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnSubmit))
+	}
+
+	btnStop := tgbotapi.NewInlineKeyboardButtonData("✖️ Прервать тест", "qz_stop")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnStop))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewQuizStopKeyboard создаёт клавиатуру сообщения о начале теста в опросах
+func NewQuizStopKeyboard() tgbotapi.InlineKeyboardMarkup {
+	btnStop := tgbotapi.NewInlineKeyboardButtonData("✖️ Прервать тест", "qz_stop")
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnStop))
+}
+
+// NewQuizResultKeyboard создаёт клавиатуру экрана результатов теста
+// Кнопка повторной попытки показывается, если тест не пройден и попытки остались
+func NewQuizResultKeyboard(quiz domain.Quiz, result domain.QuizResult) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if !result.Passed && result.AttemptsLeft(quiz) != 0 {
+		btnRetry := tgbotapi.NewInlineKeyboardButtonData("🔁 Пройти ещё раз", fmt.Sprintf("qz_start_%d_%d", quiz.CourseID, quiz.LessonID))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnRetry))
+	}
+
+	btnLesson := tgbotapi.NewInlineKeyboardButtonData("📖 К уроку", fmt.Sprintf("les_open_%d_%d", quiz.CourseID, quiz.LessonID))
+	btnLessons := tgbotapi.NewInlineKeyboardButtonData("📋 Все уроки", LessonsPageData(quiz.CourseID, 0))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnLesson, btnLessons))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
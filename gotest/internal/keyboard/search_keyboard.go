@@ -0,0 +1,77 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// CourseResultsPerPage — количество найденных курсов на странице результатов поиска
+const CourseResultsPerPage = 5
+
+// AddCourseSearchRows добавляет под результаты поиска кнопки изменения запроса и фильтров
+// Кнопка сброса видна, только если фильтр задан
+func AddCourseSearchRows(keyboard tgbotapi.InlineKeyboardMarkup, filter domain.CourseFilter) tgbotapi.InlineKeyboardMarkup {
+	rows := append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔍 Запрос", "srch_ask"),
+		tgbotapi.NewInlineKeyboardButtonData("🗂 Категория", "srch_cats"),
+		tgbotapi.NewInlineKeyboardButtonData("📶 Уровень", "srch_levels"),
+	))
+	if !filter.Empty() {
+		btnReset := tgbotapi.NewInlineKeyboardButtonData("✖️ Сбросить фильтры", "srch_reset")
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnReset))
+	}
+
+	btnCatalog := tgbotapi.NewInlineKeyboardButtonData("⬅️ К каталогу", "menu_courses")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnCatalog))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewCategoryFilterKeyboard создаёт клавиатуру выбора категории, по две кнопки в ряд
+// Категории передаются в callback номером в списке: названия могут не поместиться в 64 байта
+func NewCategoryFilterKeyboard(categories []string, selected string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, category := range categories {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(checkedLabel(category, category == selected), fmt.Sprintf("srch_cat_%d", i)))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	btnAny := tgbotapi.NewInlineKeyboardButtonData(checkedLabel("Любая", selected == ""), "srch_cat_all")
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К результатам", "srch_show")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAny), tgbotapi.NewInlineKeyboardRow(btnBack))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// NewLevelFilterKeyboard создаёт клавиатуру выбора уровня сложности
+func NewLevelFilterKeyboard(selected domain.CourseLevel) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, level := range domain.CourseLevels {
+		btn := tgbotapi.NewInlineKeyboardButtonData(checkedLabel(level.Title(), level == selected), "srch_lvl_"+string(level))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btn))
+	}
+
+	btnAny := tgbotapi.NewInlineKeyboardButtonData(checkedLabel("Любой", selected == ""), "srch_lvl_all")
+	btnBack := tgbotapi.NewInlineKeyboardButtonData("⬅️ К результатам", "srch_show")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAny), tgbotapi.NewInlineKeyboardRow(btnBack))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// checkedLabel отмечает выбранный вариант галочкой
+func checkedLabel(label string, checked bool) string {
+	if checked {
+		return "✅ " + label
+	}
+	return label
+}
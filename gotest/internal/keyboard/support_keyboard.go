@@ -0,0 +1,59 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// NewSupportOpenKeyboard создаёт клавиатуру с кнопкой обращения в поддержку
+func NewSupportOpenKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return NewBuilder().Button("🆘 Написать в поддержку", "sup_open").MustBuild()
+}
+
+// NewSupportDialogKeyboard создаёт клавиатуру переписки с поддержкой
+// hasTicket - есть ли у пользователя открытое обращение (тогда его можно закрыть)
+func NewSupportDialogKeyboard(hasTicket bool) tgbotapi.InlineKeyboardMarkup {
+	return NewBuilder().
+		Button("✅ Готово", "dlg_done").
+		ButtonIf(hasTicket, "🔒 Закрыть обращение", "dlg_close").
+		MustBuild()
+}
+
+// NewTicketKeyboard создаёт клавиатуру карточки обращения для поддержки
+// withBack - показывать ли кнопку возврата к списку обращений
+func NewTicketKeyboard(ticket domain.Ticket, withBack bool) tgbotapi.InlineKeyboardMarkup {
+	return NewBuilder().MaxPerRow(2).
+		When(ticket.Active(), func(b *Builder) {
+			b.Button("🙋 Взять себе", fmt.Sprintf("tkt_take_%d", ticket.ID)).
+				Button("✅ Закрыть", fmt.Sprintf("tkt_close_%d", ticket.ID))
+		}).
+		ButtonIf(!ticket.Active(), "♻️ Открыть снова", fmt.Sprintf("tkt_reopen_%d", ticket.ID)).
+		Button("📜 История", fmt.Sprintf("tkt_hist_%d", ticket.ID)).
+		ButtonIf(withBack, "⬅️ К списку", "tkt_list_p_0").
+		MustBuild()
+}
+
+// AddTicketStatusRows добавляет к списку обращений кнопки выбора статуса
+// selected - выбранный статус (пустой — все обращения)
+func AddTicketStatusRows(keyboard tgbotapi.InlineKeyboardMarkup, selected domain.TicketStatus) tgbotapi.InlineKeyboardMarkup {
+	b := From(keyboard).Columns(2)
+	for _, status := range domain.TicketStatuses {
+		b.Button(checkedLabel(ticketStatusLabel(status), status == selected), fmt.Sprintf("tkt_st_%s", status))
+	}
+	return b.Button(checkedLabel("📋 Все", selected == ""), "tkt_st_all").MustBuild()
+}
+
+// ticketStatusLabel возвращает короткое название статуса для кнопки фильтра
+func ticketStatusLabel(status domain.TicketStatus) string {
+	switch status {
+	case domain.TicketOpen:
+		return "🟢 Открытые"
+	case domain.TicketPending:
+		return "🟡 Ждут пользователя"
+	default:
+		return "⚪️ Закрытые"
+	}
+}
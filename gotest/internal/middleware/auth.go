@@ -1,31 +1,39 @@
 package middleware
 
 import (
-	"fmt"
-
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/service"
 )
 
-// IsAdmin проверяет, является ли пользователь администратором
-func IsAdmin(userID int64, adminIDs []int64) bool {
-    for _, adminID := range adminIDs {
-        if userID == adminID {
-            return true
-        }
-    }
-    return false
+// RequirePermission проверяет разрешение и отправляет сообщение, если его нет
+func RequirePermission(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, access *service.AccessService, perm domain.Permission) bool {
+	if !access.Can(msg.From.ID, perm) {
+		request := "message"
+		if msg.IsCommand() {
+			request = "/" + msg.Command()
+		}
+		access.RecordDenied(msg.From.ID, perm, request)
+
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "У вас нет прав для выполнения этой команды.")
+		bot.Send(reply)
+		return false
+	}
+
+	return true
 }
 
-// RequireAdmin проверяет права доступа и отправляет сообщение, если пользователь не админ
-func RequireAdmin(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, adminIDs []int64) bool {
-    userID := msg.From.ID
-    
-    if !IsAdmin(userID, adminIDs) {
-        fmt.Println(userID, adminIDs)
-        reply := tgbotapi.NewMessage(msg.Chat.ID, "У вас нет прав для выполнения этой команды.")
-        bot.Send(reply)
-        return false
-    }
-    
-    return true
-}
\ No newline at end of file
+// RequireCallbackPermission проверяет разрешение для нажатия на инлайн-кнопку
+// Если разрешения нет — отвечает на callback уведомлением
+func RequireCallbackPermission(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, access *service.AccessService, perm domain.Permission) bool {
+	if !access.Can(callback.From.ID, perm) {
+		access.RecordDenied(callback.From.ID, perm, callback.Data)
+
+		callbackConfig := tgbotapi.NewCallback(callback.ID, "⛔ Недостаточно прав")
+		bot.Request(callbackConfig)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/service"
+)
+
+// RequireNotBanned проверяет, не заблокирован ли автор обновления
+// Заблокированный пользователь один раз получает уведомление о блокировке,
+// дальнейшие сообщения игнорируются, а нажатия на кнопки получают пустой ответ
+// Возвращает false, если обновление обрабатывать не нужно
+func RequireNotBanned(bot *tgbotapi.BotAPI, update tgbotapi.Update, bans *service.BanService) bool {
+	var from *tgbotapi.User
+	var chatID int64
+	switch {
+	case update.Message != nil:
+		from = update.Message.From
+		chatID = update.Message.Chat.ID
+	case update.CallbackQuery != nil:
+		from = update.CallbackQuery.From
+		if update.CallbackQuery.Message != nil {
+			chatID = update.CallbackQuery.Message.Chat.ID
+		}
+	}
+	if from == nil {
+		return true
+	}
+
+	ban, banned := bans.Get(from.ID)
+	if !banned {
+		return true
+	}
+
+	log.Printf("Обновление от заблокированного пользователя %d проигнорировано", from.ID)
+
+	if update.CallbackQuery != nil {
+		text := ""
+		if !ban.Notified {
+			text = "🚫 Вы заблокированы"
+		}
+		bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, text))
+	}
+
+	if !ban.Notified && chatID != 0 {
+		notice := tgbotapi.NewMessage(chatID, BanNotice(ban))
+		if _, err := bot.Send(notice); err != nil {
+			log.Printf("Ошибка отправки уведомления о блокировке %d: %v", from.ID, err)
+		}
+		if err := bans.MarkNotified(ban); err != nil {
+			log.Printf("Ошибка сохранения блокировки %d: %v", from.ID, err)
+		}
+	}
+
+	return false
+}
+
+// BanNotice формирует текст уведомления о блокировке для пользователя
+func BanNotice(ban domain.Ban) string {
+	text := "🚫 Вы заблокированы в этом боте"
+	if !ban.Permanent() {
+		text += " до " + ban.ExpiresAt.Format("2006-01-02 15:04")
+	}
+	text += "."
+	if ban.Reason != "" {
+		text += "\nПричина: " + ban.Reason
+	}
+	return text
+}
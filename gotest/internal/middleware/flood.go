@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/config"
+	"telegram-bot/internal/domain"
+)
+
+// floodSweepInterval — как часто удалять состояние неактивных пользователей
+const floodSweepInterval = 5 * time.Minute
+
+// floodState — состояние защиты от флуда для одного пользователя
+type floodState struct {
+	events        map[domain.EventType][]time.Time // Время последних событий по типам
+	strikes       int                              // Количество нарушений подряд
+	blockedUntil  time.Time                        // До какого момента обновления игнорируются
+	lastViolation time.Time                        // Время последнего нарушения
+	warned        bool                             // Предупреждение за текущую паузу уже отправлено
+}
+
+// FloodGuard ограничивает частоту обновлений от одного пользователя
+// Для каждого типа обновлений (сообщения, команды, кнопки) используется своё
+// скользящее окно. При превышении лимита пользователь получает паузу,
+// которая удваивается при каждом повторном нарушении (до MaxCooldown)
+type FloodGuard struct {
+	cfg       config.FloodConfig
+	mu        sync.Mutex
+	users     map[int64]*floodState // Ключ - userID
+	lastSweep time.Time
+}
+
+// NewFloodGuard создаёт защиту от флуда с указанными лимитами
+func NewFloodGuard(cfg config.FloodConfig) *FloodGuard {
+	return &FloodGuard{
+		cfg:       cfg,
+		users:     make(map[int64]*floodState),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow проверяет, можно ли обработать обновление
+// Если пользователь превысил лимит — вежливо просит подождать и возвращает false
+func (g *FloodGuard) Allow(bot *tgbotapi.BotAPI, update tgbotapi.Update) bool {
+	var from *tgbotapi.User
+	var chatID int64
+	var eventType domain.EventType
+	switch {
+	case update.CallbackQuery != nil:
+		from = update.CallbackQuery.From
+		eventType = domain.EventCallback
+	case update.Message != nil:
+		from = update.Message.From
+		chatID = update.Message.Chat.ID
+		eventType = domain.EventMessage
+		if update.Message.IsCommand() {
+			eventType = domain.EventCommand
+		}
+	}
+	if from == nil {
+		return true
+	}
+
+	allowed, wait, warn := g.check(from.ID, eventType, time.Now())
+	if allowed {
+		return true
+	}
+
+	// На нажатие кнопки отвечаем всегда, иначе у пользователя будет крутиться индикатор загрузки
+	if update.CallbackQuery != nil {
+		bot.Request(tgbotapi.NewCallback(update.CallbackQuery.ID, slowDownText(wait)))
+	} else if warn {
+		bot.Send(tgbotapi.NewMessage(chatID, slowDownText(wait)))
+	}
+	return false
+}
+
+// check учитывает событие и решает, пропускать ли его
+// Возвращает: разрешено ли событие, сколько осталось ждать, нужно ли отправить предупреждение
+func (g *FloodGuard) check(userID int64, eventType domain.EventType, now time.Time) (bool, time.Duration, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.sweep(now)
+
+	state, exists := g.users[userID]
+	if !exists {
+		state = &floodState{events: make(map[domain.EventType][]time.Time)}
+		g.users[userID] = state
+	}
+
+	// Пользователь на паузе — предупреждаем только один раз за паузу
+	if now.Before(state.blockedUntil) {
+		warn := !state.warned
+		state.warned = true
+		return false, state.blockedUntil.Sub(now), warn
+	}
+
+	// После долгого периода без нарушений счётчик нарушений обнуляется
+	if state.strikes > 0 && now.Sub(state.lastViolation) > g.cfg.MaxCooldown {
+		state.strikes = 0
+	}
+
+	// Оставляем только события внутри скользящего окна
+	windowStart := now.Add(-g.cfg.Window)
+	events := state.events[eventType]
+	kept := events[:0]
+	for _, at := range events {
+		if at.After(windowStart) {
+			kept = append(kept, at)
+		}
+	}
+	kept = append(kept, now)
+	state.events[eventType] = kept
+
+	if len(kept) <= g.limit(eventType) {
+		return true, 0, false
+	}
+
+	// Лимит превышен: пауза удваивается с каждым нарушением
+	state.strikes++
+	cooldown := g.cfg.Cooldown << (state.strikes - 1)
+	if cooldown > g.cfg.MaxCooldown || cooldown <= 0 {
+		cooldown = g.cfg.MaxCooldown
+	}
+	state.blockedUntil = now.Add(cooldown)
+	state.lastViolation = now
+	state.warned = true
+	state.events = make(map[domain.EventType][]time.Time)
+
+	log.Printf("Флуд от пользователя %d (%s): пауза %s, нарушение №%d", userID, eventType, cooldown, state.strikes)
+	return false, cooldown, true
+}
+
+// limit возвращает лимит событий за окно для типа обновления
+func (g *FloodGuard) limit(eventType domain.EventType) int {
+	switch eventType {
+	case domain.EventCommand:
+		return g.cfg.MaxCommands
+	case domain.EventCallback:
+		return g.cfg.MaxCallbacks
+	default:
+		return g.cfg.MaxMessages
+	}
+}
+
+// sweep удаляет состояние пользователей, которые давно ничего не присылали
+// Вызывается под g.mu
+func (g *FloodGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < floodSweepInterval {
+		return
+	}
+	g.lastSweep = now
+
+	for userID, state := range g.users {
+		if now.After(state.blockedUntil) && now.Sub(state.lastViolation) > g.cfg.MaxCooldown && !hasRecentEvents(state, now.Add(-g.cfg.Window)) {
+			delete(g.users, userID)
+		}
+	}
+}
+
+// hasRecentEvents проверяет, есть ли у пользователя события после since
+func hasRecentEvents(state *floodState, since time.Time) bool {
+	for _, events := range state.events {
+		if len(events) > 0 && events[len(events)-1].After(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// slowDownText формирует вежливую просьбу подождать
+func slowDownText(wait time.Duration) string {
+	seconds := int(wait.Round(time.Second).Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Sprintf("🐢 Не так быстро! Пожалуйста, подождите %d сек.", seconds)
+}
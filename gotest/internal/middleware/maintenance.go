@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/service"
+)
+
+// callbackTextLimit — максимальная длина текста ответа на callback в Telegram
+const callbackTextLimit = 200
+
+// RequireNotMaintenance пропускает обновления, пока режим обслуживания выключен
+// Во время обслуживания сотрудники работают как обычно, остальные пользователи
+// получают сообщение о работах, а их нажатия на кнопки — уведомление
+// Возвращает false, если обновление обрабатывать не нужно
+func RequireNotMaintenance(bot *tgbotapi.BotAPI, update tgbotapi.Update, maintenance *service.MaintenanceService) bool {
+	switch {
+	case update.CallbackQuery != nil:
+		callback := update.CallbackQuery
+		if !maintenance.Blocks(callback.From.ID) {
+			return true
+		}
+		text := []rune(maintenance.Message(callback.From.ID))
+		if len(text) > callbackTextLimit {
+			text = text[:callbackTextLimit]
+		}
+		bot.Request(tgbotapi.NewCallbackWithAlert(callback.ID, string(text)))
+		return false
+
+	case update.Message != nil && update.Message.From != nil:
+		msg := update.Message
+		if !maintenance.Blocks(msg.From.ID) {
+			return true
+		}
+		bot.Send(tgbotapi.NewMessage(msg.Chat.ID, maintenance.Message(msg.From.ID)))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"slices"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/service"
+)
+
+// premiumRequiredText — ответ на премиум-команду без подписки
+const premiumRequiredText = "💎 Эта команда доступна по подписке.\n\nТарифы и оформление: /premium"
+
+// RequireSubscription пропускает команды из списка commands только пользователям
+// с действующей подпиской, в плане которой есть премиум-команды
+// Остальные обновления проходят без проверки
+// Возвращает false, если обновление обрабатывать не нужно
+func RequireSubscription(bot *tgbotapi.BotAPI, update tgbotapi.Update, subscriptions *service.SubscriptionService, commands []string) bool {
+	msg := update.Message
+	if msg == nil || msg.From == nil || !msg.IsCommand() || !slices.Contains(commands, msg.Command()) {
+		return true
+	}
+	if subscriptions.Has(msg.From.ID, domain.PlanPremiumCommands) {
+		return true
+	}
+
+	bot.Send(tgbotapi.NewMessage(msg.Chat.ID, premiumRequiredText))
+	return false
+}
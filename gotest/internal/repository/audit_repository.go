@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// auditCollection — имя журнала аудита в хранилище
+const auditCollection = "audit"
+
+// AuditRepository хранит журнал аудита
+// Записи только добавляются и связаны в цепочку хешей
+type AuditRepository struct {
+	store    *JSONStore
+	mu       sync.Mutex
+	lastID   int64  // ID последней записи
+	lastHash string // Хеш последней записи
+}
+
+// NewAuditRepository создаёт репозиторий и находит последнюю запись журнала
+func NewAuditRepository(store *JSONStore) (*AuditRepository, error) {
+	r := &AuditRepository{store: store}
+
+	err := store.ReadLog(auditCollection, func(line []byte) error {
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("повреждённая запись журнала аудита: %w", err)
+		}
+		r.lastID = entry.ID
+		r.lastHash = entry.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Append добавляет запись в журнал, присваивая ей ID и хеш
+func (r *AuditRepository) Append(entry *domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.ID = r.lastID + 1
+	entry.PrevHash = r.lastHash
+	entry.Hash = auditHash(*entry)
+
+	if err := r.store.Append(auditCollection, entry); err != nil {
+		return err
+	}
+
+	r.lastID = entry.ID
+	r.lastHash = entry.Hash
+	return nil
+}
+
+// Find возвращает записи, подходящие под фильтр, от старых к новым
+// При заданном Limit возвращаются последние Limit записей
+func (r *AuditRepository) Find(filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+
+	err := r.store.ReadLog(auditCollection, func(line []byte) error {
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("повреждённая запись журнала аудита: %w", err)
+		}
+		if matchesAudit(entry, filter) {
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
+
+// Verify проверяет цепочку хешей журнала
+// Возвращает ID первой записи, которая была изменена, или 0, если журнал цел
+func (r *AuditRepository) Verify() (int64, error) {
+	var brokenID int64
+	prevHash := ""
+
+	err := r.store.ReadLog(auditCollection, func(line []byte) error {
+		if brokenID != 0 {
+			return nil
+		}
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("повреждённая запись журнала аудита: %w", err)
+		}
+		if entry.PrevHash != prevHash || entry.Hash != auditHash(entry) {
+			brokenID = entry.ID
+		}
+		prevHash = entry.Hash
+		return nil
+	})
+
+	return brokenID, err
+}
+
+// matchesAudit проверяет запись на соответствие фильтру
+func matchesAudit(entry domain.AuditEntry, filter domain.AuditFilter) bool {
+	if filter.ActorID != 0 && entry.ActorID != filter.ActorID {
+		return false
+	}
+	if filter.TargetID != 0 && entry.TargetID != filter.TargetID {
+		return false
+	}
+	if filter.Action != "" && !strings.HasPrefix(entry.Action, filter.Action) {
+		return false
+	}
+	if !filter.Since.IsZero() && entry.At.Before(filter.Since) {
+		return false
+	}
+	return true
+}
+
+// auditHash считает SHA-256 записи (без поля Hash)
+func auditHash(entry domain.AuditEntry) string {
+	entry.Hash = ""
+	data, _ := json.Marshal(entry)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
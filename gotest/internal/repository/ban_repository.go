@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// bansCollection — имя файла с блокировками в хранилище
+const bansCollection = "bans"
+
+// BanRepository хранит блокировки пользователей
+type BanRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	bans  map[int64]domain.Ban // Ключ - userID
+}
+
+// NewBanRepository создаёт репозиторий и загружает сохранённые блокировки
+func NewBanRepository(store *JSONStore) (*BanRepository, error) {
+	r := &BanRepository{
+		store: store,
+		bans:  make(map[int64]domain.Ban),
+	}
+
+	if err := store.Load(bansCollection, &r.bans); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает блокировку пользователя
+// Второе значение false, если пользователь не заблокирован
+func (r *BanRepository) Get(userID int64) (domain.Ban, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ban, exists := r.bans[userID]
+	return ban, exists
+}
+
+// Save сохраняет блокировку (заменяет существующую)
+func (r *BanRepository) Save(ban domain.Ban) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bans[ban.UserID] = ban
+	return r.store.Save(bansCollection, r.bans)
+}
+
+// Delete снимает блокировку с пользователя
+// Возвращает false, если пользователь не был заблокирован
+func (r *BanRepository) Delete(userID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.bans[userID]; !exists {
+		return false, nil
+	}
+	delete(r.bans, userID)
+	return true, r.store.Save(bansCollection, r.bans)
+}
+
+// All возвращает все блокировки, начиная с самых новых
+func (r *BanRepository) All() []domain.Ban {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bans := make([]domain.Ban, 0, len(r.bans))
+	for _, ban := range r.bans {
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].CreatedAt.After(bans[j].CreatedAt)
+	})
+	return bans
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// broadcastsCollection — имя файла с заданиями рассылки в хранилище
+const broadcastsCollection = "broadcasts"
+
+// BroadcastRepository хранит задания рассылки
+type BroadcastRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	jobs  map[int64]domain.Broadcast // Ключ - ID задания
+}
+
+// NewBroadcastRepository создаёт репозиторий и загружает сохранённые задания
+func NewBroadcastRepository(store *JSONStore) (*BroadcastRepository, error) {
+	r := &BroadcastRepository{
+		store: store,
+		jobs:  make(map[int64]domain.Broadcast),
+	}
+
+	if err := store.Load(broadcastsCollection, &r.jobs); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Create сохраняет новое задание и присваивает ему ID
+func (r *BroadcastRepository) Create(job *domain.Broadcast) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var maxID int64
+	for id := range r.jobs {
+		if id > maxID {
+			maxID = id
+		}
+	}
+	job.ID = maxID + 1
+	r.jobs[job.ID] = *job
+
+	return r.store.Save(broadcastsCollection, r.jobs)
+}
+
+// Update сохраняет изменения задания
+func (r *BroadcastRepository) Update(job domain.Broadcast) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[job.ID] = job
+	return r.store.Save(broadcastsCollection, r.jobs)
+}
+
+// Running возвращает незавершённые задания, отсортированные по ID
+func (r *BroadcastRepository) Running() []domain.Broadcast {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var jobs []domain.Broadcast
+	for _, job := range r.jobs {
+		if job.Status == domain.BroadcastRunning {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// certificatesCollection — имя файла с выданными сертификатами в хранилище
+const certificatesCollection = "certificates"
+
+// ErrCertificateNotFound — сертификат с таким кодом не выдавался
+var ErrCertificateNotFound = errors.New("сертификат не найден")
+
+// CertificateRepository хранит выданные сертификаты
+type CertificateRepository struct {
+	store        *JSONStore
+	mu           sync.RWMutex
+	certificates map[string]domain.Certificate // Ключ - код проверки
+}
+
+// NewCertificateRepository создаёт репозиторий и загружает выданные сертификаты
+func NewCertificateRepository(store *JSONStore) (*CertificateRepository, error) {
+	r := &CertificateRepository{
+		store:        store,
+		certificates: make(map[string]domain.Certificate),
+	}
+
+	if err := store.Load(certificatesCollection, &r.certificates); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает сертификат по коду проверки
+func (r *CertificateRepository) Get(code string) (domain.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	certificate, exists := r.certificates[code]
+	if !exists {
+		return domain.Certificate{}, ErrCertificateNotFound
+	}
+	return certificate, nil
+}
+
+// ByUserCourse возвращает сертификат пользователя по курсу
+// Второе значение false, если сертификат ещё не выдавался
+func (r *CertificateRepository) ByUserCourse(userID int64, courseID int) (domain.Certificate, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, certificate := range r.certificates {
+		if certificate.UserID == userID && certificate.CourseID == courseID {
+			return certificate, true
+		}
+	}
+	return domain.Certificate{}, false
+}
+
+// Create сохраняет новый сертификат
+// Возвращает false, если код уже занят (сертификат не сохраняется)
+func (r *CertificateRepository) Create(certificate domain.Certificate) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.certificates[certificate.Code]; exists {
+		return false, nil
+	}
+	r.certificates[certificate.Code] = certificate
+
+	return true, r.store.Save(certificatesCollection, r.certificates)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"telegram-bot/internal/domain"
+)
+
+// coursesCollection — имя файла с курсами в хранилище
+const coursesCollection = "courses"
+
+// FileCourseRepository хранит курсы в JSON-файле хранилища
+type FileCourseRepository struct {
+	*MemoryCourseRepository
+}
+
+// NewFileCourseRepository создаёт репозиторий и загружает сохранённые курсы
+func NewFileCourseRepository(store *JSONStore) (*FileCourseRepository, error) {
+	memory := NewMemoryCourseRepository()
+	if err := store.Load(coursesCollection, &memory.courses); err != nil {
+		return nil, err
+	}
+
+	memory.persist = func(courses map[int]domain.Course) error {
+		return store.Save(coursesCollection, courses)
+	}
+
+	return &FileCourseRepository{MemoryCourseRepository: memory}, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+)
+
+// MemoryCourseRepository хранит курсы в памяти
+// Данные теряются при перезапуске; подходит для разработки и как основа FileCourseRepository
+type MemoryCourseRepository struct {
+	mu      sync.RWMutex
+	courses map[int]domain.Course // Ключ - ID курса
+	persist func(map[int]domain.Course) error
+}
+
+// NewMemoryCourseRepository создаёт пустой репозиторий курсов в памяти
+func NewMemoryCourseRepository() *MemoryCourseRepository {
+	return &MemoryCourseRepository{courses: make(map[int]domain.Course)}
+}
+
+// All возвращает все курсы, отсортированные по ID
+func (r *MemoryCourseRepository) All() ([]domain.Course, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	courses := make([]domain.Course, 0, len(r.courses))
+	for _, course := range r.courses {
+		courses = append(courses, cloneCourse(course))
+	}
+	sort.Slice(courses, func(i, j int) bool { return courses[i].ID < courses[j].ID })
+	return courses, nil
+}
+
+// Get возвращает курс по ID
+func (r *MemoryCourseRepository) Get(id int) (domain.Course, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	course, exists := r.courses[id]
+	if !exists {
+		return domain.Course{}, ErrCourseNotFound
+	}
+	return cloneCourse(course), nil
+}
+
+// GetBySlug возвращает курс по slug
+func (r *MemoryCourseRepository) GetBySlug(slug string) (domain.Course, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, course := range r.courses {
+		if course.Slug == slug {
+			return cloneCourse(course), nil
+		}
+	}
+	return domain.Course{}, ErrCourseNotFound
+}
+
+// Save создаёт или обновляет курс
+func (r *MemoryCourseRepository) Save(course *domain.Course) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.courses {
+		if other.Slug == course.Slug && other.ID != course.ID {
+			return ErrCourseSlugTaken
+		}
+	}
+
+	now := time.Now()
+	if course.ID == 0 {
+		for id := range r.courses {
+			course.ID = max(course.ID, id)
+		}
+		course.ID++
+		course.CreatedAt = now
+	} else if existing, exists := r.courses[course.ID]; exists {
+		course.CreatedAt = existing.CreatedAt
+	} else if course.CreatedAt.IsZero() {
+		course.CreatedAt = now
+	}
+	course.UpdatedAt = now
+
+	previous, existed := r.courses[course.ID]
+	r.courses[course.ID] = cloneCourse(*course)
+	if err := r.save(); err != nil {
+		// Откатываем изменение, чтобы память не расходилась с файлом
+		if existed {
+			r.courses[course.ID] = previous
+		} else {
+			delete(r.courses, course.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete удаляет курс
+func (r *MemoryCourseRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	course, exists := r.courses[id]
+	if !exists {
+		return ErrCourseNotFound
+	}
+
+	delete(r.courses, id)
+	if err := r.save(); err != nil {
+		r.courses[id] = course
+		return err
+	}
+	return nil
+}
+
+// save сохраняет курсы, если репозиторий работает поверх файла
+func (r *MemoryCourseRepository) save() error {
+	if r.persist == nil {
+		return nil
+	}
+	return r.persist(r.courses)
+}
+
+// cloneCourse копирует курс вместе с картами переводов
+func cloneCourse(course domain.Course) domain.Course {
+	course.Title = cloneText(course.Title)
+	course.Description = cloneText(course.Description)
+	return course
+}
+
+// cloneText копирует локализованный текст
+func cloneText(text domain.LocalizedText) domain.LocalizedText {
+	if text == nil {
+		return nil
+	}
+	clone := make(domain.LocalizedText, len(text))
+	for lang, value := range text {
+		clone[lang] = value
+	}
+	return clone
+}
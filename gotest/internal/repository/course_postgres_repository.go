@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"telegram-bot/internal/domain"
+)
+
+// coursesSchema создаёт таблицу курсов, если её ещё нет, и добавляет новые столбцы в старую
+const coursesSchema = `
+CREATE TABLE IF NOT EXISTS courses (
+    id               SERIAL PRIMARY KEY,
+    slug             VARCHAR(64) NOT NULL UNIQUE,
+    category         VARCHAR(64) NOT NULL DEFAULT '',
+    level            VARCHAR(16) NOT NULL DEFAULT 'beginner',
+    duration_minutes INTEGER NOT NULL DEFAULT 0,
+    price            BIGINT NOT NULL DEFAULT 0,
+    currency         VARCHAR(8) NOT NULL DEFAULT 'RUB',
+    published        BOOLEAN NOT NULL DEFAULT FALSE,
+    position         INTEGER NOT NULL DEFAULT 0,
+    cover_file_id    TEXT NOT NULL DEFAULT '',
+    title            JSONB NOT NULL DEFAULT '{}',
+    description      JSONB NOT NULL DEFAULT '{}',
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS cover_file_id TEXT NOT NULL DEFAULT ''`
+
+// courseColumns — столбцы курса в порядке сканирования (см. scanCourse)
+const courseColumns = `id, slug, category, level, duration_minutes, price, currency, published, position, cover_file_id, title, description, created_at, updated_at`
+
+// PostgresCourseRepository хранит курсы в PostgreSQL
+type PostgresCourseRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCourseRepository создаёт репозиторий и при необходимости создаёт таблицу courses
+func NewPostgresCourseRepository(db *sql.DB) (*PostgresCourseRepository, error) {
+	if _, err := db.Exec(coursesSchema); err != nil {
+		return nil, fmt.Errorf("ошибка создания таблицы courses: %w", err)
+	}
+	return &PostgresCourseRepository{db: db}, nil
+}
+
+// All возвращает все курсы, отсортированные по ID
+func (r *PostgresCourseRepository) All() ([]domain.Course, error) {
+	rows, err := r.db.Query(`SELECT ` + courseColumns + ` FROM courses ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения курсов: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []domain.Course
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+// Get возвращает курс по ID
+func (r *PostgresCourseRepository) Get(id int) (domain.Course, error) {
+	row := r.db.QueryRow(`SELECT `+courseColumns+` FROM courses WHERE id = $1`, id)
+	return scanCourse(row)
+}
+
+// GetBySlug возвращает курс по slug
+func (r *PostgresCourseRepository) GetBySlug(slug string) (domain.Course, error) {
+	row := r.db.QueryRow(`SELECT `+courseColumns+` FROM courses WHERE slug = $1`, slug)
+	return scanCourse(row)
+}
+
+// Save создаёт или обновляет курс
+func (r *PostgresCourseRepository) Save(course *domain.Course) error {
+	title, err := json.Marshal(course.Title)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации названия курса: %w", err)
+	}
+	description, err := json.Marshal(course.Description)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации описания курса: %w", err)
+	}
+
+	now := time.Now()
+	if course.ID == 0 {
+		err = r.db.QueryRow(`
+			INSERT INTO courses (slug, category, level, duration_minutes, price, currency, published, position, cover_file_id, title, description, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+			RETURNING id, created_at`,
+			course.Slug, course.Category, course.Level, course.DurationMinutes, course.Price,
+			course.Currency, course.Published, course.Position, course.CoverFileID, title, description, now,
+		).Scan(&course.ID, &course.CreatedAt)
+	} else {
+		// Явный ID используется при переносе курсов из файла или начального каталога
+		err = r.db.QueryRow(`
+			INSERT INTO courses (id, slug, category, level, duration_minutes, price, currency, published, position, cover_file_id, title, description, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13)
+			ON CONFLICT (id) DO UPDATE SET
+				slug = EXCLUDED.slug,
+				category = EXCLUDED.category,
+				level = EXCLUDED.level,
+				duration_minutes = EXCLUDED.duration_minutes,
+				price = EXCLUDED.price,
+				currency = EXCLUDED.currency,
+				published = EXCLUDED.published,
+				position = EXCLUDED.position,
+				cover_file_id = EXCLUDED.cover_file_id,
+				title = EXCLUDED.title,
+				description = EXCLUDED.description,
+				updated_at = EXCLUDED.updated_at
+			RETURNING created_at`,
+			course.ID, course.Slug, course.Category, course.Level, course.DurationMinutes, course.Price,
+			course.Currency, course.Published, course.Position, course.CoverFileID, title, description, now,
+		).Scan(&course.CreatedAt)
+		if err == nil {
+			// SERIAL не знает о вставленных вручную ID — сдвигаем последовательность
+			_, err = r.db.Exec(`SELECT setval(pg_get_serial_sequence('courses', 'id'), (SELECT MAX(id) FROM courses))`)
+		}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrCourseSlugTaken
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения курса: %w", err)
+	}
+
+	course.UpdatedAt = now
+	return nil
+}
+
+// Delete удаляет курс
+func (r *PostgresCourseRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM courses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления курса: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка удаления курса: %w", err)
+	}
+	if affected == 0 {
+		return ErrCourseNotFound
+	}
+	return nil
+}
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCourse читает курс из строки результата запроса
+func scanCourse(row rowScanner) (domain.Course, error) {
+	var course domain.Course
+	var title, description []byte
+
+	err := row.Scan(
+		&course.ID,
+		&course.Slug,
+		&course.Category,
+		&course.Level,
+		&course.DurationMinutes,
+		&course.Price,
+		&course.Currency,
+		&course.Published,
+		&course.Position,
+		&course.CoverFileID,
+		&title,
+		&description,
+		&course.CreatedAt,
+		&course.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Course{}, ErrCourseNotFound
+	}
+	if err != nil {
+		return domain.Course{}, fmt.Errorf("ошибка чтения курса: %w", err)
+	}
+
+	if err := json.Unmarshal(title, &course.Title); err != nil {
+		return domain.Course{}, fmt.Errorf("ошибка чтения названия курса %d: %w", course.ID, err)
+	}
+	if err := json.Unmarshal(description, &course.Description); err != nil {
+		return domain.Course{}, fmt.Errorf("ошибка чтения описания курса %d: %w", course.ID, err)
+	}
+	return course, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+
+	"telegram-bot/internal/domain"
+)
+
+var (
+	// ErrCourseNotFound — курс не найден
+	ErrCourseNotFound = errors.New("курс не найден")
+	// ErrCourseSlugTaken — курс с таким slug уже существует
+	ErrCourseSlugTaken = errors.New("курс с таким slug уже существует")
+)
+
+// CourseRepository хранит каталог курсов
+// Реализации: MemoryCourseRepository (в памяти), FileCourseRepository (JSON-файл)
+// и PostgresCourseRepository (PostgreSQL); выбирается через COURSE_STORAGE
+type CourseRepository interface {
+	// All возвращает все курсы, включая неопубликованные, отсортированные по ID
+	All() ([]domain.Course, error)
+	// Get возвращает курс по ID или ErrCourseNotFound
+	Get(id int) (domain.Course, error)
+	// GetBySlug возвращает курс по slug или ErrCourseNotFound
+	GetBySlug(slug string) (domain.Course, error)
+	// Save создаёт курс (если ID = 0, ID назначается автоматически) или обновляет существующий
+	Save(course *domain.Course) error
+	// Delete удаляет курс или возвращает ErrCourseNotFound
+	Delete(id int) error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import "telegram-bot/internal/domain"
+
+// DefaultCourses возвращает начальный каталог курсов
+// Используется, когда хранилище курсов пустое (первый запуск)
+func DefaultCourses() []domain.Course {
+	return []domain.Course{
+		seedCourse(1, "go-basics", "go", domain.LevelBeginner, 600,
+			"Go для начинающих", "Изучите основы языка Go",
+			"Go for Beginners", "Learn the basics of the Go language"),
+		seedCourse(2, "go-advanced", "go", domain.LevelAdvanced, 900,
+			"Продвинутый Go", "Углублённое изучение Go",
+			"Advanced Go", "An in-depth look at Go"),
+		seedCourse(3, "telegram-bot-api", "bots", domain.LevelIntermediate, 480,
+			"Telegram Bot API", "Создание ботов на Go",
+			"Telegram Bot API", "Building bots in Go"),
+		seedCourse(4, "go-databases", "backend", domain.LevelIntermediate, 540,
+			"Базы данных в Go", "Работа с PostgreSQL и MySQL",
+			"Databases in Go", "Working with PostgreSQL and MySQL"),
+		seedCourse(5, "go-microservices", "backend", domain.LevelAdvanced, 720,
+			"Микросервисы на Go", "Архитектура микросервисов",
+			"Microservices in Go", "Microservice architecture"),
+		seedCourse(6, "go-testing", "go", domain.LevelIntermediate, 360,
+			"Тестирование в Go", "Unit и интеграционные тесты",
+			"Testing in Go", "Unit and integration tests"),
+		seedCourse(7, "go-concurrency", "go", domain.LevelAdvanced, 480,
+			"Конкурентность в Go", "Goroutines и Channels",
+			"Concurrency in Go", "Goroutines and channels"),
+		seedCourse(8, "go-rest-api", "backend", domain.LevelIntermediate, 420,
+			"REST API на Go", "Создание RESTful API",
+			"REST APIs in Go", "Building RESTful APIs"),
+		seedCourse(9, "go-docker", "devops", domain.LevelBeginner, 300,
+			"Docker и Go", "Контейнеризация приложений",
+			"Docker and Go", "Containerizing applications"),
+		seedCourse(10, "go-deployment", "devops", domain.LevelIntermediate, 360,
+			"Deployment Go приложений", "Развёртывание на сервере",
+			"Deploying Go Applications", "Deploying to a server"),
+	}
+}
+
+// seedCourse создаёт опубликованный бесплатный курс с переводами на русский и английский
+func seedCourse(id int, slug, category string, level domain.CourseLevel, minutes int, titleRu, descRu, titleEn, descEn string) domain.Course {
+	return domain.Course{
+		ID:              id,
+		Slug:            slug,
+		Category:        category,
+		Level:           level,
+		DurationMinutes: minutes,
+		Currency:        "RUB",
+		Published:       true,
+		Title:           domain.LocalizedText{"ru": titleRu, "en": titleEn},
+		Description:     domain.LocalizedText{"ru": descRu, "en": descEn},
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+)
+
+// dialogsCollection — имя файла с состояниями диалогов в хранилище
+const dialogsCollection = "dialogs"
+
+// DialogRepository хранит состояния многошаговых диалогов
+type DialogRepository struct {
+	store   *JSONStore
+	mu      sync.RWMutex
+	dialogs map[string]domain.DialogState // Ключ - "<ID чата>:<ID пользователя>"
+}
+
+// NewDialogRepository создаёт репозиторий и загружает сохранённые диалоги
+func NewDialogRepository(store *JSONStore) (*DialogRepository, error) {
+	r := &DialogRepository{
+		store:   store,
+		dialogs: make(map[string]domain.DialogState),
+	}
+
+	if err := store.Load(dialogsCollection, &r.dialogs); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает состояние диалога пользователя в чате
+// userID 0 — общее состояние чата; второе значение false, если диалога нет
+func (r *DialogRepository) Get(chatID, userID int64) (domain.DialogState, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state, exists := r.dialogs[dialogKey(chatID, userID)]
+	return state, exists
+}
+
+// Save сохраняет состояние диалога (создаёт или заменяет)
+func (r *DialogRepository) Save(state domain.DialogState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dialogs[dialogKey(state.ChatID, state.UserID)] = state
+	return r.store.Save(dialogsCollection, r.dialogs)
+}
+
+// Delete удаляет состояние диалога
+// Возвращает false, если диалога не было
+func (r *DialogRepository) Delete(chatID, userID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := dialogKey(chatID, userID)
+	if _, exists := r.dialogs[key]; !exists {
+		return false, nil
+	}
+	delete(r.dialogs, key)
+
+	return true, r.store.Save(dialogsCollection, r.dialogs)
+}
+
+// DeleteExpired удаляет диалоги с истёкшим временем ожидания и возвращает их
+func (r *DialogRepository) DeleteExpired(now time.Time) ([]domain.DialogState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []domain.DialogState
+	for key, state := range r.dialogs {
+		if state.Expired(now) {
+			expired = append(expired, state)
+			delete(r.dialogs, key)
+		}
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	return expired, r.store.Save(dialogsCollection, r.dialogs)
+}
+
+// dialogKey возвращает ключ диалога в хранилище
+func dialogKey(chatID, userID int64) string {
+	return fmt.Sprintf("%d:%d", chatID, userID)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// enrollmentsCollection — имя файла с записями на курсы в хранилище
+const enrollmentsCollection = "enrollments"
+
+// quizHistoryCollection — имя файла с результатами тестов курсов, от которых пользователи отписались
+const quizHistoryCollection = "quiz_history"
+
+// EnrollmentRepository хранит записи пользователей на курсы и их прогресс
+// Результаты тестов переживают отмену записи, иначе повторная запись сбрасывала бы лимит попыток
+type EnrollmentRepository struct {
+	store       *JSONStore
+	mu          sync.RWMutex
+	enrollments map[int64][]domain.Enrollment               // Ключ - userID
+	quizHistory map[int64]map[int]map[int]domain.QuizResult // userID -> ID курса -> ID теста
+}
+
+// NewEnrollmentRepository создаёт репозиторий и загружает сохранённые записи
+func NewEnrollmentRepository(store *JSONStore) (*EnrollmentRepository, error) {
+	r := &EnrollmentRepository{
+		store:       store,
+		enrollments: make(map[int64][]domain.Enrollment),
+		quizHistory: make(map[int64]map[int]map[int]domain.QuizResult),
+	}
+
+	if err := store.Load(enrollmentsCollection, &r.enrollments); err != nil {
+		return nil, err
+	}
+	if err := store.Load(quizHistoryCollection, &r.quizHistory); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает запись пользователя на курс
+// Второе значение false, если пользователь не записан
+func (r *EnrollmentRepository) Get(userID int64, courseID int) (domain.Enrollment, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, enrollment := range r.enrollments[userID] {
+		if enrollment.CourseID == courseID {
+			return cloneEnrollment(enrollment), true
+		}
+	}
+	return domain.Enrollment{}, false
+}
+
+// ByUser возвращает курсы пользователя, начиная с последней записи
+func (r *EnrollmentRepository) ByUser(userID int64) []domain.Enrollment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	enrollments := make([]domain.Enrollment, 0, len(r.enrollments[userID]))
+	for _, enrollment := range r.enrollments[userID] {
+		enrollments = append(enrollments, cloneEnrollment(enrollment))
+	}
+	sort.Slice(enrollments, func(i, j int) bool {
+		return enrollments[i].EnrolledAt.After(enrollments[j].EnrolledAt)
+	})
+	return enrollments
+}
+
+// UserIDsByCourse возвращает ID пользователей, записанных на курс
+func (r *EnrollmentRepository) UserIDsByCourse(courseID int) []int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var userIDs []int64
+	for userID, enrollments := range r.enrollments {
+		for _, enrollment := range enrollments {
+			if enrollment.CourseID == courseID {
+				userIDs = append(userIDs, userID)
+				break
+			}
+		}
+	}
+	slices.Sort(userIDs)
+	return userIDs
+}
+
+// Save сохраняет запись на курс (заменяет существующую)
+func (r *EnrollmentRepository) Save(enrollment domain.Enrollment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	enrollments := r.enrollments[enrollment.UserID]
+	i := slices.IndexFunc(enrollments, func(e domain.Enrollment) bool { return e.CourseID == enrollment.CourseID })
+	if i >= 0 {
+		enrollments[i] = cloneEnrollment(enrollment)
+	} else {
+		r.enrollments[enrollment.UserID] = append(enrollments, cloneEnrollment(enrollment))
+	}
+	return r.store.Save(enrollmentsCollection, r.enrollments)
+}
+
+// QuizHistory возвращает результаты тестов курса, сохранённые при отмене записи пользователя
+func (r *EnrollmentRepository) QuizHistory(userID int64, courseID int) map[int]domain.QuizResult {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return maps.Clone(r.quizHistory[userID][courseID])
+}
+
+// Delete удаляет запись пользователя на курс; результаты тестов сохраняются в истории
+// Возвращает false, если пользователь не был записан
+func (r *EnrollmentRepository) Delete(userID int64, courseID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	enrollments := r.enrollments[userID]
+	i := slices.IndexFunc(enrollments, func(e domain.Enrollment) bool { return e.CourseID == courseID })
+	if i < 0 {
+		return false, nil
+	}
+
+	if quizzes := enrollments[i].Quizzes; len(quizzes) > 0 {
+		if r.quizHistory[userID] == nil {
+			r.quizHistory[userID] = make(map[int]map[int]domain.QuizResult)
+		}
+		r.quizHistory[userID][courseID] = maps.Clone(quizzes)
+		if err := r.store.Save(quizHistoryCollection, r.quizHistory); err != nil {
+			return false, err
+		}
+	}
+
+	enrollments = slices.Delete(enrollments, i, i+1)
+	if len(enrollments) == 0 {
+		delete(r.enrollments, userID)
+	} else {
+		r.enrollments[userID] = enrollments
+	}
+	return true, r.store.Save(enrollmentsCollection, r.enrollments)
+}
+
+// cloneEnrollment копирует запись вместе со списком пройденных уроков и результатами тестов
+func cloneEnrollment(enrollment domain.Enrollment) domain.Enrollment {
+	enrollment.CompletedLessons = slices.Clone(enrollment.CompletedLessons)
+	enrollment.Quizzes = maps.Clone(enrollment.Quizzes)
+	return enrollment
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+)
+
+// eventsCollection — имя файла со счётчиками событий в хранилище
+const eventsCollection = "events"
+
+// dayLayout — формат ключа дня в счётчиках событий
+const dayLayout = "2006-01-02"
+
+// EventRepository хранит дневные счётчики событий (команды, callback, ошибки)
+// Счётчики меняются в памяти и сохраняются в хранилище методом Flush
+type EventRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	days  map[string]*domain.DailyEvents // Ключ - день в формате 2006-01-02
+	dirty bool                           // Есть изменения, не сохранённые в хранилище
+}
+
+// NewEventRepository создаёт репозиторий и загружает сохранённые счётчики
+func NewEventRepository(store *JSONStore) (*EventRepository, error) {
+	r := &EventRepository{
+		store: store,
+		days:  make(map[string]*domain.DailyEvents),
+	}
+
+	if err := store.Load(eventsCollection, &r.days); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Add увеличивает счётчик события за день at
+// name - имя команды или маршрута callback (для сообщений и ошибок не используется)
+func (r *EventRepository) Add(eventType domain.EventType, name string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := at.Format(dayLayout)
+	day, exists := r.days[key]
+	if !exists {
+		day = domain.NewDailyEvents()
+		r.days[key] = day
+	}
+
+	switch eventType {
+	case domain.EventCommand:
+		day.Commands[name]++
+	case domain.EventCallback:
+		day.Callbacks[name]++
+	case domain.EventMessage:
+		day.Messages++
+	case domain.EventError:
+		day.Errors++
+	}
+	r.dirty = true
+}
+
+// Flush удаляет счётчики старше days дней (включая день now) и сохраняет изменения в хранилище
+func (r *EventRepository) Flush(now time.Time, days int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Ключи дней сравниваются как строки: формат 2006-01-02 упорядочен так же, как даты
+	oldest := now.AddDate(0, 0, -days+1).Format(dayLayout)
+	for key := range r.days {
+		if key < oldest {
+			delete(r.days, key)
+			r.dirty = true
+		}
+	}
+
+	if !r.dirty {
+		return nil
+	}
+	if err := r.store.Save(eventsCollection, r.days); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// Since возвращает суммарные счётчики за последние days дней, включая день now
+func (r *EventRepository) Since(now time.Time, days int) domain.DailyEvents {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := domain.NewDailyEvents()
+	for i := 0; i < days; i++ {
+		day, exists := r.days[now.AddDate(0, 0, -i).Format(dayLayout)]
+		if !exists {
+			continue
+		}
+		for name, count := range day.Commands {
+			total.Commands[name] += count
+		}
+		for name, count := range day.Callbacks {
+			total.Callbacks[name] += count
+		}
+		total.Messages += day.Messages
+		total.Errors += day.Errors
+	}
+	return *total
+}
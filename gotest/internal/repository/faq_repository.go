@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// faqCollection — имя файла с правилами автоответов в хранилище
+const faqCollection = "faq_rules"
+
+// ErrFAQRuleNotFound — правило автоответа не найдено
+var ErrFAQRuleNotFound = errors.New("правило не найдено")
+
+// FAQRepository хранит правила автоответов
+type FAQRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	rules map[int]domain.FAQRule // Ключ - ID правила
+}
+
+// NewFAQRepository создаёт репозиторий и загружает сохранённые правила
+func NewFAQRepository(store *JSONStore) (*FAQRepository, error) {
+	r := &FAQRepository{
+		store: store,
+		rules: make(map[int]domain.FAQRule),
+	}
+
+	if err := store.Load(faqCollection, &r.rules); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Count возвращает количество правил
+func (r *FAQRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.rules)
+}
+
+// Create сохраняет новое правило и присваивает ему ID
+func (r *FAQRepository) Create(rule *domain.FAQRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	maxID := 0
+	for id := range r.rules {
+		if id > maxID {
+			maxID = id
+		}
+	}
+	rule.ID = maxID + 1
+	r.rules[rule.ID] = *rule
+
+	return r.store.Save(faqCollection, r.rules)
+}
+
+// Get возвращает правило по ID
+func (r *FAQRepository) Get(id int) (domain.FAQRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, exists := r.rules[id]
+	if !exists {
+		return domain.FAQRule{}, ErrFAQRuleNotFound
+	}
+	rule.Patterns = slices.Clone(rule.Patterns)
+	rule.Buttons = slices.Clone(rule.Buttons)
+	return rule, nil
+}
+
+// Update сохраняет изменения правила
+func (r *FAQRepository) Update(rule domain.FAQRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[rule.ID]; !exists {
+		return ErrFAQRuleNotFound
+	}
+	r.rules[rule.ID] = rule
+
+	return r.store.Save(faqCollection, r.rules)
+}
+
+// Delete удаляет правило
+func (r *FAQRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[id]; !exists {
+		return ErrFAQRuleNotFound
+	}
+	delete(r.rules, id)
+
+	return r.store.Save(faqCollection, r.rules)
+}
+
+// All возвращает все правила в порядке проверки: по убыванию приоритета, затем по ID
+func (r *FAQRepository) All() []domain.FAQRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := make([]domain.FAQRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
@@ -0,0 +1,39 @@
+package repository
+
+import "telegram-bot/internal/domain"
+
+// DefaultFAQRules возвращает начальные правила автоответов
+// Используется, когда хранилище правил пустое (первый запуск)
+func DefaultFAQRules() []domain.FAQRule {
+	return []domain.FAQRule{
+		{
+			Name:     "Подписка",
+			Match:    domain.FAQKeyword,
+			Patterns: []string{"подпис", "subscri", "订阅"},
+			Answer: domain.LocalizedText{
+				"ru": "Напишите в поддержку — вам с радостью помогут! 😊",
+				"en": "Contact support — we'll be happy to help! 😊",
+				"zh": "请联系客服——我们很乐意为您提供帮助！😊",
+			},
+			Buttons: []domain.FAQButton{
+				{Text: domain.LocalizedText{"ru": "🆘 Написать в поддержку", "en": "🆘 Contact support", "zh": "🆘 联系客服"}, Data: "sup_open"},
+			},
+			Enabled: true,
+		},
+		{
+			Name:     "Курсы",
+			Match:    domain.FAQFuzzy,
+			Patterns: []string{"какие курсы", "список курсов", "which courses", "course list"},
+			Priority: -10,
+			Answer: domain.LocalizedText{
+				"ru": "📚 Каталог курсов открывается командой /courses — там же работает поиск.",
+				"en": "📚 Open the course catalog with /courses — search works there too.",
+				"zh": "📚 使用 /courses 打开课程目录，也可以在那里搜索。",
+			},
+			Buttons: []domain.FAQButton{
+				{Text: domain.LocalizedText{"ru": "📚 Курсы", "en": "📚 Courses", "zh": "📚 课程"}, Data: "menu_courses"},
+			},
+			Enabled: true,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// featuresCollection — имя файла с флагами функций в хранилище
+const featuresCollection = "features"
+
+// FeatureRepository хранит флаги функций
+type FeatureRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	flags map[string]domain.FeatureFlag // Ключ - имя флага
+}
+
+// NewFeatureRepository создаёт репозиторий и загружает сохранённые флаги
+func NewFeatureRepository(store *JSONStore) (*FeatureRepository, error) {
+	r := &FeatureRepository{
+		store: store,
+		flags: make(map[string]domain.FeatureFlag),
+	}
+
+	if err := store.Load(featuresCollection, &r.flags); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает флаг по имени
+// Второе значение false, если флаг не создавался
+func (r *FeatureRepository) Get(name string) (domain.FeatureFlag, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	flag, exists := r.flags[name]
+	flag.Users = slices.Clone(flag.Users)
+	flag.Locales = slices.Clone(flag.Locales)
+	return flag, exists
+}
+
+// Save сохраняет флаг (заменяет существующий)
+func (r *FeatureRepository) Save(flag domain.FeatureFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.flags[flag.Name] = flag
+	return r.store.Save(featuresCollection, r.flags)
+}
+
+// Delete удаляет флаг
+// Возвращает false, если флага не было
+func (r *FeatureRepository) Delete(name string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.flags[name]; !exists {
+		return false, nil
+	}
+
+	delete(r.flags, name)
+	return true, r.store.Save(featuresCollection, r.flags)
+}
+
+// All возвращает все флаги, отсортированные по имени
+func (r *FeatureRepository) All() []domain.FeatureFlag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	flags := make([]domain.FeatureFlag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONStore хранит коллекции данных в JSON-файлах внутри одного каталога
+// Каждая коллекция — отдельный файл <name>.json
+type JSONStore struct {
+	dir string
+	mu  sync.Mutex // Мьютекс для последовательной записи файлов
+}
+
+// NewJSONStore создаёт хранилище в указанном каталоге
+// Если каталога нет — он будет создан
+func NewJSONStore(dir string) (*JSONStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания каталога хранилища: %w", err)
+	}
+	return &JSONStore{dir: dir}, nil
+}
+
+// Load читает коллекцию name в v
+// Если файла ещё нет — v остаётся без изменений и ошибка не возвращается
+func (s *JSONStore) Load(name string, v any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка чтения %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("ошибка разбора %s: %w", name, err)
+	}
+	return nil
+}
+
+// Save записывает v в коллекцию name
+// Запись атомарная: сначала во временный файл, затем переименование
+func (s *JSONStore) Save(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := s.path(name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("ошибка записи %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, s.path(name)); err != nil {
+		return fmt.Errorf("ошибка сохранения %s: %w", name, err)
+	}
+	return nil
+}
+
+// Append дописывает v отдельной строкой в журнал name (<name>.jsonl)
+// Журнал только дополняется: существующие записи никогда не перезаписываются
+func (s *JSONStore) Append(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.logPath(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия журнала %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи в журнал %s: %w", name, err)
+	}
+	return file.Sync()
+}
+
+// ReadLog вызывает fn для каждой строки журнала name по порядку
+// Если журнала ещё нет — fn не вызывается и ошибка не возвращается
+func (s *JSONStore) ReadLog(name string, fn func(line []byte) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.logPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка открытия журнала %s: %w", name, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения журнала %s: %w", name, err)
+	}
+	return nil
+}
+
+// path возвращает путь к файлу коллекции
+func (s *JSONStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// logPath возвращает путь к файлу журнала
+func (s *JSONStore) logPath(name string) string {
+	return filepath.Join(s.dir, name+".jsonl")
+}
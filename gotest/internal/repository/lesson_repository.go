@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// lessonsCollection — имя файла с уроками в хранилище
+const lessonsCollection = "lessons"
+
+// ErrLessonNotFound — урок с таким ID не существует
+var ErrLessonNotFound = errors.New("урок не найден")
+
+// LessonRepository хранит уроки курсов
+type LessonRepository struct {
+	store   *JSONStore
+	mu      sync.RWMutex
+	lessons map[int]domain.Lesson // Ключ - ID урока
+}
+
+// NewLessonRepository создаёт репозиторий и загружает сохранённые уроки
+func NewLessonRepository(store *JSONStore) (*LessonRepository, error) {
+	r := &LessonRepository{
+		store:   store,
+		lessons: make(map[int]domain.Lesson),
+	}
+
+	if err := store.Load(lessonsCollection, &r.lessons); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Count возвращает общее число уроков
+func (r *LessonRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.lessons)
+}
+
+// Get возвращает урок по ID
+func (r *LessonRepository) Get(id int) (domain.Lesson, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lesson, exists := r.lessons[id]
+	if !exists {
+		return domain.Lesson{}, ErrLessonNotFound
+	}
+	return cloneLesson(lesson), nil
+}
+
+// ByCourse возвращает уроки курса в порядке прохождения
+func (r *LessonRepository) ByCourse(courseID int) []domain.Lesson {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var lessons []domain.Lesson
+	for _, lesson := range r.lessons {
+		if lesson.CourseID == courseID {
+			lessons = append(lessons, cloneLesson(lesson))
+		}
+	}
+	sort.Slice(lessons, func(i, j int) bool {
+		if lessons[i].Position != lessons[j].Position {
+			return lessons[i].Position < lessons[j].Position
+		}
+		return lessons[i].ID < lessons[j].ID
+	})
+	return lessons
+}
+
+// Save сохраняет урок; новому уроку (ID == 0) присваивается ID
+func (r *LessonRepository) Save(lesson *domain.Lesson) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lesson.ID == 0 {
+		for id := range r.lessons {
+			lesson.ID = max(lesson.ID, id)
+		}
+		lesson.ID++
+	}
+	r.lessons[lesson.ID] = cloneLesson(*lesson)
+
+	return r.store.Save(lessonsCollection, r.lessons)
+}
+
+// Delete удаляет урок
+func (r *LessonRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.lessons[id]; !exists {
+		return ErrLessonNotFound
+	}
+	delete(r.lessons, id)
+
+	return r.store.Save(lessonsCollection, r.lessons)
+}
+
+// cloneLesson копирует урок вместе с картами переводов
+func cloneLesson(lesson domain.Lesson) domain.Lesson {
+	lesson.Title = cloneText(lesson.Title)
+	lesson.Body = cloneText(lesson.Body)
+	return lesson
+}
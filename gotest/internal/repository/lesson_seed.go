@@ -0,0 +1,50 @@
+package repository
+
+import "telegram-bot/internal/domain"
+
+// DefaultLessons возвращает начальные уроки для курса «Go для начинающих» (ID 1)
+// Используется, когда хранилище уроков пустое (первый запуск)
+func DefaultLessons() []domain.Lesson {
+	return []domain.Lesson{
+		seedLesson(1, 1, 0,
+			"Установка Go и первая программа", "Installing Go and Your First Program",
+			"Скачайте Go с go.dev/dl и проверьте установку командой `go version`.\n\n"+
+				"```go\npackage main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Привет, Go!\")\n}\n```\n\n"+
+				"Запустите программу: `go run main.go`",
+			"Download Go from go.dev/dl and check the installation with `go version`.\n\n"+
+				"```go\npackage main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello, Go!\")\n}\n```\n\n"+
+				"Run the program: `go run main.go`"),
+		seedLesson(1, 2, 0,
+			"Переменные и типы", "Variables and Types",
+			"Переменные объявляются через `var` или коротким присваиванием `:=`.\n\n"+
+				"```go\nvar name string = \"Gopher\"\nage := 13\nconst pi = 3.14\n```\n\n"+
+				"У каждой переменной есть нулевое значение: 0, \"\", false или nil.",
+			"Variables are declared with `var` or the short assignment `:=`.\n\n"+
+				"```go\nvar name string = \"Gopher\"\nage := 13\nconst pi = 3.14\n```\n\n"+
+				"Every variable has a zero value: 0, \"\", false or nil."),
+		seedLesson(1, 3, 1,
+			"Функции и ошибки", "Functions and Errors",
+			"Функции могут возвращать несколько значений — обычно результат и ошибку.\n\n"+
+				"```go\nfunc divide(a, b float64) (float64, error) {\n\tif b == 0 {\n\t\treturn 0, errors.New(\"деление на ноль\")\n\t}\n\treturn a / b, nil\n}\n```",
+			"Functions can return multiple values — usually a result and an error.\n\n"+
+				"```go\nfunc divide(a, b float64) (float64, error) {\n\tif b == 0 {\n\t\treturn 0, errors.New(\"division by zero\")\n\t}\n\treturn a / b, nil\n}\n```"),
+		seedLesson(1, 4, 2,
+			"Срезы и карты", "Slices and Maps",
+			"Срез — динамический массив, карта — хеш-таблица.\n\n"+
+				"```go\nnums := []int{1, 2, 3}\nnums = append(nums, 4)\n\nages := map[string]int{\"alice\": 30}\nages[\"bob\"] = 25\n```",
+			"A slice is a dynamic array, a map is a hash table.\n\n"+
+				"```go\nnums := []int{1, 2, 3}\nnums = append(nums, 4)\n\nages := map[string]int{\"alice\": 30}\nages[\"bob\"] = 25\n```"),
+	}
+}
+
+// seedLesson создаёт текстовый урок с переводами на русский и английский
+func seedLesson(courseID, position, unlockAfterDays int, titleRu, titleEn, bodyRu, bodyEn string) domain.Lesson {
+	return domain.Lesson{
+		CourseID:        courseID,
+		Position:        position,
+		Kind:            domain.LessonText,
+		Title:           domain.LocalizedText{"ru": titleRu, "en": titleEn},
+		Body:            domain.LocalizedText{"ru": bodyRu, "en": bodyEn},
+		UnlockAfterDays: unlockAfterDays,
+	}
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// maintenanceCollection — имя файла с состоянием режима обслуживания в хранилище
+const maintenanceCollection = "maintenance"
+
+// MaintenanceRepository хранит состояние режима обслуживания
+type MaintenanceRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	state domain.Maintenance
+}
+
+// NewMaintenanceRepository создаёт репозиторий и загружает сохранённое состояние
+func NewMaintenanceRepository(store *JSONStore) (*MaintenanceRepository, error) {
+	r := &MaintenanceRepository{store: store}
+
+	if err := store.Load(maintenanceCollection, &r.state); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает копию текущего состояния
+func (r *MaintenanceRepository) Get() domain.Maintenance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state := r.state
+	state.Messages = make(map[string]string, len(r.state.Messages))
+	for lang, text := range r.state.Messages {
+		state.Messages[lang] = text
+	}
+	return state
+}
+
+// Save сохраняет состояние
+func (r *MaintenanceRepository) Save(state domain.Maintenance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.state = state
+	return r.store.Save(maintenanceCollection, r.state)
+}
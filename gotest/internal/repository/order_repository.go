@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// ordersCollection — имя файла с заказами в хранилище
+const ordersCollection = "orders"
+
+// ErrOrderNotFound — заказ не найден
+var ErrOrderNotFound = errors.New("заказ не найден")
+
+// OrderRepository хранит заказы пользователей
+type OrderRepository struct {
+	store  *JSONStore
+	mu     sync.RWMutex
+	orders map[int]domain.Order // Ключ - ID заказа
+}
+
+// NewOrderRepository создаёт репозиторий и загружает сохранённые заказы
+func NewOrderRepository(store *JSONStore) (*OrderRepository, error) {
+	r := &OrderRepository{
+		store:  store,
+		orders: make(map[int]domain.Order),
+	}
+
+	if err := store.Load(ordersCollection, &r.orders); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Create сохраняет новый заказ и присваивает ему ID
+func (r *OrderRepository) Create(order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	maxID := 0
+	for id := range r.orders {
+		if id > maxID {
+			maxID = id
+		}
+	}
+	order.ID = maxID + 1
+	r.orders[order.ID] = *order
+
+	return r.store.Save(ordersCollection, r.orders)
+}
+
+// Get возвращает заказ по ID
+func (r *OrderRepository) Get(id int) (domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, exists := r.orders[id]
+	if !exists {
+		return domain.Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// Update сохраняет изменения заказа
+func (r *OrderRepository) Update(order domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; !exists {
+		return ErrOrderNotFound
+	}
+	r.orders[order.ID] = order
+
+	return r.store.Save(ordersCollection, r.orders)
+}
+
+// List возвращает заказы пользователя userID (0 — всех пользователей), новые первыми
+func (r *OrderRepository) List(userID int64) []domain.Order {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var orders []domain.Order
+	for _, order := range r.orders {
+		if userID == 0 || order.UserID == userID {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID > orders[j].ID })
+	return orders
+}
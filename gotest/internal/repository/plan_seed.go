@@ -0,0 +1,30 @@
+package repository
+
+import "telegram-bot/internal/domain"
+
+// DefaultPlans возвращает начальные тарифные планы
+// Используется, когда хранилище планов пустое (первый запуск)
+func DefaultPlans() []domain.Plan {
+	return []domain.Plan{
+		{
+			ID:       "month",
+			Title:    domain.LocalizedText{"ru": "Месяц", "en": "Month", "zh": "月度"},
+			Days:     30,
+			Price:    150,
+			Currency: "XTR",
+			Features: []string{domain.PlanPaidCourses, domain.PlanPremiumCommands},
+			Active:   true,
+			Position: 1,
+		},
+		{
+			ID:       "year",
+			Title:    domain.LocalizedText{"ru": "Год", "en": "Year", "zh": "年度"},
+			Days:     365,
+			Price:    1500,
+			Currency: "XTR",
+			Features: []string{domain.PlanPaidCourses, domain.PlanPremiumCommands},
+			Active:   true,
+			Position: 2,
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	// Драйвер PostgreSQL регистрирует себя при импорте
+	_ "github.com/lib/pq"
+
+	"telegram-bot/internal/config"
+)
+
+// PostgresDB — обёртка над подключением к PostgreSQL
+type PostgresDB struct {
+	DB *sql.DB
+}
+
+// NewPostgresDB создаёт новое подключение к PostgreSQL
+func NewPostgresDB(cfg config.DatabaseConfig) (*PostgresDB, error) {
+	connStr := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Password,
+		cfg.Name,
+		cfg.SSLMode,
+	)
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия БД: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ошибка подключения к БД: %w", err)
+	}
+
+	return &PostgresDB{DB: db}, nil
+}
+
+// Close закрывает соединение с базой данных
+func (p *PostgresDB) Close() error {
+	return p.DB.Close()
+}
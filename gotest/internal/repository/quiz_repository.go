@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"slices"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// quizzesCollection — имя файла с тестами в хранилище
+const quizzesCollection = "quizzes"
+
+// QuizRepository хранит тесты уроков (не больше одного теста на урок)
+type QuizRepository struct {
+	store   *JSONStore
+	mu      sync.RWMutex
+	quizzes map[int]domain.Quiz // Ключ - ID теста
+}
+
+// NewQuizRepository создаёт репозиторий и загружает сохранённые тесты
+func NewQuizRepository(store *JSONStore) (*QuizRepository, error) {
+	r := &QuizRepository{
+		store:   store,
+		quizzes: make(map[int]domain.Quiz),
+	}
+
+	if err := store.Load(quizzesCollection, &r.quizzes); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Count возвращает общее число тестов
+func (r *QuizRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.quizzes)
+}
+
+// ByLesson возвращает тест урока
+// Второе значение false, если у урока нет теста
+func (r *QuizRepository) ByLesson(lessonID int) (domain.Quiz, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, quiz := range r.quizzes {
+		if quiz.LessonID == lessonID {
+			return cloneQuiz(quiz), true
+		}
+	}
+	return domain.Quiz{}, false
+}
+
+// Save сохраняет тест; новому тесту (ID == 0) присваивается ID
+func (r *QuizRepository) Save(quiz *domain.Quiz) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if quiz.ID == 0 {
+		for id := range r.quizzes {
+			quiz.ID = max(quiz.ID, id)
+		}
+		quiz.ID++
+	}
+	r.quizzes[quiz.ID] = cloneQuiz(*quiz)
+
+	return r.store.Save(quizzesCollection, r.quizzes)
+}
+
+// Delete удаляет тест
+// Возвращает false, если теста не было
+func (r *QuizRepository) Delete(id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.quizzes[id]; !exists {
+		return false, nil
+	}
+	delete(r.quizzes, id)
+
+	return true, r.store.Save(quizzesCollection, r.quizzes)
+}
+
+// cloneQuiz копирует тест вместе с вопросами
+func cloneQuiz(quiz domain.Quiz) domain.Quiz {
+	quiz.Questions = slices.Clone(quiz.Questions)
+	for i := range quiz.Questions {
+		quiz.Questions[i].Options = slices.Clone(quiz.Questions[i].Options)
+		quiz.Questions[i].Correct = slices.Clone(quiz.Questions[i].Correct)
+	}
+	return quiz
+}
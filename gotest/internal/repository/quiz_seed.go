@@ -0,0 +1,56 @@
+package repository
+
+import "telegram-bot/internal/domain"
+
+// DefaultQuizzes возвращает начальные тесты для уроков курса «Go для начинающих» (ID 1)
+// Используется, когда хранилище тестов пустое (первый запуск)
+func DefaultQuizzes() []domain.Quiz {
+	return []domain.Quiz{
+		{
+			CourseID:    1,
+			LessonID:    2,
+			Mode:        domain.QuizButtons,
+			PassPercent: 70,
+			MaxAttempts: 3,
+			Questions: []domain.QuizQuestion{
+				{
+					Text:        "Какой оператор объявляет переменную с выводом типа?",
+					Options:     []string{"=", ":=", "=>", "<-"},
+					Correct:     []int{1},
+					Explanation: "Короткое присваивание := объявляет переменную и выводит её тип.",
+				},
+				{
+					Text:        "Какие значения являются нулевыми в Go?",
+					Options:     []string{"0 для int", "\"\" для string", "nil для bool", "false для bool"},
+					Correct:     []int{0, 1, 3},
+					Explanation: "Нулевое значение bool — false, а nil — у указателей, срезов, карт и интерфейсов.",
+				},
+				{
+					Text:    "Можно ли изменить значение константы после объявления?",
+					Options: []string{"Да", "Нет"},
+					Correct: []int{1},
+				},
+			},
+		},
+		{
+			CourseID:    1,
+			LessonID:    4,
+			Mode:        domain.QuizPoll,
+			PassPercent: 50,
+			MaxAttempts: 0,
+			Questions: []domain.QuizQuestion{
+				{
+					Text:        "Какая функция добавляет элемент в срез?",
+					Options:     []string{"push", "append", "add", "insert"},
+					Correct:     []int{1},
+					Explanation: "append возвращает новый срез — результат нужно присвоить.",
+				},
+				{
+					Text:    "Какие типы являются ссылочными?",
+					Options: []string{"slice", "map", "int", "struct"},
+					Correct: []int{0, 1},
+				},
+			},
+		},
+	}
+}
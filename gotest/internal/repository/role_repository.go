@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// rolesCollection — имя файла с ролями в хранилище
+const rolesCollection = "roles"
+
+// RoleRepository хранит роли пользователей
+// Пользователи без записи считаются обычными (domain.RoleUser)
+type RoleRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	roles map[int64]domain.Role // Ключ - userID, значение - роль
+}
+
+// NewRoleRepository создаёт репозиторий и загружает сохранённые роли
+func NewRoleRepository(store *JSONStore) (*RoleRepository, error) {
+	r := &RoleRepository{
+		store: store,
+		roles: make(map[int64]domain.Role),
+	}
+
+	if err := store.Load(rolesCollection, &r.roles); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает сохранённую роль пользователя
+// Второе значение false, если роль не назначалась
+func (r *RoleRepository) Get(userID int64) (domain.Role, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, exists := r.roles[userID]
+	return role, exists
+}
+
+// Set назначает пользователю роль
+func (r *RoleRepository) Set(userID int64, role domain.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.roles[userID] = role
+	return r.saveLocked()
+}
+
+// Delete удаляет роль пользователя (он снова становится обычным)
+func (r *RoleRepository) Delete(userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.roles, userID)
+	return r.saveLocked()
+}
+
+// All возвращает копию всех назначенных ролей
+func (r *RoleRepository) All() map[int64]domain.Role {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[int64]domain.Role, len(r.roles))
+	for userID, role := range r.roles {
+		result[userID] = role
+	}
+	return result
+}
+
+// saveLocked сохраняет роли в хранилище (вызывается под r.mu)
+func (r *RoleRepository) saveLocked() error {
+	return r.store.Save(rolesCollection, r.roles)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// schedulesCollection — имя файла с запланированными рассылками в хранилище
+const schedulesCollection = "schedules"
+
+// ScheduleRepository хранит запланированные рассылки
+type ScheduleRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	jobs  map[int64]domain.ScheduledBroadcast // Ключ - ID задания
+}
+
+// NewScheduleRepository создаёт репозиторий и загружает сохранённые задания
+func NewScheduleRepository(store *JSONStore) (*ScheduleRepository, error) {
+	r := &ScheduleRepository{
+		store: store,
+		jobs:  make(map[int64]domain.ScheduledBroadcast),
+	}
+
+	if err := store.Load(schedulesCollection, &r.jobs); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Create сохраняет новое задание и присваивает ему ID
+func (r *ScheduleRepository) Create(job *domain.ScheduledBroadcast) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var maxID int64
+	for id := range r.jobs {
+		if id > maxID {
+			maxID = id
+		}
+	}
+	job.ID = maxID + 1
+	r.jobs[job.ID] = *job
+
+	return r.store.Save(schedulesCollection, r.jobs)
+}
+
+// Get возвращает задание по ID
+// Второе значение false, если задание не найдено
+func (r *ScheduleRepository) Get(id int64) (domain.ScheduledBroadcast, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, exists := r.jobs[id]
+	return job, exists
+}
+
+// Update сохраняет изменения задания
+func (r *ScheduleRepository) Update(job domain.ScheduledBroadcast) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[job.ID] = job
+	return r.store.Save(schedulesCollection, r.jobs)
+}
+
+// Pending возвращает активные и приостановленные задания, отсортированные по времени запуска
+func (r *ScheduleRepository) Pending() []domain.ScheduledBroadcast {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var jobs []domain.ScheduledBroadcast
+	for _, job := range r.jobs {
+		if job.Status == domain.ScheduleActive || job.Status == domain.SchedulePaused {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].NextRunAt.Before(jobs[j].NextRunAt)
+	})
+	return jobs
+}
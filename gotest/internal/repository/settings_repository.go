@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+// settingsCollection — имя файла с настройками пользователей в хранилище
+const settingsCollection = "settings"
+
+// SettingsRepository хранит настройки пользователей (язык, уведомления)
+type SettingsRepository struct {
+	store    *JSONStore
+	mu       sync.RWMutex
+	settings map[int64]domain.UserSettings // Ключ - chatID
+}
+
+// NewSettingsRepository создаёт репозиторий и загружает сохранённые настройки
+func NewSettingsRepository(store *JSONStore) (*SettingsRepository, error) {
+	r := &SettingsRepository{
+		store:    store,
+		settings: make(map[int64]domain.UserSettings),
+	}
+
+	if err := store.Load(settingsCollection, &r.settings); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get возвращает настройки пользователя
+// Если настройки не сохранены, возвращает значения по умолчанию
+func (r *SettingsRepository) Get(userID int64) domain.UserSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings, exists := r.settings[userID]
+	if !exists {
+		return domain.DefaultUserSettings(userID)
+	}
+	return settings
+}
+
+// SetLanguage сохраняет язык интерфейса пользователя
+func (r *SettingsRepository) SetLanguage(userID int64, lang string) error {
+	return r.update(userID, func(s *domain.UserSettings) {
+		s.Language = lang
+	})
+}
+
+// SetNotifications сохраняет состояние уведомлений пользователя
+func (r *SettingsRepository) SetNotifications(userID int64, enabled bool) error {
+	return r.update(userID, func(s *domain.UserSettings) {
+		s.NotificationsEnabled = enabled
+	})
+}
+
+// Reset сбрасывает настройки пользователя к значениям по умолчанию
+func (r *SettingsRepository) Reset(userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.settings, userID)
+	return r.store.Save(settingsCollection, r.settings)
+}
+
+// All возвращает копию всех сохранённых настроек
+func (r *SettingsRepository) All() map[int64]domain.UserSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[int64]domain.UserSettings, len(r.settings))
+	for userID, settings := range r.settings {
+		result[userID] = settings
+	}
+	return result
+}
+
+// update изменяет настройки пользователя и сохраняет их
+func (r *SettingsRepository) update(userID int64, change func(*domain.UserSettings)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings, exists := r.settings[userID]
+	if !exists {
+		settings = domain.DefaultUserSettings(userID)
+	}
+	change(&settings)
+	r.settings[userID] = settings
+
+	return r.store.Save(settingsCollection, r.settings)
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+const (
+	// plansCollection — имя файла с тарифными планами в хранилище
+	plansCollection = "plans"
+	// subscriptionsCollection — имя файла с подписками пользователей в хранилище
+	subscriptionsCollection = "subscriptions"
+)
+
+// ErrPlanNotFound — тарифный план не найден
+var ErrPlanNotFound = errors.New("тарифный план не найден")
+
+// SubscriptionRepository хранит тарифные планы и подписки пользователей
+type SubscriptionRepository struct {
+	store         *JSONStore
+	mu            sync.RWMutex
+	plans         map[string]domain.Plan        // Ключ - ID плана
+	subscriptions map[int64]domain.Subscription // Ключ - ID пользователя
+}
+
+// NewSubscriptionRepository создаёт репозиторий и загружает сохранённые планы и подписки
+func NewSubscriptionRepository(store *JSONStore) (*SubscriptionRepository, error) {
+	r := &SubscriptionRepository{
+		store:         store,
+		plans:         make(map[string]domain.Plan),
+		subscriptions: make(map[int64]domain.Subscription),
+	}
+
+	if err := store.Load(plansCollection, &r.plans); err != nil {
+		return nil, err
+	}
+	if err := store.Load(subscriptionsCollection, &r.subscriptions); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// PlanCount возвращает количество тарифных планов
+func (r *SubscriptionRepository) PlanCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.plans)
+}
+
+// SavePlan сохраняет тарифный план (заменяет существующий)
+func (r *SubscriptionRepository) SavePlan(plan domain.Plan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.plans[plan.ID] = plan
+	return r.store.Save(plansCollection, r.plans)
+}
+
+// Plan возвращает тарифный план по ID
+func (r *SubscriptionRepository) Plan(id string) (domain.Plan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plan, exists := r.plans[id]
+	if !exists {
+		return domain.Plan{}, ErrPlanNotFound
+	}
+	plan.Features = slices.Clone(plan.Features)
+	return plan, nil
+}
+
+// Plans возвращает все тарифные планы в порядке отображения
+func (r *SubscriptionRepository) Plans() []domain.Plan {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plans := make([]domain.Plan, 0, len(r.plans))
+	for _, plan := range r.plans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].Position != plans[j].Position {
+			return plans[i].Position < plans[j].Position
+		}
+		return plans[i].ID < plans[j].ID
+	})
+	return plans
+}
+
+// Get возвращает подписку пользователя
+// Второе значение false, если пользователь никогда не был подписан
+func (r *SubscriptionRepository) Get(userID int64) (domain.Subscription, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscription, exists := r.subscriptions[userID]
+	return subscription, exists
+}
+
+// Save сохраняет подписку пользователя (заменяет существующую)
+func (r *SubscriptionRepository) Save(subscription domain.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions[subscription.UserID] = subscription
+	return r.store.Save(subscriptionsCollection, r.subscriptions)
+}
+
+// All возвращает подписки всех пользователей
+func (r *SubscriptionRepository) All() []domain.Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]domain.Subscription, 0, len(r.subscriptions))
+	for _, subscription := range r.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions
+}
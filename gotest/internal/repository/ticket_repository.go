@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"telegram-bot/internal/domain"
+)
+
+const (
+	// ticketsCollection — имя файла с обращениями в поддержку в хранилище
+	ticketsCollection = "tickets"
+	// ticketLinksCollection — имя файла со связями сообщений и обращений в хранилище
+	ticketLinksCollection = "ticket_links"
+)
+
+// ErrTicketNotFound — обращение не найдено
+var ErrTicketNotFound = errors.New("обращение не найдено")
+
+// TicketRepository хранит обращения в поддержку и связи сообщений с ними
+type TicketRepository struct {
+	store   *JSONStore
+	mu      sync.RWMutex
+	tickets map[int]domain.Ticket // Ключ - ID обращения
+	links   map[string]int        // Ключ - "<ID чата>:<ID сообщения>", значение - ID обращения
+}
+
+// NewTicketRepository создаёт репозиторий и загружает сохранённые обращения
+func NewTicketRepository(store *JSONStore) (*TicketRepository, error) {
+	r := &TicketRepository{
+		store:   store,
+		tickets: make(map[int]domain.Ticket),
+		links:   make(map[string]int),
+	}
+
+	if err := store.Load(ticketsCollection, &r.tickets); err != nil {
+		return nil, err
+	}
+	if err := store.Load(ticketLinksCollection, &r.links); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Create сохраняет новое обращение и присваивает ему ID
+func (r *TicketRepository) Create(ticket *domain.Ticket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	maxID := 0
+	for id := range r.tickets {
+		if id > maxID {
+			maxID = id
+		}
+	}
+	ticket.ID = maxID + 1
+	r.tickets[ticket.ID] = *ticket
+
+	return r.store.Save(ticketsCollection, r.tickets)
+}
+
+// Get возвращает обращение по ID
+func (r *TicketRepository) Get(id int) (domain.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ticket, exists := r.tickets[id]
+	if !exists {
+		return domain.Ticket{}, ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+// Update сохраняет изменения обращения
+func (r *TicketRepository) Update(ticket domain.Ticket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tickets[ticket.ID]; !exists {
+		return ErrTicketNotFound
+	}
+	r.tickets[ticket.ID] = ticket
+	return r.store.Save(ticketsCollection, r.tickets)
+}
+
+// ActiveByUser возвращает незакрытое обращение пользователя
+// Второе значение false, если такого обращения нет
+func (r *TicketRepository) ActiveByUser(userID int64) (domain.Ticket, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, ticket := range r.tickets {
+		if ticket.UserID == userID && ticket.Active() {
+			return ticket, true
+		}
+	}
+	return domain.Ticket{}, false
+}
+
+// List возвращает обращения со статусом status (пустой — все), новые первыми
+func (r *TicketRepository) List(status domain.TicketStatus) []domain.Ticket {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tickets []domain.Ticket
+	for _, ticket := range r.tickets {
+		if status == "" || ticket.Status == status {
+			tickets = append(tickets, ticket)
+		}
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].ID > tickets[j].ID
+	})
+	return tickets
+}
+
+// Link связывает сообщение в чате с обращением
+func (r *TicketRepository) Link(link domain.TicketLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.links[linkKey(link.ChatID, link.MessageID)] = link.TicketID
+	return r.store.Save(ticketLinksCollection, r.links)
+}
+
+// ByMessage возвращает ID обращения, с которым связано сообщение
+// Второе значение false, если сообщение не связано ни с одним обращением
+func (r *TicketRepository) ByMessage(chatID int64, messageID int) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.links[linkKey(chatID, messageID)]
+	return id, exists
+}
+
+// linkKey возвращает ключ связи сообщения с обращением
+func linkKey(chatID int64, messageID int) string {
+	return fmt.Sprintf("%d:%d", chatID, messageID)
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+)
+
+// usersCollection — имя файла с пользователями в хранилище
+const usersCollection = "users"
+
+// lastSeenPrecision — с какой точностью сохраняется время последней активности
+// Чтобы не переписывать файл на каждое сообщение
+const lastSeenPrecision = time.Minute
+
+// UserRepository хранит пользователей бота
+type UserRepository struct {
+	store *JSONStore
+	mu    sync.RWMutex
+	users map[int64]*domain.User // Ключ - userID
+}
+
+// NewUserRepository создаёт репозиторий и загружает сохранённых пользователей
+func NewUserRepository(store *JSONStore) (*UserRepository, error) {
+	r := &UserRepository{
+		store: store,
+		users: make(map[int64]*domain.User),
+	}
+
+	if err := store.Load(usersCollection, &r.users); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Touch создаёт пользователя или обновляет его данные и время активности
+// Возвращает true, если пользователь обратился к боту впервые
+func (r *UserRepository) Touch(from *tgbotapi.User, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[from.ID]
+	if !exists {
+		user = &domain.User{ID: from.ID, CreatedAt: now}
+		r.users[from.ID] = user
+	}
+
+	changed := !exists ||
+		user.Username != from.UserName ||
+		user.FirstName != from.FirstName ||
+		user.LastName != from.LastName ||
+		user.LanguageCode != from.LanguageCode ||
+		now.Sub(user.LastSeenAt) >= lastSeenPrecision
+
+	if !changed {
+		return false, nil
+	}
+
+	user.Username = from.UserName
+	user.FirstName = from.FirstName
+	user.LastName = from.LastName
+	user.LanguageCode = from.LanguageCode
+	user.LastSeenAt = now
+
+	return !exists, r.store.Save(usersCollection, r.users)
+}
+
+// GetByID возвращает пользователя по ID
+// Возвращает nil, если пользователь не найден
+func (r *UserRepository) GetByID(id int64) *domain.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, exists := r.users[id]
+	if !exists {
+		return nil
+	}
+	copied := *user
+	return &copied
+}
+
+// GetByUsername возвращает пользователя по username (без учёта регистра и @)
+// Возвращает nil, если пользователь не найден
+func (r *UserRepository) GetByUsername(username string) *domain.User {
+	username = strings.TrimPrefix(username, "@")
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Username, username) {
+			copied := *user
+			return &copied
+		}
+	}
+	return nil
+}
+
+// All возвращает копии всех пользователей, отсортированные по дате регистрации
+func (r *UserRepository) All() []domain.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+	return users
+}
+
+// Count возвращает количество пользователей
+func (r *UserRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.users)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// deniedWindow — как часто в журнал попадает отказ в доступе одному пользователю
+// Остальные попытки за это время только подсчитываются и записываются вместе со следующим отказом
+const deniedWindow = 10 * time.Minute
+
+var (
+	// ErrForbidden — у пользователя недостаточно прав для действия
+	ErrForbidden = errors.New("недостаточно прав")
+	// ErrOwnerRole — роль владельца нельзя выдать или отозвать через бота
+	ErrOwnerRole = errors.New("роль владельца задаётся только через ADMIN_IDS")
+)
+
+// AccessService отвечает за роли и проверку разрешений (RBAC)
+type AccessService struct {
+	owners map[int64]struct{}         // Владельцы из ADMIN_IDS
+	roles  *repository.RoleRepository // Роли, выданные через бота
+	audit  *AuditService              // Журнал изменений ролей
+
+	deniedMu sync.Mutex
+	denied   map[int64]*deniedAttempts // Ключ - ID пользователя
+}
+
+// deniedAttempts — отказы в доступе пользователю, ещё не записанные в журнал
+type deniedAttempts struct {
+	until      time.Time // До какого момента новые отказы не записываются
+	suppressed int       // Сколько отказов пропущено
+}
+
+// NewAccessService создаёт сервис доступа
+// ownerIDs - ID владельцев из конфигурации (ADMIN_IDS)
+func NewAccessService(ownerIDs []int64, roles *repository.RoleRepository, audit *AuditService) *AccessService {
+	owners := make(map[int64]struct{}, len(ownerIDs))
+	for _, id := range ownerIDs {
+		owners[id] = struct{}{}
+	}
+
+	return &AccessService{
+		owners: owners,
+		roles:  roles,
+		audit:  audit,
+		denied: make(map[int64]*deniedAttempts),
+	}
+}
+
+// RoleOf возвращает роль пользователя
+// Владельцы из ADMIN_IDS всегда имеют роль owner
+func (s *AccessService) RoleOf(userID int64) domain.Role {
+	if _, ok := s.owners[userID]; ok {
+		return domain.RoleOwner
+	}
+	if role, ok := s.roles.Get(userID); ok {
+		return role
+	}
+	return domain.RoleUser
+}
+
+// Can проверяет, есть ли у пользователя разрешение
+func (s *AccessService) Can(userID int64, perm domain.Permission) bool {
+	return s.RoleOf(userID).Has(perm)
+}
+
+// RecordDenied записывает в журнал попытку userID выполнить request без разрешения perm
+// Один пользователь попадает в журнал не чаще раза в deniedWindow, чтобы нельзя было засорить журнал;
+// число пропущенных попыток записывается в следующую запись
+func (s *AccessService) RecordDenied(userID int64, perm domain.Permission, request string) {
+	now := time.Now()
+
+	s.deniedMu.Lock()
+	attempts, exists := s.denied[userID]
+	if exists && now.Before(attempts.until) {
+		attempts.suppressed++
+		s.deniedMu.Unlock()
+		return
+	}
+	suppressed := 0
+	if exists {
+		suppressed = attempts.suppressed
+	}
+	// Окно без пропущенных попыток больше не нужно; пропущенные ждут следующего отказа пользователю
+	for id, other := range s.denied {
+		if !now.Before(other.until) && other.suppressed == 0 {
+			delete(s.denied, id)
+		}
+	}
+	s.denied[userID] = &deniedAttempts{until: now.Add(deniedWindow)}
+	s.deniedMu.Unlock()
+
+	params := map[string]string{
+		"permission": string(perm),
+		"request":    request,
+	}
+	if suppressed > 0 {
+		params["suppressed"] = strconv.Itoa(suppressed)
+	}
+	s.audit.Record(userID, domain.AuditAccessDenied, 0, params, ErrForbidden)
+}
+
+// Grant выдаёт пользователю targetID роль role от имени actorID
+// Выдавать можно только роли ниже собственной
+func (s *AccessService) Grant(actorID, targetID int64, role domain.Role) error {
+	err := s.grant(actorID, targetID, role)
+	s.audit.Record(actorID, domain.AuditRoleGrant, targetID, map[string]string{"role": string(role)}, err)
+	return err
+}
+
+// Revoke отзывает у пользователя targetID роль от имени actorID
+func (s *AccessService) Revoke(actorID, targetID int64) error {
+	role := s.RoleOf(targetID)
+	err := s.revoke(actorID, targetID)
+	s.audit.Record(actorID, domain.AuditRoleRevoke, targetID, map[string]string{"role": string(role)}, err)
+	return err
+}
+
+// grant выполняет выдачу роли без записи в журнал
+func (s *AccessService) grant(actorID, targetID int64, role domain.Role) error {
+	if role == domain.RoleOwner {
+		return ErrOwnerRole
+	}
+	if err := s.checkManage(actorID, targetID); err != nil {
+		return err
+	}
+	if role.Level() >= s.RoleOf(actorID).Level() {
+		return fmt.Errorf("%w: нельзя выдать роль %s", ErrForbidden, role)
+	}
+
+	if role == domain.RoleUser {
+		return s.roles.Delete(targetID)
+	}
+	return s.roles.Set(targetID, role)
+}
+
+// revoke выполняет отзыв роли без записи в журнал
+func (s *AccessService) revoke(actorID, targetID int64) error {
+	if err := s.checkManage(actorID, targetID); err != nil {
+		return err
+	}
+	return s.roles.Delete(targetID)
+}
+
+// Staff возвращает всех пользователей с ролью выше обычной
+func (s *AccessService) Staff() map[int64]domain.Role {
+	staff := s.roles.All()
+	for id := range s.owners {
+		staff[id] = domain.RoleOwner
+	}
+	return staff
+}
+
+// checkManage проверяет, может ли actorID менять роль targetID
+func (s *AccessService) checkManage(actorID, targetID int64) error {
+	if !s.Can(actorID, domain.PermRolesManage) {
+		return ErrForbidden
+	}
+	if _, ok := s.owners[targetID]; ok {
+		return ErrOwnerRole
+	}
+	if s.RoleOf(targetID).Level() >= s.RoleOf(actorID).Level() {
+		return fmt.Errorf("%w: роль пользователя не ниже вашей", ErrForbidden)
+	}
+	return nil
+}
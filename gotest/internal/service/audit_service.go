@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// AuditService ведёт журнал действий администраторов
+type AuditService struct {
+	entries *repository.AuditRepository
+}
+
+// NewAuditService создаёт сервис аудита
+func NewAuditService(entries *repository.AuditRepository) *AuditService {
+	return &AuditService{entries: entries}
+}
+
+// Record записывает действие actorID над targetID в журнал
+// Результат определяется по err: nil — успех, ErrForbidden — отказ в доступе, иначе — ошибка
+// Сбой записи в журнал не прерывает действие, а только логируется
+func (s *AuditService) Record(actorID int64, action string, targetID int64, params map[string]string, err error) {
+	entry := domain.AuditEntry{
+		At:       time.Now().UTC(),
+		ActorID:  actorID,
+		Action:   action,
+		TargetID: targetID,
+		Params:   params,
+		Outcome:  domain.AuditSuccess,
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrForbidden):
+		entry.Outcome = domain.AuditDenied
+		entry.Error = err.Error()
+	default:
+		entry.Outcome = domain.AuditFailure
+		entry.Error = err.Error()
+	}
+
+	if err := s.entries.Append(&entry); err != nil {
+		log.Printf("Ошибка записи в журнал аудита (%s от %d): %v", action, actorID, err)
+	}
+}
+
+// Find возвращает записи журнала, подходящие под фильтр
+func (s *AuditService) Find(filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	return s.entries.Find(filter)
+}
+
+// Verify проверяет целостность журнала
+// Возвращает ID первой изменённой записи или 0, если журнал не изменялся
+func (s *AuditService) Verify() (int64, error) {
+	return s.entries.Verify()
+}
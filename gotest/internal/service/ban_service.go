@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// ErrNotBanned — пользователь не заблокирован
+var ErrNotBanned = errors.New("пользователь не заблокирован")
+
+// BanService управляет блокировками пользователей
+type BanService struct {
+	bans   *repository.BanRepository
+	access *AccessService
+	audit  *AuditService
+}
+
+// NewBanService создаёт сервис блокировок
+func NewBanService(bans *repository.BanRepository, access *AccessService, audit *AuditService) *BanService {
+	return &BanService{
+		bans:   bans,
+		access: access,
+		audit:  audit,
+	}
+}
+
+// Ban блокирует пользователя targetID от имени actorID
+// duration - срок блокировки; 0 означает бессрочную блокировку
+// Нельзя заблокировать пользователя, чья роль не ниже роли администратора
+func (s *BanService) Ban(actorID, targetID int64, reason string, duration time.Duration) (domain.Ban, error) {
+	ban, err := s.ban(actorID, targetID, reason, duration)
+	s.audit.Record(actorID, domain.AuditUserBan, targetID, map[string]string{
+		"reason":   reason,
+		"duration": banDuration(duration),
+	}, err)
+	return ban, err
+}
+
+// ban выполняет блокировку без записи в журнал
+func (s *BanService) ban(actorID, targetID int64, reason string, duration time.Duration) (domain.Ban, error) {
+	if s.access.RoleOf(targetID).Level() >= s.access.RoleOf(actorID).Level() {
+		return domain.Ban{}, ErrForbidden
+	}
+
+	now := time.Now()
+	ban := domain.Ban{
+		UserID:    targetID,
+		Reason:    reason,
+		BannedBy:  actorID,
+		CreatedAt: now,
+	}
+	if duration > 0 {
+		ban.ExpiresAt = now.Add(duration)
+	}
+	if err := s.bans.Save(ban); err != nil {
+		return domain.Ban{}, err
+	}
+
+	log.Printf("Пользователь %d заблокирован администратором %d (до %s): %s", targetID, actorID, banUntil(ban), reason)
+	return ban, nil
+}
+
+// Unban снимает блокировку с пользователя targetID от имени actorID
+func (s *BanService) Unban(actorID, targetID int64) error {
+	err := s.unban(actorID, targetID)
+	s.audit.Record(actorID, domain.AuditUserUnban, targetID, nil, err)
+	return err
+}
+
+// unban снимает блокировку без записи в журнал
+func (s *BanService) unban(actorID, targetID int64) error {
+	removed, err := s.bans.Delete(targetID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotBanned
+	}
+
+	log.Printf("Пользователь %d разблокирован администратором %d", targetID, actorID)
+	return nil
+}
+
+// Get возвращает действующую блокировку пользователя
+// Второе значение false, если пользователь не заблокирован
+// Истёкшие временные блокировки удаляются
+func (s *BanService) Get(userID int64) (domain.Ban, bool) {
+	ban, exists := s.bans.Get(userID)
+	if !exists {
+		return domain.Ban{}, false
+	}
+
+	if !ban.Active(time.Now()) {
+		if _, err := s.bans.Delete(userID); err != nil {
+			log.Printf("Ошибка удаления истёкшей блокировки %d: %v", userID, err)
+		} else {
+			log.Printf("Истёк срок блокировки пользователя %d", userID)
+		}
+		return domain.Ban{}, false
+	}
+
+	return ban, true
+}
+
+// List возвращает действующие блокировки, начиная с самых новых
+func (s *BanService) List() []domain.Ban {
+	now := time.Now()
+
+	var bans []domain.Ban
+	for _, ban := range s.bans.All() {
+		if ban.Active(now) {
+			bans = append(bans, ban)
+		}
+	}
+	return bans
+}
+
+// MarkNotified отмечает, что пользователь получил уведомление о блокировке
+func (s *BanService) MarkNotified(ban domain.Ban) error {
+	ban.Notified = true
+	return s.bans.Save(ban)
+}
+
+// banUntil возвращает срок блокировки для логов
+func banUntil(ban domain.Ban) string {
+	if ban.Permanent() {
+		return "бессрочно"
+	}
+	return ban.ExpiresAt.Format(time.RFC3339)
+}
+
+// banDuration возвращает срок блокировки для журнала аудита
+func banDuration(duration time.Duration) string {
+	if duration <= 0 {
+		return "permanent"
+	}
+	return duration.String()
+}
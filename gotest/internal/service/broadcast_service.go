@@ -0,0 +1,315 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+const (
+	// progressInterval — как часто (не чаще) обновлять сообщение с прогрессом
+	progressInterval = 3 * time.Second
+	// maxSendAttempts — сколько раз пытаться отправить сообщение при ошибке 429
+	maxSendAttempts = 3
+)
+
+// EnrollmentSource возвращает пользователей, записанных на курс
+// Нужен для рассылки по сегменту domain.SegmentCourse
+type EnrollmentSource interface {
+	EnrolledUserIDs(courseID int) []int64
+}
+
+// BroadcastService выполняет рассылки в фоне с ограничением скорости
+//
+// Позиция рассылки сохраняется до отправки каждому получателю, поэтому после перезапуска
+// рассылка продолжается со следующего получателя: каждый получает сообщение не больше одного раза.
+// Если бот упал во время отправки, её результат неизвестен и считается ошибкой
+type BroadcastService struct {
+	bot         *tgbotapi.BotAPI
+	jobs        *repository.BroadcastRepository
+	users       *repository.UserRepository
+	settings    *repository.SettingsRepository
+	enrollments EnrollmentSource
+	audit       *AuditService
+	limiter     *time.Ticker // Общий ограничитель скорости для всех рассылок
+}
+
+// NewBroadcastService создаёт сервис рассылок
+// ratePerSecond - максимальное количество сообщений в секунду (лимит Telegram — около 30)
+func NewBroadcastService(
+	bot *tgbotapi.BotAPI,
+	jobs *repository.BroadcastRepository,
+	users *repository.UserRepository,
+	settings *repository.SettingsRepository,
+	audit *AuditService,
+	ratePerSecond int,
+) *BroadcastService {
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+
+	return &BroadcastService{
+		bot:      bot,
+		jobs:     jobs,
+		users:    users,
+		settings: settings,
+		audit:    audit,
+		limiter:  time.NewTicker(time.Second / time.Duration(ratePerSecond)),
+	}
+}
+
+// SetEnrollmentSource подключает источник записей на курсы
+// Без него сегмент «записанные на курс» недоступен
+func (s *BroadcastService) SetEnrollmentSource(source EnrollmentSource) {
+	s.enrollments = source
+}
+
+// HasCourseSegment сообщает, доступна ли рассылка по записанным на курс
+func (s *BroadcastService) HasCourseSegment() bool {
+	return s.enrollments != nil
+}
+
+// Audience возвращает получателей сегмента
+// Пользователи, отключившие уведомления, в аудиторию не попадают
+func (s *BroadcastService) Audience(segment domain.Segment) []int64 {
+	var candidates []int64
+	switch segment.Kind {
+	case domain.SegmentCourse:
+		if s.enrollments != nil {
+			candidates = s.enrollments.EnrolledUserIDs(segment.CourseID)
+		}
+	default:
+		for _, user := range s.users.All() {
+			candidates = append(candidates, user.ID)
+		}
+	}
+
+	var recipients []int64
+	for _, userID := range candidates {
+		settings := s.settings.Get(userID)
+		if !settings.NotificationsEnabled {
+			continue
+		}
+		if segment.Kind == domain.SegmentLanguage && settings.Language != segment.Language {
+			continue
+		}
+		recipients = append(recipients, userID)
+	}
+	return recipients
+}
+
+// Preview отправляет сообщение рассылки в чат chatID (для предпросмотра)
+func (s *BroadcastService) Preview(chatID int64, message domain.BroadcastMessage) error {
+	_, err := s.bot.Send(NewBroadcastChattable(chatID, message))
+	return err
+}
+
+// Start сохраняет задание, определяет получателей и запускает рассылку в фоне
+func (s *BroadcastService) Start(job *domain.Broadcast) error {
+	job.Recipients = s.Audience(job.Segment)
+	job.Status = domain.BroadcastRunning
+	job.CreatedAt = time.Now()
+
+	if err := s.jobs.Create(job); err != nil {
+		s.audit.Record(job.CreatedBy, domain.AuditBroadcastStart, 0, broadcastAuditParams(*job), err)
+		return err
+	}
+	s.audit.Record(job.CreatedBy, domain.AuditBroadcastStart, job.ID, broadcastAuditParams(*job), nil)
+
+	// Для рассылок без сообщения о прогрессе (например, запланированных) создаём его
+	if job.ProgressMessageID == 0 && job.ReportChatID != 0 {
+		msg := tgbotapi.NewMessage(job.ReportChatID, BroadcastProgressText(*job))
+		msg.ParseMode = tgbotapi.ModeHTML
+		if sent, err := s.bot.Send(msg); err == nil {
+			job.ProgressMessageID = sent.MessageID
+			// Иначе продолженная после перезапуска рассылка создаст второе сообщение о прогрессе
+			if err := s.jobs.Update(*job); err != nil {
+				log.Printf("Рассылка #%d: ошибка сохранения сообщения о прогрессе: %v", job.ID, err)
+			}
+		}
+	}
+
+	log.Printf("Рассылка #%d запущена администратором %d, получателей: %d", job.ID, job.CreatedBy, len(job.Recipients))
+	go s.run(*job)
+	return nil
+}
+
+// Resume продолжает рассылки, прерванные перезапуском бота
+func (s *BroadcastService) Resume() {
+	for _, job := range s.jobs.Running() {
+		// Отправка последнему получателю до перезапуска могла не завершиться: повторять её нельзя
+		if lost := job.Cursor - job.Processed(); lost > 0 {
+			job.Failed += lost
+		}
+		log.Printf("Продолжаем рассылку #%d с позиции %d из %d", job.ID, job.Cursor, len(job.Recipients))
+		go s.run(job)
+	}
+}
+
+// run отправляет сообщение оставшимся получателям задания
+func (s *BroadcastService) run(job domain.Broadcast) {
+	lastProgress := time.Now()
+
+	for job.Cursor < len(job.Recipients) {
+		userID := job.Recipients[job.Cursor]
+
+		// Получатель отмечается обработанным до отправки: после перезапуска рассылка
+		// продолжится со следующего, и сообщение не будет отправлено дважды
+		job.Cursor++
+		if err := s.jobs.Update(job); err != nil {
+			log.Printf("Рассылка #%d: ошибка сохранения позиции, рассылка остановлена: %v", job.ID, err)
+			return
+		}
+
+		// Пользователь мог отключить уведомления уже после запуска рассылки
+		if !s.settings.Get(userID).NotificationsEnabled {
+			job.Skipped++
+		} else {
+			switch err := s.send(userID, job.Message); {
+			case err == nil:
+				job.Delivered++
+			case isBlockedError(err):
+				job.Blocked++
+			default:
+				job.Failed++
+				log.Printf("Рассылка #%d: ошибка отправки пользователю %d: %v", job.ID, userID, err)
+			}
+		}
+
+		if time.Since(lastProgress) >= progressInterval {
+			s.reportProgress(job)
+			lastProgress = time.Now()
+		}
+	}
+
+	job.Status = domain.BroadcastFinished
+	job.FinishedAt = time.Now()
+	if err := s.jobs.Update(job); err != nil {
+		log.Printf("Рассылка #%d: ошибка сохранения результата: %v", job.ID, err)
+	}
+	s.reportProgress(job)
+
+	log.Printf("Рассылка #%d завершена: доставлено %d, заблокировали %d, ошибок %d, пропущено %d",
+		job.ID, job.Delivered, job.Blocked, job.Failed, job.Skipped)
+}
+
+// send отправляет сообщение с учётом ограничения скорости
+// При ошибке 429 (слишком много запросов) ждёт указанное Telegram время и повторяет
+func (s *BroadcastService) send(chatID int64, message domain.BroadcastMessage) error {
+	var err error
+	for attempt := 0; attempt < maxSendAttempts; attempt++ {
+		<-s.limiter.C
+
+		_, err = s.bot.Send(NewBroadcastChattable(chatID, message))
+
+		var apiErr *tgbotapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+			return err
+		}
+		time.Sleep(time.Duration(apiErr.RetryAfter) * time.Second)
+	}
+	return err
+}
+
+// reportProgress обновляет сообщение с прогрессом рассылки у администратора
+func (s *BroadcastService) reportProgress(job domain.Broadcast) {
+	if job.ProgressMessageID == 0 {
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(job.ReportChatID, job.ProgressMessageID, BroadcastProgressText(job))
+	edit.ParseMode = tgbotapi.ModeHTML
+	if _, err := s.bot.Send(edit); err != nil {
+		log.Printf("Рассылка #%d: ошибка обновления прогресса: %v", job.ID, err)
+	}
+}
+
+// BroadcastProgressText формирует текст о ходе рассылки
+func BroadcastProgressText(job domain.Broadcast) string {
+	title := fmt.Sprintf("📤 <b>Рассылка #%d выполняется</b>", job.ID)
+	if job.Status == domain.BroadcastFinished {
+		title = fmt.Sprintf("✅ <b>Рассылка #%d завершена</b>", job.ID)
+	}
+
+	return fmt.Sprintf("%s\n\n"+
+		"Обработано: <code>%d</code> из <code>%d</code>\n"+
+		"✅ Доставлено: <code>%d</code>\n"+
+		"🚫 Заблокировали бота: <code>%d</code>\n"+
+		"❌ Ошибок: <code>%d</code>\n"+
+		"🔕 Отключили уведомления: <code>%d</code>",
+		title, job.Cursor, len(job.Recipients), job.Delivered, job.Blocked, job.Failed, job.Skipped)
+}
+
+// NewBroadcastChattable создаёт запрос отправки сообщения рассылки в чат chatID
+func NewBroadcastChattable(chatID int64, message domain.BroadcastMessage) tgbotapi.Chattable {
+	var markup interface{}
+	if len(message.Buttons) > 0 {
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, button := range message.Buttons {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonURL(button.Text, button.URL)))
+		}
+		markup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+
+	file := tgbotapi.FileID(message.FileID)
+	switch message.MediaType {
+	case domain.MediaPhoto:
+		photo := tgbotapi.NewPhoto(chatID, file)
+		photo.Caption = message.Text
+		photo.CaptionEntities = message.Entities
+		photo.ReplyMarkup = markup
+		return photo
+	case domain.MediaVideo:
+		video := tgbotapi.NewVideo(chatID, file)
+		video.Caption = message.Text
+		video.CaptionEntities = message.Entities
+		video.ReplyMarkup = markup
+		return video
+	case domain.MediaDocument:
+		document := tgbotapi.NewDocument(chatID, file)
+		document.Caption = message.Text
+		document.CaptionEntities = message.Entities
+		document.ReplyMarkup = markup
+		return document
+	case domain.MediaAnimation:
+		animation := tgbotapi.NewAnimation(chatID, file)
+		animation.Caption = message.Text
+		animation.CaptionEntities = message.Entities
+		animation.ReplyMarkup = markup
+		return animation
+	default:
+		msg := tgbotapi.NewMessage(chatID, message.Text)
+		msg.Entities = message.Entities
+		msg.ReplyMarkup = markup
+		return msg
+	}
+}
+
+// broadcastAuditParams возвращает параметры рассылки для журнала аудита
+func broadcastAuditParams(job domain.Broadcast) map[string]string {
+	params := map[string]string{
+		"segment":    string(job.Segment.Kind),
+		"recipients": strconv.Itoa(len(job.Recipients)),
+	}
+	switch job.Segment.Kind {
+	case domain.SegmentLanguage:
+		params["language"] = job.Segment.Language
+	case domain.SegmentCourse:
+		params["course"] = strconv.Itoa(job.Segment.CourseID)
+	}
+	return params
+}
+
+// isBlockedError проверяет, что пользователь заблокировал бота или удалил аккаунт
+func isBlockedError(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"telegram-bot/internal/domain"
+)
+
+// Размер сертификата в пикселях — альбомный A4
+const (
+	certificateWidth  = 1600
+	certificateHeight = 1131
+	certificateMargin = 160 // Отступ текста от краёв листа
+)
+
+// Цвета сертификата
+var (
+	certificateBackground = color.RGBA{0xFB, 0xF8, 0xF0, 0xFF}
+	certificateFrame      = color.RGBA{0x1F, 0x3A, 0x5F, 0xFF}
+	certificateAccent     = color.RGBA{0xB8, 0x8A, 0x2E, 0xFF}
+	certificateText       = color.RGBA{0x22, 0x22, 0x22, 0xFF}
+	certificateMuted      = color.RGBA{0x6B, 0x6B, 0x6B, 0xFF}
+)
+
+// certificateLabels — подписи сертификата на языке пользователя
+// Шрифт Go не содержит иероглифов, поэтому для остальных языков используется английский
+var certificateLabels = map[string]struct {
+	Title, Intro, Completed, Date, Code, Verify string
+}{
+	"ru": {"СЕРТИФИКАТ", "настоящим подтверждается, что", "успешно прошёл(ла) курс", "Дата выдачи", "Код проверки", "Проверить подлинность: /verify"},
+	"en": {"CERTIFICATE", "this is to certify that", "has successfully completed the course", "Date of issue", "Verification code", "Verify authenticity: /verify"},
+}
+
+// Шрифты сертификата разбираются один раз при первой отрисовке
+var (
+	certificateFontsOnce sync.Once
+	certificateRegular   *opentype.Font
+	certificateBold      *opentype.Font
+	certificateFontsErr  error
+)
+
+// loadCertificateFonts разбирает встроенные шрифты Go
+func loadCertificateFonts() error {
+	certificateFontsOnce.Do(func() {
+		if certificateRegular, certificateFontsErr = opentype.Parse(goregular.TTF); certificateFontsErr != nil {
+			return
+		}
+		certificateBold, certificateFontsErr = opentype.Parse(gobold.TTF)
+	})
+	if certificateFontsErr != nil {
+		return fmt.Errorf("ошибка загрузки шрифта сертификата: %w", certificateFontsErr)
+	}
+	return nil
+}
+
+// renderCertificateImage рисует сертификат на языке lang
+func renderCertificateImage(certificate domain.Certificate, lang string) (*image.RGBA, error) {
+	if err := loadCertificateFonts(); err != nil {
+		return nil, err
+	}
+
+	labels, ok := certificateLabels[lang]
+	if !ok {
+		lang = "en"
+		labels = certificateLabels[lang]
+	}
+	// Название курса на языке пользователя, если шрифт может его нарисовать
+	title := certificate.CourseTitle.Get(lang)
+	if !fontCovers(certificateBold, title) {
+		title = certificate.CourseTitle.Get(domain.DefaultLanguage)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, certificateWidth, certificateHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(certificateBackground), image.Point{}, draw.Src)
+	drawFrame(img, 40, 12, certificateFrame)
+	drawFrame(img, 64, 3, certificateAccent)
+	fillRect(img, image.Rect(certificateWidth/2-180, 318, certificateWidth/2+180, 322), certificateAccent)
+
+	lines := []struct {
+		text  string
+		font  *opentype.Font
+		size  float64
+		color color.Color
+		y     int // Базовая линия текста
+	}{
+		{labels.Title, certificateBold, 96, certificateFrame, 280},
+		{labels.Intro, certificateRegular, 36, certificateMuted, 410},
+		{certificate.UserName, certificateBold, 76, certificateText, 520},
+		{labels.Completed, certificateRegular, 36, certificateMuted, 610},
+		{"«" + title + "»", certificateBold, 60, certificateFrame, 710},
+		{fmt.Sprintf("%s: %s", labels.Date, certificate.IssuedAt.Format("02.01.2006")), certificateRegular, 32, certificateText, 880},
+		{fmt.Sprintf("%s: %s", labels.Code, certificate.Code), certificateBold, 32, certificateText, 935},
+		{fmt.Sprintf("%s %s", labels.Verify, certificate.Code), certificateRegular, 26, certificateMuted, 985},
+	}
+	for _, line := range lines {
+		if err := drawCentered(img, line.text, line.font, line.size, line.color, line.y); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+// drawCentered рисует строку по центру листа
+// Длинная строка уменьшается, пока не поместится между полями
+func drawCentered(img *image.RGBA, text string, f *opentype.Font, size float64, c color.Color, y int) error {
+	maxWidth := fixed.I(certificateWidth - 2*certificateMargin)
+	for {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return fmt.Errorf("ошибка создания шрифта сертификата: %w", err)
+		}
+
+		width := font.MeasureString(face, text)
+		if width > maxWidth && size > 16 {
+			face.Close()
+			size *= 0.9
+			continue
+		}
+
+		drawer := font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(c),
+			Face: face,
+			Dot:  fixed.Point26_6{X: (fixed.I(certificateWidth) - width) / 2, Y: fixed.I(y)},
+		}
+		drawer.DrawString(text)
+		return face.Close()
+	}
+}
+
+// fontCovers возвращает true, если в шрифте есть все символы строки
+func fontCovers(f *opentype.Font, text string) bool {
+	var buf sfnt.Buffer
+	for _, r := range text {
+		if index, err := f.GlyphIndex(&buf, r); err != nil || index == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// drawFrame рисует рамку толщиной width с отступом inset от краёв листа
+func drawFrame(img *image.RGBA, inset, width int, c color.Color) {
+	outer := img.Bounds().Inset(inset)
+	fillRect(img, image.Rect(outer.Min.X, outer.Min.Y, outer.Max.X, outer.Min.Y+width), c)
+	fillRect(img, image.Rect(outer.Min.X, outer.Max.Y-width, outer.Max.X, outer.Max.Y), c)
+	fillRect(img, image.Rect(outer.Min.X, outer.Min.Y, outer.Min.X+width, outer.Max.Y), c)
+	fillRect(img, image.Rect(outer.Max.X-width, outer.Min.Y, outer.Max.X, outer.Max.Y), c)
+}
+
+// fillRect закрашивает прямоугольник цветом c
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// encodeCertificatePNG сохраняет сертификат в PNG
+func encodeCertificatePNG(img *image.RGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeCertificatePDF сохраняет сертификат в одностраничный PDF
+// Страница A4 в альбомной ориентации целиком занята картинкой сертификата
+func encodeCertificatePDF(img *image.RGBA, code string) ([]byte, error) {
+	// Пиксели без альфа-канала, сжатые zlib (фильтр FlateDecode)
+	var pixels bytes.Buffer
+	zw := zlib.NewWriter(&pixels)
+	bounds := img.Bounds()
+	row := make([]byte, 0, bounds.Dx()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row = row[:0]
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			offset := img.PixOffset(x, y)
+			row = append(row, img.Pix[offset], img.Pix[offset+1], img.Pix[offset+2])
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	const pageWidth, pageHeight = 842, 595 // A4 в пунктах
+	content := fmt.Sprintf("q %d 0 0 %d 0 0 cm /Im0 Do Q", pageWidth, pageHeight)
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string, stream []byte) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\n", len(offsets), body)
+		if stream != nil {
+			buf.WriteString("stream\n")
+			buf.Write(stream)
+			buf.WriteString("\nendstream\n")
+		}
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>", nil)
+	object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /XObject << /Im0 5 0 R >> >> /Contents 4 0 R >>",
+		pageWidth, pageHeight), nil)
+	object(fmt.Sprintf("<< /Length %d >>", len(content)), []byte(content))
+	object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>",
+		bounds.Dx(), bounds.Dy(), pixels.Len()), pixels.Bytes())
+	object(fmt.Sprintf("<< /Title (Certificate %s) /Producer (telegram-bot) >>", code), nil)
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, len(offsets), xref)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// Форматы сертификата
+const (
+	CertificatePNG = "png" // Картинка, отправляется фото
+	CertificatePDF = "pdf" // PDF, отправляется документом
+)
+
+// certificateAlphabet — символы кода проверки без похожих друг на друга (0/O, 1/I)
+// 32 символа: остаток от деления случайного байта не искажает распределение
+const certificateAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// ErrCourseNotCompleted — сертификат запрошен до прохождения курса
+var ErrCourseNotCompleted = errors.New("сертификат выдаётся после прохождения всех уроков курса")
+
+// CertificateService выдаёт, проверяет и рисует сертификаты о прохождении курсов
+type CertificateService struct {
+	certificates *repository.CertificateRepository
+	enrollments  *EnrollmentService
+	courses      *CourseService
+	format       string
+	mu           sync.Mutex // Не даёт выдать два сертификата на один курс
+}
+
+// NewCertificateService создаёт сервис сертификатов
+// format — CertificatePNG или CertificatePDF
+func NewCertificateService(certificates *repository.CertificateRepository, enrollments *EnrollmentService, courses *CourseService, format string) (*CertificateService, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != CertificatePNG && format != CertificatePDF {
+		return nil, fmt.Errorf("неизвестный формат сертификата %q (ожидается png или pdf)", format)
+	}
+
+	return &CertificateService{
+		certificates: certificates,
+		enrollments:  enrollments,
+		courses:      courses,
+		format:       format,
+	}, nil
+}
+
+// Format возвращает формат, в котором рисуются сертификаты
+func (s *CertificateService) Format() string {
+	return s.format
+}
+
+// Issue выдаёт пользователю сертификат о прохождении курса
+// Если сертификат уже выдавался, возвращается прежний с тем же кодом
+func (s *CertificateService) Issue(userID int64, courseID int, name string) (domain.Certificate, error) {
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return domain.Certificate{}, ErrNotEnrolled
+	}
+	if !enrollment.Completed() {
+		return domain.Certificate{}, ErrCourseNotCompleted
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if certificate, exists := s.certificates.ByUserCourse(userID, courseID); exists {
+		return certificate, nil
+	}
+
+	course, err := s.courses.Get(courseID)
+	if err != nil {
+		return domain.Certificate{}, err
+	}
+
+	certificate := domain.Certificate{
+		UserID:      userID,
+		CourseID:    courseID,
+		UserName:    name,
+		CourseTitle: course.Title,
+		IssuedAt:    enrollment.CompletedAt,
+	}
+	// Код случайный, поэтому совпадение почти невозможно, но занятый код не перезаписываем
+	for {
+		certificate.Code, err = newCertificateCode()
+		if err != nil {
+			return domain.Certificate{}, err
+		}
+		created, err := s.certificates.Create(certificate)
+		if err != nil {
+			return domain.Certificate{}, err
+		}
+		if created {
+			break
+		}
+	}
+
+	log.Printf("Пользователю %d выдан сертификат %s по курсу %d", userID, certificate.Code, courseID)
+	return certificate, nil
+}
+
+// Verify находит сертификат по коду проверки
+// Регистр и пробелы в коде не важны, дефисы можно не вводить
+func (s *CertificateService) Verify(code string) (domain.Certificate, error) {
+	code = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	if len(code) != 10 {
+		return domain.Certificate{}, repository.ErrCertificateNotFound
+	}
+	return s.certificates.Get(formatCertificateCode(code))
+}
+
+// Render рисует сертификат на языке lang в формате сервиса
+// Возвращает содержимое файла и имя файла для отправки
+func (s *CertificateService) Render(certificate domain.Certificate, lang string) ([]byte, string, error) {
+	img, err := renderCertificateImage(certificate, lang)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var data []byte
+	if s.format == CertificatePDF {
+		data, err = encodeCertificatePDF(img, certificate.Code)
+	} else {
+		data, err = encodeCertificatePNG(img)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка сохранения сертификата: %w", err)
+	}
+	return data, fmt.Sprintf("certificate-%s.%s", certificate.Code, s.format), nil
+}
+
+// newCertificateCode создаёт случайный код проверки вида XXXX-XXXX-XX
+func newCertificateCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("ошибка генерации кода сертификата: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = certificateAlphabet[int(b)%len(certificateAlphabet)]
+	}
+	return formatCertificateCode(string(buf)), nil
+}
+
+// formatCertificateCode расставляет дефисы в коде из 10 символов
+func formatCertificateCode(code string) string {
+	return code[:4] + "-" + code[4:8] + "-" + code[8:]
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"telegram-bot/internal/domain"
+)
+
+// Веса совпадений при поиске курсов
+const (
+	searchTitleWeight = 2.0 // Совпадение в названии важнее совпадения в описании
+	searchExact       = 1.0 // Слово совпало полностью
+	searchPartial     = 0.8 // Слово текста содержит запрос («сервис» → «микросервисы», части слитного текста на китайском)
+	searchFuzzy       = 0.6 // Слово отличается на одну-две буквы (опечатка)
+)
+
+// Search возвращает опубликованные курсы, подходящие под фильтр
+// Текст запроса ищется нечётко во всех переводах названия и описания:
+// каждое слово запроса должно найтись хотя бы с опечаткой.
+// Результаты с запросом отсортированы по релевантности, без запроса — в порядке каталога
+func (s *CourseService) Search(filter domain.CourseFilter) ([]domain.Course, error) {
+	catalog, err := s.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchWords(filter.Query)
+	type match struct {
+		course domain.Course
+		score  float64
+	}
+	var matches []match
+	for _, course := range catalog {
+		if filter.Category != "" && course.Category != filter.Category {
+			continue
+		}
+		if filter.Level != "" && course.Level != filter.Level {
+			continue
+		}
+		score, ok := courseScore(course, terms)
+		if !ok {
+			continue
+		}
+		matches = append(matches, match{course: course, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]domain.Course, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.course)
+	}
+	return result, nil
+}
+
+// Categories возвращает категории опубликованных курсов по алфавиту
+func (s *CourseService) Categories() ([]string, error) {
+	catalog, err := s.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var categories []string
+	for _, course := range catalog {
+		if course.Category != "" && !seen[course.Category] {
+			seen[course.Category] = true
+			categories = append(categories, course.Category)
+		}
+	}
+	sort.Strings(categories)
+	return categories, nil
+}
+
+// courseScore оценивает, насколько курс подходит под слова запроса
+// Второе значение false, если хотя бы одно слово не нашлось
+func courseScore(course domain.Course, terms []string) (float64, bool) {
+	if len(terms) == 0 {
+		return 0, true
+	}
+
+	var title, description []string
+	for _, text := range course.Title {
+		title = append(title, searchWords(text)...)
+	}
+	for _, text := range course.Description {
+		description = append(description, searchWords(text)...)
+	}
+	description = append(description, searchWords(course.Category+" "+course.Slug)...)
+
+	total := 0.0
+	for _, term := range terms {
+		score := max(wordScore(term, title)*searchTitleWeight, wordScore(term, description))
+		if score == 0 {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+// wordScore возвращает лучшее совпадение слова запроса со словами текста (0 — не найдено)
+func wordScore(term string, words []string) float64 {
+	best := 0.0
+	for _, word := range words {
+		switch {
+		case word == term:
+			return searchExact
+		case strings.Contains(word, term) && len([]rune(term)) >= 2:
+			best = max(best, searchPartial)
+		case fuzzyMatch(term, word):
+			best = max(best, searchFuzzy)
+		}
+	}
+	return best
+}
+
+// fuzzyMatch сравнивает слова с допуском на опечатки:
+// одна ошибка для слов от 4 букв, две — от 8 букв.
+// Длинное слово текста сравнивается и по началу, чтобы «микросервис» находил «микросервисы»
+func fuzzyMatch(term, word string) bool {
+	t, w := []rune(term), []rune(word)
+	allowed := 0
+	switch {
+	case len(t) >= 8:
+		allowed = 2
+	case len(t) >= 4:
+		allowed = 1
+	default:
+		return false
+	}
+
+	if levenshtein(t, w) <= allowed {
+		return true
+	}
+	if len(w) > len(t) {
+		return levenshtein(t, w[:len(t)]) <= allowed
+	}
+	return false
+}
+
+// levenshtein считает редакционное расстояние между словами
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// searchWords приводит текст к словам для поиска: нижний регистр, ё → е, без знаков препинания
+func searchWords(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "ё", "е")
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '#' && r != '+'
+	})
+}
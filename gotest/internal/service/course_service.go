@@ -0,0 +1,338 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+const (
+	maxCourseTitle       = 64   // Максимальная длина названия курса
+	maxCourseDescription = 1000 // Максимальная длина описания курса
+	maxCourseSlug        = 48   // Максимальная длина slug
+)
+
+var (
+	// ErrCourseInvalid — курс не прошёл проверку (подробности — в тексте ошибки)
+	ErrCourseInvalid = errors.New("некорректный курс")
+	// ErrCourseInUse — курс нельзя удалить: на него записаны пользователи или его купили
+	ErrCourseInUse = errors.New("на курс есть записи или оплаченные заказы — снимите его с публикации вместо удаления")
+)
+
+// CourseUsage сообщает, используется ли курс (записи пользователей, оплаченные заказы)
+type CourseUsage interface {
+	// CourseInUse возвращает true, если удаление курса лишит пользователей доступа или прогресса
+	CourseInUse(courseID int) bool
+}
+
+// courseCategoryPattern — допустимые категории курсов
+var courseCategoryPattern = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+// currencyPattern — код валюты платного курса (ISO 4217 или XTR для Telegram Stars)
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CourseService предоставляет каталог курсов и управление им
+type CourseService struct {
+	courses repository.CourseRepository
+	audit   *AuditService
+	usages  []CourseUsage
+}
+
+// NewCourseService создаёт сервис каталога курсов
+func NewCourseService(courses repository.CourseRepository, audit *AuditService) *CourseService {
+	return &CourseService{courses: courses, audit: audit}
+}
+
+// AddUsage подключает проверку, запрещающую удалять используемые курсы
+func (s *CourseService) AddUsage(usage CourseUsage) {
+	s.usages = append(s.usages, usage)
+}
+
+// SeedIfEmpty заполняет пустое хранилище курсами seed (при первом запуске)
+func (s *CourseService) SeedIfEmpty(seed []domain.Course) error {
+	existing, err := s.courses.All()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	for i := range seed {
+		if err := s.courses.Save(&seed[i]); err != nil {
+			return fmt.Errorf("ошибка добавления курса %s: %w", seed[i].Slug, err)
+		}
+	}
+	log.Printf("Каталог курсов заполнен начальными данными: %d курсов", len(seed))
+	return nil
+}
+
+// All возвращает все курсы, включая неопубликованные, в порядке каталога
+func (s *CourseService) All() ([]domain.Course, error) {
+	courses, err := s.courses.All()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(courses, func(i, j int) bool {
+		if courses[i].Position != courses[j].Position {
+			return courses[i].Position < courses[j].Position
+		}
+		return courses[i].ID < courses[j].ID
+	})
+	return courses, nil
+}
+
+// Catalog возвращает опубликованные курсы в порядке каталога
+func (s *CourseService) Catalog() ([]domain.Course, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]domain.Course, 0, len(all))
+	for _, course := range all {
+		if course.Published {
+			published = append(published, course)
+		}
+	}
+	return published, nil
+}
+
+// Get возвращает курс по ID, в том числе неопубликованный
+func (s *CourseService) Get(id int) (domain.Course, error) {
+	return s.courses.Get(id)
+}
+
+// Published возвращает опубликованный курс по ID
+// Неопубликованный курс для пользователей считается ненайденным
+func (s *CourseService) Published(id int) (domain.Course, error) {
+	course, err := s.courses.Get(id)
+	if err != nil {
+		return domain.Course{}, err
+	}
+	if !course.Published {
+		return domain.Course{}, repository.ErrCourseNotFound
+	}
+	return course, nil
+}
+
+// Create проверяет и сохраняет новый курс от имени actorID
+// Курс создаётся неопубликованным и добавляется в конец каталога; slug формируется из названия
+func (s *CourseService) Create(actorID int64, course *domain.Course) error {
+	err := s.create(course)
+	s.audit.Record(actorID, domain.AuditCourseCreate, int64(course.ID), map[string]string{
+		"slug":  course.Slug,
+		"title": course.Title.Get(domain.DefaultLanguage),
+	}, err)
+	return err
+}
+
+// create сохраняет новый курс без записи в журнал
+func (s *CourseService) create(course *domain.Course) error {
+	if err := ValidateCourse(*course); err != nil {
+		return err
+	}
+
+	all, err := s.courses.All()
+	if err != nil {
+		return err
+	}
+
+	course.ID = 0
+	course.Published = false
+	course.Slug = uniqueSlug(slugify(course.Title.Get(domain.DefaultLanguage)), all)
+	if course.Level == "" {
+		course.Level = domain.LevelBeginner
+	}
+	if course.Currency == "" {
+		course.Currency = "RUB"
+	}
+	for _, other := range all {
+		course.Position = max(course.Position, other.Position, other.ID)
+	}
+	course.Position++
+
+	return s.courses.Save(course)
+}
+
+// Update проверяет и сохраняет изменённый курс от имени actorID
+// field - что изменилось (для журнала аудита)
+func (s *CourseService) Update(actorID int64, course *domain.Course, field string) error {
+	err := ValidateCourse(*course)
+	if err == nil {
+		err = s.courses.Save(course)
+	}
+	s.audit.Record(actorID, domain.AuditCourseUpdate, int64(course.ID), map[string]string{"field": field}, err)
+	return err
+}
+
+// SetPublished публикует курс или снимает его с публикации
+func (s *CourseService) SetPublished(actorID int64, id int, published bool) error {
+	course, err := s.courses.Get(id)
+	if err == nil {
+		course.Published = published
+		err = s.courses.Save(&course)
+	}
+	s.audit.Record(actorID, domain.AuditCoursePublish, int64(id), map[string]string{"published": strconv.FormatBool(published)}, err)
+	return err
+}
+
+// Move сдвигает курс в каталоге на одну позицию вверх (delta < 0) или вниз (delta > 0)
+func (s *CourseService) Move(actorID int64, id int, delta int) error {
+	err := s.move(id, delta)
+	s.audit.Record(actorID, domain.AuditCourseMove, int64(id), map[string]string{"delta": strconv.Itoa(delta)}, err)
+	return err
+}
+
+// move меняет курс местами с соседним и перенумеровывает позиции без записи в журнал
+func (s *CourseService) move(id int, delta int) error {
+	courses, err := s.All()
+	if err != nil {
+		return err
+	}
+
+	from := -1
+	for i, course := range courses {
+		if course.ID == id {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return repository.ErrCourseNotFound
+	}
+
+	to := from + 1
+	if delta < 0 {
+		to = from - 1
+	}
+	if delta == 0 || to < 0 || to >= len(courses) {
+		return nil // Курс уже первый или последний
+	}
+	courses[from], courses[to] = courses[to], courses[from]
+
+	// Сохраняем только курсы, позиция которых изменилась
+	for i := range courses {
+		if courses[i].Position == i+1 {
+			continue
+		}
+		courses[i].Position = i + 1
+		if err := s.courses.Save(&courses[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete удаляет курс от имени actorID
+func (s *CourseService) Delete(actorID int64, id int) error {
+	course, _ := s.courses.Get(id)
+	err := s.delete(id)
+	s.audit.Record(actorID, domain.AuditCourseDelete, int64(id), map[string]string{"slug": course.Slug}, err)
+	return err
+}
+
+// delete удаляет курс без записи в журнал, если он не используется
+func (s *CourseService) delete(id int) error {
+	for _, usage := range s.usages {
+		if usage.CourseInUse(id) {
+			return ErrCourseInUse
+		}
+	}
+	return s.courses.Delete(id)
+}
+
+// ValidateCourse проверяет поля курса, которые заполняет администратор
+func ValidateCourse(course domain.Course) error {
+	if strings.TrimSpace(course.Title.Get(domain.DefaultLanguage)) == "" {
+		return fmt.Errorf("%w: нужно название на языке %s", ErrCourseInvalid, domain.DefaultLanguage)
+	}
+	for lang, title := range course.Title {
+		if utf8.RuneCountInString(title) > maxCourseTitle {
+			return fmt.Errorf("%w: название (%s) длиннее %d символов", ErrCourseInvalid, lang, maxCourseTitle)
+		}
+	}
+
+	if strings.TrimSpace(course.Description.Get(domain.DefaultLanguage)) == "" {
+		return fmt.Errorf("%w: нужно описание на языке %s", ErrCourseInvalid, domain.DefaultLanguage)
+	}
+	for lang, description := range course.Description {
+		if utf8.RuneCountInString(description) > maxCourseDescription {
+			return fmt.Errorf("%w: описание (%s) длиннее %d символов", ErrCourseInvalid, lang, maxCourseDescription)
+		}
+	}
+
+	if !courseCategoryPattern.MatchString(course.Category) {
+		return fmt.Errorf("%w: категория может содержать только a-z, 0-9 и дефис (до 32 символов)", ErrCourseInvalid)
+	}
+
+	if course.Price < 0 {
+		return fmt.Errorf("%w: цена не может быть отрицательной", ErrCourseInvalid)
+	}
+	if course.Price > 0 && !currencyPattern.MatchString(course.Currency) {
+		return fmt.Errorf("%w: валюта — трёхбуквенный код, например XTR или RUB", ErrCourseInvalid)
+	}
+	return nil
+}
+
+// translit — транслитерация кириллицы для slug
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sch", 'ы': "y", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// slugify формирует slug из названия: латиница, цифры и дефисы
+func slugify(title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			dash = false
+		case translit[r] != "":
+			sb.WriteString(translit[r])
+			dash = false
+		case r == 'ъ' || r == 'ь':
+			// Знаки не транслитерируются
+		default:
+			if !dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := strings.Trim(sb.String(), "-")
+	if len(slug) > maxCourseSlug {
+		slug = strings.Trim(slug[:maxCourseSlug], "-")
+	}
+	if slug == "" {
+		slug = "course"
+	}
+	return slug
+}
+
+// uniqueSlug добавляет к slug номер, если он уже занят другим курсом
+func uniqueSlug(slug string, courses []domain.Course) string {
+	taken := make(map[string]bool, len(courses))
+	for _, course := range courses {
+		taken[course.Slug] = true
+	}
+
+	candidate := slug
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", slug, n)
+	}
+	return candidate
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// DialogService управляет состояниями многошаговых диалогов
+// Диалог принадлежит пользователю в чате либо всему чату (userID = 0)
+type DialogService struct {
+	dialogs *repository.DialogRepository
+	timeout time.Duration // Время ожидания ответа по умолчанию
+}
+
+// NewDialogService создаёт сервис диалогов
+// timeout - время ожидания ответа для сценариев, где оно не задано
+func NewDialogService(dialogs *repository.DialogRepository, timeout time.Duration) *DialogService {
+	return &DialogService{
+		dialogs: dialogs,
+		timeout: timeout,
+	}
+}
+
+// Begin начинает диалог на шаге step, заменяя текущий диалог с тем же владельцем
+// timeout 0 означает время ожидания по умолчанию
+func (s *DialogService) Begin(chatID, userID int64, scene, step string, data json.RawMessage, timeout time.Duration) (domain.DialogState, error) {
+	if timeout == 0 {
+		timeout = s.timeout
+	}
+
+	now := time.Now()
+	state := domain.DialogState{
+		ChatID:    chatID,
+		UserID:    userID,
+		Scene:     scene,
+		Step:      step,
+		Data:      data,
+		Timeout:   timeout,
+		StartedAt: now,
+	}
+	state.Touch(now)
+
+	return state, s.dialogs.Save(state)
+}
+
+// Current возвращает активный диалог пользователя в чате
+// Личный диалог пользователя важнее общего диалога чата; диалоги с истёкшим временем не возвращаются
+func (s *DialogService) Current(chatID, userID int64) (domain.DialogState, bool) {
+	now := time.Now()
+	for _, owner := range []int64{userID, 0} {
+		if state, exists := s.dialogs.Get(chatID, owner); exists && !state.Expired(now) {
+			return state, true
+		}
+	}
+	return domain.DialogState{}, false
+}
+
+// Update сохраняет шаг и данные диалога и продлевает время ожидания ответа
+func (s *DialogService) Update(state domain.DialogState) error {
+	state.Touch(time.Now())
+	return s.dialogs.Save(state)
+}
+
+// End завершает диалог
+func (s *DialogService) End(state domain.DialogState) error {
+	_, err := s.dialogs.Delete(state.ChatID, state.UserID)
+	return err
+}
+
+// Expire завершает диалоги, время ожидания ответа в которых истекло, и возвращает их
+func (s *DialogService) Expire(now time.Time) ([]domain.DialogState, error) {
+	return s.dialogs.DeleteExpired(now)
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+var (
+	// ErrAlreadyEnrolled — пользователь уже записан на курс
+	ErrAlreadyEnrolled = errors.New("вы уже записаны на этот курс")
+	// ErrNotEnrolled — пользователь не записан на курс
+	ErrNotEnrolled = errors.New("вы не записаны на этот курс")
+	// ErrLessonLocked — урок ещё не открыт по расписанию курса
+	ErrLessonLocked = errors.New("урок ещё закрыт")
+	// ErrSubscriptionRequired — платный курс доступен только по подписке
+	ErrSubscriptionRequired = errors.New("курс доступен по подписке: /premium")
+)
+
+// LessonSource — источник уроков курса для подсчёта прогресса и выдачи уроков
+type LessonSource interface {
+	// ByCourse возвращает уроки курса в порядке прохождения
+	ByCourse(courseID int) []domain.Lesson
+}
+
+// CourseAccess проверяет, доступен ли пользователю курс (например, платный курс по подписке)
+type CourseAccess interface {
+	// CourseAllowed возвращает true, если пользователь может записаться на курс и проходить его уроки
+	CourseAllowed(userID int64, course domain.Course) bool
+}
+
+// EnrollmentService управляет записью на курсы и прогрессом обучения
+type EnrollmentService struct {
+	enrollments *repository.EnrollmentRepository
+	courses     *CourseService
+	lessons     LessonSource
+	access      CourseAccess
+	mu          sync.Mutex // Последовательные изменения прогресса
+}
+
+// NewEnrollmentService создаёт сервис записи на курсы
+func NewEnrollmentService(enrollments *repository.EnrollmentRepository, courses *CourseService) *EnrollmentService {
+	return &EnrollmentService{
+		enrollments: enrollments,
+		courses:     courses,
+	}
+}
+
+// SetLessonSource подключает источник уроков
+// Без него у курсов нет уроков и прогресс всегда нулевой
+func (s *EnrollmentService) SetLessonSource(lessons LessonSource) {
+	s.lessons = lessons
+}
+
+// SetCourseAccess подключает проверку доступа к курсам
+// Без неё все опубликованные курсы доступны всем пользователям
+func (s *EnrollmentService) SetCourseAccess(access CourseAccess) {
+	s.access = access
+}
+
+// Enroll записывает пользователя на опубликованный курс
+func (s *EnrollmentService) Enroll(userID int64, courseID int) (domain.Enrollment, error) {
+	course, err := s.courses.Published(courseID)
+	if err != nil {
+		return domain.Enrollment{}, err
+	}
+	if !s.allowed(userID, course) {
+		return domain.Enrollment{}, ErrSubscriptionRequired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.enrollments.Get(userID, courseID); exists {
+		return domain.Enrollment{}, ErrAlreadyEnrolled
+	}
+
+	// Результаты тестов возвращаются из прошлой записи: лимит попыток не сбрасывается повторной записью
+	enrollment := domain.Enrollment{
+		UserID:     userID,
+		CourseID:   courseID,
+		EnrolledAt: time.Now(),
+		Quizzes:    s.enrollments.QuizHistory(userID, courseID),
+	}
+	if err := s.enrollments.Save(enrollment); err != nil {
+		return domain.Enrollment{}, err
+	}
+
+	log.Printf("Пользователь %d записался на курс %d", userID, courseID)
+	return enrollment, nil
+}
+
+// Unenroll отменяет запись пользователя на курс вместе с прогрессом
+// Результаты тестов сохраняются и вернутся при повторной записи
+func (s *EnrollmentService) Unenroll(userID int64, courseID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed, err := s.enrollments.Delete(userID, courseID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotEnrolled
+	}
+
+	log.Printf("Пользователь %d отписался от курса %d", userID, courseID)
+	return nil
+}
+
+// Get возвращает запись пользователя на курс
+// Второе значение false, если пользователь не записан
+func (s *EnrollmentService) Get(userID int64, courseID int) (domain.Enrollment, bool) {
+	return s.enrollments.Get(userID, courseID)
+}
+
+// CourseInUse возвращает true, если на курс записан хотя бы один пользователь
+func (s *EnrollmentService) CourseInUse(courseID int) bool {
+	return len(s.enrollments.UserIDsByCourse(courseID)) > 0
+}
+
+// Progress возвращает курс пользователя с прогрессом
+func (s *EnrollmentService) Progress(userID int64, courseID int) (domain.CourseProgress, error) {
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return domain.CourseProgress{}, ErrNotEnrolled
+	}
+	course, err := s.courses.Get(courseID)
+	if err != nil {
+		return domain.CourseProgress{}, err
+	}
+	return s.progress(course, enrollment), nil
+}
+
+// MyCourses возвращает курсы пользователя с прогрессом, начиная с последней записи
+// Удалённые из каталога курсы пропускаются
+func (s *EnrollmentService) MyCourses(userID int64) ([]domain.CourseProgress, error) {
+	var result []domain.CourseProgress
+	for _, enrollment := range s.enrollments.ByUser(userID) {
+		course, err := s.courses.Get(enrollment.CourseID)
+		if errors.Is(err, repository.ErrCourseNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s.progress(course, enrollment))
+	}
+	return result, nil
+}
+
+// Plan возвращает уроки курса с отметками о прохождении и открытии для пользователя
+func (s *EnrollmentService) Plan(userID int64, courseID int) ([]domain.LessonStep, error) {
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return nil, ErrNotEnrolled
+	}
+	return s.plan(enrollment), nil
+}
+
+// OpenLesson открывает урок и запоминает его как место, с которого пользователь продолжит курс
+// Возвращает план курса и индекс открытого урока в нём
+func (s *EnrollmentService) OpenLesson(userID int64, courseID, lessonID int) ([]domain.LessonStep, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return nil, 0, ErrNotEnrolled
+	}
+	if s.access != nil {
+		course, err := s.courses.Get(courseID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !s.allowed(userID, course) {
+			return nil, 0, ErrSubscriptionRequired
+		}
+	}
+
+	plan := s.plan(enrollment)
+	index, err := lessonIndex(plan, lessonID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if enrollment.LastLessonID != lessonID {
+		enrollment.LastLessonID = lessonID
+		if err := s.enrollments.Save(enrollment); err != nil {
+			return nil, 0, err
+		}
+	}
+	return plan, index, nil
+}
+
+// ResumeLesson возвращает ID урока, с которого пользователь продолжит курс:
+// последний открытый урок, если он не пройден, иначе первый непройденный
+// Если пройдены все уроки, курс начинается сначала
+func (s *EnrollmentService) ResumeLesson(userID int64, courseID int) (int, error) {
+	plan, err := s.Plan(userID, courseID)
+	if err != nil {
+		return 0, err
+	}
+	if len(plan) == 0 {
+		return 0, repository.ErrLessonNotFound
+	}
+
+	enrollment, _ := s.enrollments.Get(userID, courseID)
+	for _, step := range plan {
+		if step.Lesson.ID == enrollment.LastLessonID && !step.Completed {
+			return step.Lesson.ID, nil
+		}
+	}
+	for _, step := range plan {
+		if !step.Completed {
+			return step.Lesson.ID, nil
+		}
+	}
+	return plan[0].Lesson.ID, nil
+}
+
+// CompleteLesson отмечает открытый урок пройденным
+// Когда пройдены все уроки курса, запись отмечается завершённой
+func (s *EnrollmentService) CompleteLesson(userID int64, courseID, lessonID int) (domain.CourseProgress, error) {
+	course, err := s.courses.Get(courseID)
+	if err != nil {
+		return domain.CourseProgress{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return domain.CourseProgress{}, ErrNotEnrolled
+	}
+	if _, err := lessonIndex(s.plan(enrollment), lessonID); err != nil {
+		return domain.CourseProgress{}, err
+	}
+
+	if !enrollment.LessonCompleted(lessonID) {
+		enrollment.CompletedLessons = append(enrollment.CompletedLessons, lessonID)
+	}
+	enrollment.LastLessonID = lessonID
+
+	progress := s.progress(course, enrollment)
+	if progress.Total > 0 && progress.Done == progress.Total && !enrollment.Completed() {
+		enrollment.CompletedAt = time.Now()
+		progress.Enrollment = enrollment
+		log.Printf("Пользователь %d прошёл курс %d", userID, courseID)
+	}
+
+	if err := s.enrollments.Save(enrollment); err != nil {
+		return domain.CourseProgress{}, err
+	}
+	return progress, nil
+}
+
+// RecordQuiz сохраняет результат завершённой попытки теста в записи на курс
+func (s *EnrollmentService) RecordQuiz(userID int64, courseID int, quiz domain.Quiz, score int) (domain.QuizResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return domain.QuizResult{}, ErrNotEnrolled
+	}
+
+	if enrollment.Quizzes == nil {
+		enrollment.Quizzes = make(map[int]domain.QuizResult)
+	}
+	result := enrollment.Quizzes[quiz.ID]
+	result.Attempts++
+	result.LastScore = score
+	result.BestScore = max(result.BestScore, score)
+	result.Passed = result.Passed || score >= quiz.PassPercent
+	result.LastAt = time.Now()
+	enrollment.Quizzes[quiz.ID] = result
+
+	if err := s.enrollments.Save(enrollment); err != nil {
+		return domain.QuizResult{}, err
+	}
+	return result, nil
+}
+
+// ReturnQuizAttempts возвращает пользователю n использованных попыток теста quizID (0 — все попытки)
+func (s *EnrollmentService) ReturnQuizAttempts(userID int64, courseID, quizID, n int) (domain.QuizResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, exists := s.enrollments.Get(userID, courseID)
+	if !exists {
+		return domain.QuizResult{}, ErrNotEnrolled
+	}
+
+	result := enrollment.Quizzes[quizID]
+	if n == 0 {
+		result.Attempts = 0
+	} else {
+		result.Attempts = max(result.Attempts-n, 0)
+	}
+	if enrollment.Quizzes == nil {
+		enrollment.Quizzes = make(map[int]domain.QuizResult)
+	}
+	enrollment.Quizzes[quizID] = result
+
+	if err := s.enrollments.Save(enrollment); err != nil {
+		return domain.QuizResult{}, err
+	}
+	return result, nil
+}
+
+// EnrolledUserIDs возвращает ID пользователей, записанных на курс
+// Реализует EnrollmentSource для сегмента рассылок «записанные на курс»
+func (s *EnrollmentService) EnrolledUserIDs(courseID int) []int64 {
+	return s.enrollments.UserIDsByCourse(courseID)
+}
+
+// progress считает прогресс по урокам, которые есть в курсе сейчас
+// Пройденные уроки, удалённые из курса, не учитываются
+func (s *EnrollmentService) progress(course domain.Course, enrollment domain.Enrollment) domain.CourseProgress {
+	progress := domain.CourseProgress{Course: course, Enrollment: enrollment}
+	if s.lessons == nil {
+		return progress
+	}
+
+	lessons := s.lessons.ByCourse(course.ID)
+	progress.Total = len(lessons)
+	for _, lesson := range lessons {
+		if enrollment.LessonCompleted(lesson.ID) {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+// plan собирает уроки курса с отметками для записи enrollment
+func (s *EnrollmentService) plan(enrollment domain.Enrollment) []domain.LessonStep {
+	if s.lessons == nil {
+		return nil
+	}
+
+	now := time.Now()
+	lessons := s.lessons.ByCourse(enrollment.CourseID)
+	plan := make([]domain.LessonStep, len(lessons))
+	for i, lesson := range lessons {
+		plan[i] = domain.LessonStep{
+			Lesson:    lesson,
+			Number:    i + 1,
+			Completed: enrollment.LessonCompleted(lesson.ID),
+			Locked:    !lesson.Unlocked(enrollment.EnrolledAt, now),
+			UnlockAt:  lesson.UnlockAt(enrollment.EnrolledAt),
+		}
+	}
+	return plan
+}
+
+// allowed проверяет доступ пользователя к курсу
+func (s *EnrollmentService) allowed(userID int64, course domain.Course) bool {
+	return s.access == nil || s.access.CourseAllowed(userID, course)
+}
+
+// lessonIndex находит открытый урок в плане курса
+func lessonIndex(plan []domain.LessonStep, lessonID int) (int, error) {
+	for i, step := range plan {
+		if step.Lesson.ID != lessonID {
+			continue
+		}
+		if step.Locked {
+			return 0, fmt.Errorf("%w: откроется %s", ErrLessonLocked, step.UnlockAt.Format("02.01.2006 15:04"))
+		}
+		return i, nil
+	}
+	return 0, repository.ErrLessonNotFound
+}
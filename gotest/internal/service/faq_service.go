@@ -0,0 +1,304 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+const (
+	maxFAQPatterns   = 20   // Максимум шаблонов в правиле
+	maxFAQPattern    = 200  // Максимальная длина шаблона
+	maxFAQAnswer     = 4000 // Максимальная длина ответа (лимит сообщения Telegram — 4096)
+	maxFAQButtons    = 6    // Максимум кнопок под ответом
+	maxFAQButtonText = 64   // Максимальная длина подписи кнопки
+	maxFAQButtonData = 64   // Максимальная длина callback_data (лимит Telegram, байт)
+
+	// faqFuzzyThreshold — доля слов фразы, которые должны найтись в тексте для нечёткого совпадения
+	faqFuzzyThreshold = 0.75
+)
+
+// ErrFAQInvalid — правило автоответа не прошло проверку (подробности — в тексте ошибки)
+var ErrFAQInvalid = errors.New("некорректное правило")
+
+// FAQMatchResult — правило, подходящее под текст сообщения
+type FAQMatchResult struct {
+	Rule    domain.FAQRule
+	Pattern string  // Шаблон, который совпал
+	Score   float64 // Качество совпадения: 1 — точное, меньше — нечёткое
+}
+
+// FAQService управляет правилами автоответов и подбирает ответ на текстовые сообщения
+type FAQService struct {
+	rules *repository.FAQRepository
+	audit *AuditService
+
+	mu      sync.Mutex
+	regexps map[string]*regexp.Regexp // Скомпилированные регулярные выражения, ключ - шаблон
+}
+
+// NewFAQService создаёт сервис автоответов
+func NewFAQService(rules *repository.FAQRepository, audit *AuditService) *FAQService {
+	return &FAQService{
+		rules:   rules,
+		audit:   audit,
+		regexps: make(map[string]*regexp.Regexp),
+	}
+}
+
+// SeedIfEmpty заполняет пустое хранилище правилами seed (при первом запуске)
+func (s *FAQService) SeedIfEmpty(seed []domain.FAQRule) error {
+	if s.rules.Count() > 0 {
+		return nil
+	}
+
+	for i := range seed {
+		seed[i].UpdatedAt = time.Now()
+		if err := s.rules.Create(&seed[i]); err != nil {
+			return fmt.Errorf("ошибка добавления правила «%s»: %w", seed[i].Name, err)
+		}
+	}
+	if len(seed) > 0 {
+		log.Printf("Автоответы заполнены начальными данными: %d правил", len(seed))
+	}
+	return nil
+}
+
+// List возвращает все правила в порядке проверки
+func (s *FAQService) List() []domain.FAQRule {
+	return s.rules.All()
+}
+
+// Get возвращает правило по ID
+func (s *FAQService) Get(id int) (domain.FAQRule, error) {
+	return s.rules.Get(id)
+}
+
+// Create проверяет и сохраняет новое правило от имени actorID
+func (s *FAQService) Create(actorID int64, rule *domain.FAQRule) error {
+	err := s.validate(*rule)
+	if err == nil {
+		rule.UpdatedBy = actorID
+		rule.UpdatedAt = time.Now()
+		err = s.rules.Create(rule)
+	}
+
+	s.audit.Record(actorID, domain.AuditFAQCreate, int64(rule.ID), faqAuditParams(*rule), err)
+	return err
+}
+
+// Update проверяет и сохраняет изменённое правило от имени actorID
+func (s *FAQService) Update(actorID int64, rule domain.FAQRule) error {
+	err := s.validate(rule)
+	if err == nil {
+		rule.UpdatedBy = actorID
+		rule.UpdatedAt = time.Now()
+		err = s.rules.Update(rule)
+	}
+
+	s.audit.Record(actorID, domain.AuditFAQUpdate, int64(rule.ID), faqAuditParams(rule), err)
+	return err
+}
+
+// SetEnabled включает или выключает правило
+func (s *FAQService) SetEnabled(actorID int64, id int, enabled bool) (domain.FAQRule, error) {
+	rule, err := s.rules.Get(id)
+	if err == nil {
+		rule.Enabled = enabled
+		rule.UpdatedBy = actorID
+		rule.UpdatedAt = time.Now()
+		err = s.rules.Update(rule)
+	}
+
+	s.audit.Record(actorID, domain.AuditFAQUpdate, int64(id), map[string]string{"enabled": strconv.FormatBool(enabled)}, err)
+	return rule, err
+}
+
+// Delete удаляет правило
+func (s *FAQService) Delete(actorID int64, id int) error {
+	err := s.rules.Delete(id)
+	s.audit.Record(actorID, domain.AuditFAQDelete, int64(id), nil, err)
+	return err
+}
+
+// Match подбирает правило для текста сообщения: включённое правило с наибольшим приоритетом
+// Второе значение false, если ни одно правило не подошло
+func (s *FAQService) Match(text string) (domain.FAQRule, bool) {
+	for _, result := range s.Test(text) {
+		if result.Rule.Enabled {
+			return result.Rule, true
+		}
+	}
+	return domain.FAQRule{}, false
+}
+
+// Test возвращает все правила (включая выключенные), подходящие под текст, в порядке срабатывания:
+// по убыванию приоритета, затем по качеству совпадения
+func (s *FAQService) Test(text string) []FAQMatchResult {
+	normalized := faqNormalize(text)
+	words := searchWords(text)
+
+	var results []FAQMatchResult
+	for _, rule := range s.rules.All() {
+		if result, ok := s.match(rule, text, normalized, words); ok {
+			results = append(results, result)
+		}
+	}
+
+	// Правила уже отсортированы по приоритету и ID; внутри одного приоритета выше — лучшее совпадение
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rule.Priority != results[j].Rule.Priority {
+			return results[i].Rule.Priority > results[j].Rule.Priority
+		}
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// match проверяет шаблоны правила и возвращает лучшее совпадение
+func (s *FAQService) match(rule domain.FAQRule, text, normalized string, words []string) (FAQMatchResult, bool) {
+	best := FAQMatchResult{Rule: rule}
+	for _, pattern := range rule.Patterns {
+		score := 0.0
+		switch rule.Match {
+		case domain.FAQKeyword:
+			if keyword := faqNormalize(pattern); keyword != "" && strings.Contains(normalized, keyword) {
+				score = 1
+			}
+		case domain.FAQRegex:
+			re, err := s.compile(pattern)
+			if err != nil {
+				log.Printf("Ошибка регулярного выражения в правиле #%d: %v", rule.ID, err)
+				continue
+			}
+			if re.MatchString(text) {
+				score = 1
+			}
+		case domain.FAQFuzzy:
+			score = phraseScore(searchWords(pattern), words)
+		}
+
+		if score > best.Score {
+			best.Score = score
+			best.Pattern = pattern
+		}
+	}
+	return best, best.Score > 0
+}
+
+// compile возвращает скомпилированное регулярное выражение без учёта регистра
+func (s *FAQService) compile(pattern string) (*regexp.Regexp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if re, exists := s.regexps[pattern]; exists {
+		return re, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	s.regexps[pattern] = re
+	return re, nil
+}
+
+// validate проверяет правило перед сохранением
+func (s *FAQService) validate(rule domain.FAQRule) error {
+	switch {
+	case strings.TrimSpace(rule.Name) == "":
+		return fmt.Errorf("%w: нужно название", ErrFAQInvalid)
+	case !slices.Contains(domain.FAQMatches, rule.Match):
+		return fmt.Errorf("%w: способ сопоставления должен быть keyword, regex или fuzzy", ErrFAQInvalid)
+	case len(rule.Patterns) == 0:
+		return fmt.Errorf("%w: нужен хотя бы один шаблон", ErrFAQInvalid)
+	case len(rule.Patterns) > maxFAQPatterns:
+		return fmt.Errorf("%w: не больше %d шаблонов", ErrFAQInvalid, maxFAQPatterns)
+	case strings.TrimSpace(rule.Answer[domain.DefaultLanguage]) == "":
+		return fmt.Errorf("%w: нужен ответ на языке %s", ErrFAQInvalid, domain.DefaultLanguage)
+	case len(rule.Buttons) > maxFAQButtons:
+		return fmt.Errorf("%w: не больше %d кнопок", ErrFAQInvalid, maxFAQButtons)
+	}
+
+	for _, pattern := range rule.Patterns {
+		if strings.TrimSpace(pattern) == "" || utf8.RuneCountInString(pattern) > maxFAQPattern {
+			return fmt.Errorf("%w: шаблон должен быть от 1 до %d символов", ErrFAQInvalid, maxFAQPattern)
+		}
+		if rule.Match == domain.FAQRegex {
+			if _, err := s.compile(pattern); err != nil {
+				return fmt.Errorf("%w: регулярное выражение «%s»: %v", ErrFAQInvalid, pattern, err)
+			}
+		}
+		if rule.Match == domain.FAQFuzzy && len(searchWords(pattern)) == 0 {
+			return fmt.Errorf("%w: во фразе «%s» нет слов", ErrFAQInvalid, pattern)
+		}
+	}
+
+	for lang, answer := range rule.Answer {
+		if utf8.RuneCountInString(answer) > maxFAQAnswer {
+			return fmt.Errorf("%w: ответ (%s) длиннее %d символов", ErrFAQInvalid, lang, maxFAQAnswer)
+		}
+	}
+
+	for i, button := range rule.Buttons {
+		label := button.Text[domain.DefaultLanguage]
+		switch {
+		case strings.TrimSpace(label) == "":
+			return fmt.Errorf("%w: у кнопки %d нет подписи на языке %s", ErrFAQInvalid, i+1, domain.DefaultLanguage)
+		case utf8.RuneCountInString(label) > maxFAQButtonText:
+			return fmt.Errorf("%w: подпись кнопки %d длиннее %d символов", ErrFAQInvalid, i+1, maxFAQButtonText)
+		case (button.URL == "") == (button.Data == ""):
+			return fmt.Errorf("%w: у кнопки %d должна быть ссылка или данные кнопки", ErrFAQInvalid, i+1)
+		case button.URL != "" && !strings.HasPrefix(button.URL, "https://") && !strings.HasPrefix(button.URL, "http://") && !strings.HasPrefix(button.URL, "tg://"):
+			return fmt.Errorf("%w: ссылка кнопки %d должна начинаться с https://, http:// или tg://", ErrFAQInvalid, i+1)
+		case len(button.Data) > maxFAQButtonData:
+			return fmt.Errorf("%w: данные кнопки %d длиннее %d байт", ErrFAQInvalid, i+1, maxFAQButtonData)
+		}
+	}
+	return nil
+}
+
+// phraseScore возвращает долю слов фразы, найденных в тексте (с допуском на опечатки)
+// Возвращает 0, если найдено меньше faqFuzzyThreshold слов
+func phraseScore(phrase, words []string) float64 {
+	if len(phrase) == 0 {
+		return 0
+	}
+
+	found := 0
+	for _, term := range phrase {
+		if wordScore(term, words) > 0 {
+			found++
+		}
+	}
+
+	score := float64(found) / float64(len(phrase))
+	if score < faqFuzzyThreshold {
+		return 0
+	}
+	return score
+}
+
+// faqNormalize приводит текст к виду для поиска ключевых слов: нижний регистр, ё → е
+func faqNormalize(text string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(text)), "ё", "е")
+}
+
+// faqAuditParams возвращает параметры правила для журнала аудита
+func faqAuditParams(rule domain.FAQRule) map[string]string {
+	return map[string]string{
+		"name":     rule.Name,
+		"match":    string(rule.Match),
+		"priority": strconv.Itoa(rule.Priority),
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+var (
+	// ErrInvalidFeatureName — имя флага содержит недопустимые символы
+	ErrInvalidFeatureName = errors.New("имя флага может содержать только a-z, 0-9 и _ (до 32 символов)")
+	// ErrFeatureNotFound — флаг не найден
+	ErrFeatureNotFound = errors.New("флаг не найден")
+	// ErrInvalidPercent — процент раскатки вне диапазона 0–100
+	ErrInvalidPercent = errors.New("процент должен быть от 0 до 100")
+)
+
+// featureNamePattern — допустимые имена флагов
+var featureNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// FeatureService вычисляет и изменяет флаги функций
+// Флаги читаются из хранилища при каждой проверке, поэтому изменения действуют сразу
+type FeatureService struct {
+	flags    *repository.FeatureRepository
+	settings *repository.SettingsRepository
+	audit    *AuditService
+	mu       sync.Mutex // Последовательные изменения флагов
+}
+
+// NewFeatureService создаёт сервис флагов функций
+func NewFeatureService(flags *repository.FeatureRepository, settings *repository.SettingsRepository, audit *AuditService) *FeatureService {
+	return &FeatureService{
+		flags:    flags,
+		settings: settings,
+		audit:    audit,
+	}
+}
+
+// Enabled проверяет, включён ли флаг name для пользователя userID
+func (s *FeatureService) Enabled(name string, userID int64) bool {
+	flag, exists := s.flags.Get(name)
+	if !exists {
+		return false
+	}
+	return flag.EnabledFor(userID, s.settings.Get(userID).Language)
+}
+
+// Gate возвращает проверку флагов для пользователя userID
+// Удобно передавать в построители клавиатур
+func (s *FeatureService) Gate(userID int64) domain.FeatureGate {
+	lang := s.settings.Get(userID).Language
+	return func(name string) bool {
+		flag, exists := s.flags.Get(name)
+		return exists && flag.EnabledFor(userID, lang)
+	}
+}
+
+// Get возвращает флаг по имени
+// Известный коду флаг, который ещё не настраивался, возвращается выключенным
+func (s *FeatureService) Get(name string) (domain.FeatureFlag, bool) {
+	if flag, exists := s.flags.Get(name); exists {
+		return flag, true
+	}
+	if _, known := domain.KnownFeatures[name]; known {
+		return newFeatureFlag(name), true
+	}
+	return domain.FeatureFlag{}, false
+}
+
+// List возвращает сохранённые флаги и ещё не настроенные известные флаги
+func (s *FeatureService) List() []domain.FeatureFlag {
+	flags := s.flags.All()
+	for name := range domain.KnownFeatures {
+		if _, exists := s.flags.Get(name); !exists {
+			flags = append(flags, newFeatureFlag(name))
+		}
+	}
+	slices.SortFunc(flags, func(a, b domain.FeatureFlag) int { return strings.Compare(a.Name, b.Name) })
+	return flags
+}
+
+// SetEnabled включает или выключает флаг для всех (кроме списка пользователей)
+// Несуществующий флаг создаётся с раскаткой на 100%
+func (s *FeatureService) SetEnabled(actorID int64, name string, enabled bool) error {
+	return s.update(actorID, name, "enabled", strconv.FormatBool(enabled), func(flag *domain.FeatureFlag) error {
+		flag.Enabled = enabled
+		return nil
+	})
+}
+
+// SetPercent задаёт долю пользователей, для которых включён флаг
+func (s *FeatureService) SetPercent(actorID int64, name string, percent int) error {
+	return s.update(actorID, name, "percent", strconv.Itoa(percent), func(flag *domain.FeatureFlag) error {
+		if percent < 0 || percent > 100 {
+			return ErrInvalidPercent
+		}
+		flag.Percent = percent
+		return nil
+	})
+}
+
+// Allow добавляет пользователя в список, для которого флаг включён всегда
+func (s *FeatureService) Allow(actorID int64, name string, userID int64) error {
+	return s.update(actorID, name, "allow", strconv.FormatInt(userID, 10), func(flag *domain.FeatureFlag) error {
+		if !slices.Contains(flag.Users, userID) {
+			flag.Users = append(flag.Users, userID)
+		}
+		return nil
+	})
+}
+
+// Disallow убирает пользователя из списка флага
+func (s *FeatureService) Disallow(actorID int64, name string, userID int64) error {
+	return s.update(actorID, name, "disallow", strconv.FormatInt(userID, 10), func(flag *domain.FeatureFlag) error {
+		flag.Users = slices.DeleteFunc(flag.Users, func(id int64) bool { return id == userID })
+		return nil
+	})
+}
+
+// SetLocales ограничивает флаг языками интерфейса; пустой список снимает ограничение
+func (s *FeatureService) SetLocales(actorID int64, name string, locales []string) error {
+	return s.update(actorID, name, "locales", strings.Join(locales, ","), func(flag *domain.FeatureFlag) error {
+		flag.Locales = locales
+		return nil
+	})
+}
+
+// Delete удаляет флаг; флаг, который проверяет код, становится выключенным
+func (s *FeatureService) Delete(actorID int64, name string) error {
+	s.mu.Lock()
+	removed, err := s.flags.Delete(name)
+	s.mu.Unlock()
+	if err == nil && !removed {
+		err = ErrFeatureNotFound
+	}
+	s.audit.Record(actorID, domain.AuditFeatureDelete, 0, map[string]string{"name": name}, err)
+	return err
+}
+
+// update применяет изменение к флагу, сохраняет его и записывает в журнал аудита
+func (s *FeatureService) update(actorID int64, name, field, value string, change func(flag *domain.FeatureFlag) error) error {
+	err := s.apply(actorID, name, change)
+	s.audit.Record(actorID, domain.AuditFeatureUpdate, 0, map[string]string{"name": name, field: value}, err)
+	return err
+}
+
+// apply применяет изменение к флагу и сохраняет его
+func (s *FeatureService) apply(actorID int64, name string, change func(flag *domain.FeatureFlag) error) error {
+	if !featureNamePattern.MatchString(name) {
+		return ErrInvalidFeatureName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flag, exists := s.flags.Get(name)
+	if !exists {
+		flag = newFeatureFlag(name)
+	}
+	if err := change(&flag); err != nil {
+		return err
+	}
+
+	flag.UpdatedBy = actorID
+	flag.UpdatedAt = time.Now()
+	return s.flags.Save(flag)
+}
+
+// newFeatureFlag создаёт выключенный флаг с раскаткой на 100%
+func newFeatureFlag(name string) domain.FeatureFlag {
+	return domain.FeatureFlag{Name: name, Percent: 100}
+}
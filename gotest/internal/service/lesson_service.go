@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+const (
+	maxLessonTitle   = 64   // Максимальная длина названия урока
+	maxLessonText    = 3500 // Максимальная длина текстового урока (лимит сообщения Telegram — 4096)
+	maxLessonCaption = 900  // Максимальная длина подписи к медиа (лимит подписи Telegram — 1024)
+	maxLessonDrip    = 365  // Максимальная задержка открытия урока в днях
+)
+
+// ErrLessonInvalid — урок не прошёл проверку (подробности — в тексте ошибки)
+var ErrLessonInvalid = errors.New("некорректный урок")
+
+// LessonService управляет уроками курсов
+type LessonService struct {
+	lessons *repository.LessonRepository
+	courses *CourseService
+	audit   *AuditService
+	mu      sync.Mutex // Последовательные изменения порядка уроков
+}
+
+// NewLessonService создаёт сервис уроков
+func NewLessonService(lessons *repository.LessonRepository, courses *CourseService, audit *AuditService) *LessonService {
+	return &LessonService{lessons: lessons, courses: courses, audit: audit}
+}
+
+// SeedIfEmpty заполняет пустое хранилище уроками seed (при первом запуске)
+// Уроки курсов, которых нет в каталоге, пропускаются
+func (s *LessonService) SeedIfEmpty(seed []domain.Lesson) error {
+	if s.lessons.Count() > 0 {
+		return nil
+	}
+
+	added := 0
+	for i := range seed {
+		if _, err := s.courses.Get(seed[i].CourseID); err != nil {
+			continue
+		}
+		seed[i].CreatedAt = time.Now()
+		seed[i].UpdatedAt = seed[i].CreatedAt
+		if err := s.lessons.Save(&seed[i]); err != nil {
+			return fmt.Errorf("ошибка добавления урока курса %d: %w", seed[i].CourseID, err)
+		}
+		added++
+	}
+	if added > 0 {
+		log.Printf("Уроки заполнены начальными данными: %d уроков", added)
+	}
+	return nil
+}
+
+// ByCourse возвращает уроки курса в порядке прохождения
+// Реализует LessonSource для подсчёта прогресса
+func (s *LessonService) ByCourse(courseID int) []domain.Lesson {
+	return s.lessons.ByCourse(courseID)
+}
+
+// Get возвращает урок по ID
+func (s *LessonService) Get(id int) (domain.Lesson, error) {
+	return s.lessons.Get(id)
+}
+
+// Create проверяет и сохраняет новый урок от имени actorID
+// Урок добавляется в конец курса
+func (s *LessonService) Create(actorID int64, lesson *domain.Lesson) error {
+	err := s.create(lesson)
+	s.audit.Record(actorID, domain.AuditLessonCreate, int64(lesson.ID), map[string]string{
+		"course": strconv.Itoa(lesson.CourseID),
+		"title":  lesson.Title.Get(domain.DefaultLanguage),
+	}, err)
+	return err
+}
+
+// create сохраняет новый урок без записи в журнал
+func (s *LessonService) create(lesson *domain.Lesson) error {
+	if err := ValidateLesson(*lesson); err != nil {
+		return err
+	}
+	if _, err := s.courses.Get(lesson.CourseID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lesson.ID = 0
+	lesson.Position = 0
+	for _, other := range s.lessons.ByCourse(lesson.CourseID) {
+		lesson.Position = max(lesson.Position, other.Position)
+	}
+	lesson.Position++
+	lesson.CreatedAt = time.Now()
+	lesson.UpdatedAt = lesson.CreatedAt
+
+	return s.lessons.Save(lesson)
+}
+
+// Update проверяет и сохраняет изменённый урок от имени actorID
+// field - что изменилось (для журнала аудита)
+func (s *LessonService) Update(actorID int64, lesson *domain.Lesson, field string) error {
+	err := ValidateLesson(*lesson)
+	if err == nil {
+		lesson.UpdatedAt = time.Now()
+		err = s.lessons.Save(lesson)
+	}
+	s.audit.Record(actorID, domain.AuditLessonUpdate, int64(lesson.ID), map[string]string{"field": field}, err)
+	return err
+}
+
+// Move сдвигает урок на одну позицию вверх (delta < 0) или вниз (delta > 0)
+func (s *LessonService) Move(actorID int64, id int, delta int) error {
+	err := s.move(id, delta)
+	s.audit.Record(actorID, domain.AuditLessonMove, int64(id), map[string]string{"delta": strconv.Itoa(delta)}, err)
+	return err
+}
+
+// move меняет урок местами с соседним и перенумеровывает позиции без записи в журнал
+func (s *LessonService) move(id int, delta int) error {
+	lesson, err := s.lessons.Get(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lessons := s.lessons.ByCourse(lesson.CourseID)
+	from := -1
+	for i := range lessons {
+		if lessons[i].ID == id {
+			from = i
+			break
+		}
+	}
+
+	to := from + 1
+	if delta < 0 {
+		to = from - 1
+	}
+	if delta == 0 || to < 0 || to >= len(lessons) {
+		return nil // Урок уже первый или последний
+	}
+	lessons[from], lessons[to] = lessons[to], lessons[from]
+
+	// Сохраняем только уроки, позиция которых изменилась
+	for i := range lessons {
+		if lessons[i].Position == i+1 {
+			continue
+		}
+		lessons[i].Position = i + 1
+		if err := s.lessons.Save(&lessons[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete удаляет урок от имени actorID
+// Отметки о прохождении урока остаются в записях, но больше не учитываются в прогрессе
+func (s *LessonService) Delete(actorID int64, id int) error {
+	lesson, _ := s.lessons.Get(id)
+	err := s.lessons.Delete(id)
+	s.audit.Record(actorID, domain.AuditLessonDelete, int64(id), map[string]string{
+		"course": strconv.Itoa(lesson.CourseID),
+		"title":  lesson.Title.Get(domain.DefaultLanguage),
+	}, err)
+	return err
+}
+
+// ValidateLesson проверяет поля урока, которые заполняет администратор
+func ValidateLesson(lesson domain.Lesson) error {
+	if strings.TrimSpace(lesson.Title.Get(domain.DefaultLanguage)) == "" {
+		return fmt.Errorf("%w: нужно название на языке %s", ErrLessonInvalid, domain.DefaultLanguage)
+	}
+	for lang, title := range lesson.Title {
+		if utf8.RuneCountInString(title) > maxLessonTitle {
+			return fmt.Errorf("%w: название (%s) длиннее %d символов", ErrLessonInvalid, lang, maxLessonTitle)
+		}
+	}
+
+	limit := maxLessonCaption
+	switch lesson.Kind {
+	case domain.LessonText:
+		limit = maxLessonText
+		if strings.TrimSpace(lesson.Body.Get(domain.DefaultLanguage)) == "" {
+			return fmt.Errorf("%w: нужен текст урока на языке %s", ErrLessonInvalid, domain.DefaultLanguage)
+		}
+	case domain.LessonPhoto, domain.LessonVideo, domain.LessonDocument:
+		if lesson.MediaFileID == "" {
+			return fmt.Errorf("%w: не прикреплён файл", ErrLessonInvalid)
+		}
+	default:
+		return fmt.Errorf("%w: неизвестный вид урока %q", ErrLessonInvalid, lesson.Kind)
+	}
+	for lang, body := range lesson.Body {
+		if utf8.RuneCountInString(body) > limit {
+			return fmt.Errorf("%w: текст (%s) длиннее %d символов", ErrLessonInvalid, lang, limit)
+		}
+	}
+
+	if lesson.UnlockAfterDays < 0 || lesson.UnlockAfterDays > maxLessonDrip {
+		return fmt.Errorf("%w: урок может открываться через 0–%d дней", ErrLessonInvalid, maxLessonDrip)
+	}
+	return nil
+}
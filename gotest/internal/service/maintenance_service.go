@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// ErrUnknownLanguage — язык не поддерживается ботом
+var ErrUnknownLanguage = errors.New("неизвестный язык")
+
+// defaultMaintenanceMessages — встроенные тексты режима обслуживания по языкам
+var defaultMaintenanceMessages = map[string]string{
+	"ru": "🛠 Бот на техническом обслуживании. Пожалуйста, попробуйте позже.",
+	"en": "🛠 The bot is under maintenance. Please try again later.",
+	"zh": "🛠 机器人正在维护中，请稍后再试。",
+}
+
+// MaintenanceService управляет режимом обслуживания
+// Пока режим включён, обрабатываются только обновления сотрудников (роли с доступом к админ-панели)
+type MaintenanceService struct {
+	state    *repository.MaintenanceRepository
+	settings *repository.SettingsRepository
+	access   *AccessService
+	audit    *AuditService
+	fallback string     // Текст из MAINTENANCE_MESSAGE для языков без своего текста
+	mu       sync.Mutex // Последовательные изменения состояния
+}
+
+// NewMaintenanceService создаёт сервис режима обслуживания
+// fallback - текст из конфигурации; если пуст, используются встроенные тексты
+func NewMaintenanceService(
+	state *repository.MaintenanceRepository,
+	settings *repository.SettingsRepository,
+	access *AccessService,
+	audit *AuditService,
+	fallback string,
+) *MaintenanceService {
+	return &MaintenanceService{
+		state:    state,
+		settings: settings,
+		access:   access,
+		audit:    audit,
+		fallback: fallback,
+	}
+}
+
+// Enabled возвращает true, если режим обслуживания включён
+func (s *MaintenanceService) Enabled() bool {
+	return s.state.Get().Enabled
+}
+
+// State возвращает текущее состояние режима
+func (s *MaintenanceService) State() domain.Maintenance {
+	return s.state.Get()
+}
+
+// Blocks возвращает true, если обновление от userID нужно отклонить
+func (s *MaintenanceService) Blocks(userID int64) bool {
+	return s.Enabled() && !s.access.Can(userID, domain.PermAdminPanel)
+}
+
+// Message возвращает текст режима обслуживания на языке пользователя
+func (s *MaintenanceService) Message(userID int64) string {
+	lang := s.settings.Get(userID).Language
+	state := s.state.Get()
+
+	if text, ok := state.Messages[lang]; ok {
+		return text
+	}
+	if s.fallback != "" {
+		return s.fallback
+	}
+	if text, ok := defaultMaintenanceMessages[lang]; ok {
+		return text
+	}
+	return defaultMaintenanceMessages["ru"]
+}
+
+// SetEnabled включает или выключает режим от имени actorID
+// actorID = 0 означает включение из конфигурации (MAINTENANCE_MODE)
+func (s *MaintenanceService) SetEnabled(actorID int64, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state.Get()
+	state.Enabled = enabled
+	state.ChangedBy = actorID
+	state.ChangedAt = time.Now()
+	err := s.state.Save(state)
+
+	action, status := domain.AuditMaintenanceOff, "выключен"
+	if enabled {
+		action, status = domain.AuditMaintenanceOn, "включён"
+	}
+	s.audit.Record(actorID, action, 0, nil, err)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Режим обслуживания %s (изменил %d)", status, actorID)
+	return nil
+}
+
+// SetMessage задаёт текст режима для языка lang
+// Пустой text возвращает текст по умолчанию
+func (s *MaintenanceService) SetMessage(actorID int64, lang, text string) error {
+	if _, ok := defaultMaintenanceMessages[lang]; !ok {
+		return ErrUnknownLanguage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state.Get()
+	if text == "" {
+		delete(state.Messages, lang)
+	} else {
+		state.Messages[lang] = text
+	}
+	err := s.state.Save(state)
+
+	s.audit.Record(actorID, domain.AuditMaintenanceMsg, 0, map[string]string{"language": lang, "text": text}, err)
+	return err
+}
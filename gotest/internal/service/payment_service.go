@@ -0,0 +1,367 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// orderTTL — сколько действует выставленный счёт; после этого заказ нужно оформить заново
+const orderTTL = 24 * time.Hour
+
+var (
+	// ErrNotForSale — товар бесплатный или недоступен для покупки
+	ErrNotForSale = errors.New("товар недоступен для покупки")
+	// ErrAlreadyPurchased — у пользователя уже есть доступ к товару
+	ErrAlreadyPurchased = errors.New("у вас уже есть доступ")
+	// ErrOrderExpired — счёт устарел
+	ErrOrderExpired = errors.New("счёт устарел, оформите заказ заново")
+	// ErrOrderMismatch — платёж не соответствует заказу (другой пользователь, сумма или валюта)
+	ErrOrderMismatch = errors.New("платёж не соответствует заказу")
+	// ErrOrderNotPending — заказ уже оплачен или возвращён
+	ErrOrderNotPending = errors.New("заказ уже оплачен или возвращён")
+	// ErrNotRefundable — вернуть можно только оплаченный заказ
+	ErrNotRefundable = errors.New("вернуть можно только оплаченный заказ")
+	// ErrNotRefunding — заказ не ждёт возврата денег
+	ErrNotRefunding = errors.New("заказ не ждёт возврата")
+)
+
+// Payment — данные успешного платежа из обновления Telegram (successful_payment)
+type Payment struct {
+	UserID           int64
+	Payload          string
+	Currency         string
+	Amount           int64
+	TelegramChargeID string
+	ProviderChargeID string
+}
+
+// PaymentService управляет заказами: выставление счетов, проверка и зачисление платежей, возвраты
+// Сервис не обращается к Telegram: счета, ответы на pre_checkout_query и возвраты Stars отправляет обработчик
+type PaymentService struct {
+	orders        *repository.OrderRepository
+	courses       *CourseService
+	enrollments   *EnrollmentService
+	subscriptions *SubscriptionService
+	audit         *AuditService
+	mu            sync.Mutex // Последовательная обработка платежей
+}
+
+// NewPaymentService создаёт сервис платежей
+func NewPaymentService(orders *repository.OrderRepository, courses *CourseService, enrollments *EnrollmentService, subscriptions *SubscriptionService, audit *AuditService) *PaymentService {
+	return &PaymentService{
+		orders:        orders,
+		courses:       courses,
+		enrollments:   enrollments,
+		subscriptions: subscriptions,
+		audit:         audit,
+	}
+}
+
+// Get возвращает заказ по ID
+func (s *PaymentService) Get(id int) (domain.Order, error) {
+	return s.orders.Get(id)
+}
+
+// List возвращает заказы пользователя userID (0 — всех пользователей), новые первыми
+func (s *PaymentService) List(userID int64) []domain.Order {
+	return s.orders.List(userID)
+}
+
+// CourseAllowed возвращает true, если курс бесплатный, куплен пользователем или входит в его подписку
+func (s *PaymentService) CourseAllowed(userID int64, course domain.Course) bool {
+	return s.subscriptions.CourseAllowed(userID, course) || s.purchased(userID, course.ID)
+}
+
+// CourseInUse возвращает true, если курс кто-то купил и не вернул
+func (s *PaymentService) CourseInUse(courseID int) bool {
+	return s.purchased(0, courseID)
+}
+
+// purchased возвращает true, если у пользователя userID (0 — у любого) есть оплаченный заказ курса
+func (s *PaymentService) purchased(userID int64, courseID int) bool {
+	for _, order := range s.orders.List(userID) {
+		if order.Kind == domain.OrderCourse && order.CourseID == courseID && order.Status == domain.OrderPaid {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderCourse создаёт заказ платного курса
+func (s *PaymentService) OrderCourse(userID int64, courseID int) (domain.Order, error) {
+	course, err := s.courses.Published(courseID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if course.Free() {
+		return domain.Order{}, ErrNotForSale
+	}
+	if s.CourseAllowed(userID, course) {
+		return domain.Order{}, ErrAlreadyPurchased
+	}
+
+	return s.create(domain.Order{
+		UserID:   userID,
+		Kind:     domain.OrderCourse,
+		CourseID: course.ID,
+		Title:    course.Title.Get(domain.DefaultLanguage),
+		Amount:   course.Price,
+		Currency: course.Currency,
+	})
+}
+
+// OrderPlan создаёт заказ подписки на тарифный план
+func (s *PaymentService) OrderPlan(userID int64, planID string) (domain.Order, error) {
+	plan, err := s.subscriptions.Plan(planID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if !plan.Active || plan.Price <= 0 {
+		return domain.Order{}, ErrNotForSale
+	}
+
+	return s.create(domain.Order{
+		UserID:   userID,
+		Kind:     domain.OrderPlan,
+		PlanID:   plan.ID,
+		Days:     plan.Days,
+		Title:    fmt.Sprintf("Подписка «%s» на %d дн.", plan.Title.Get(domain.DefaultLanguage), plan.Days),
+		Amount:   plan.Price,
+		Currency: plan.Currency,
+	})
+}
+
+// create сохраняет новый заказ в ожидании оплаты
+func (s *PaymentService) create(order domain.Order) (domain.Order, error) {
+	order.Status = domain.OrderPending
+	order.CreatedAt = time.Now()
+	if err := s.orders.Create(&order); err != nil {
+		return domain.Order{}, err
+	}
+
+	log.Printf("Создан заказ #%d пользователя %d: %s, %s", order.ID, order.UserID, order.Title, order.PriceText())
+	return order, nil
+}
+
+// Checkout проверяет заказ перед оплатой (pre_checkout_query)
+// Ошибка означает, что платёж нужно отклонить; её текст показывается пользователю
+func (s *PaymentService) Checkout(userID int64, payload, currency string, amount int64) (domain.Order, error) {
+	order, err := s.byPayload(payload)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if err := s.check(order, userID, currency, amount); err != nil {
+		return order, err
+	}
+	if time.Since(order.CreatedAt) > orderTTL {
+		return order, ErrOrderExpired
+	}
+
+	// Курс мог стать доступен другим путём, пока счёт ждал оплаты
+	if order.Kind == domain.OrderCourse {
+		course, err := s.courses.Published(order.CourseID)
+		if err != nil {
+			return order, ErrNotForSale
+		}
+		if s.CourseAllowed(userID, course) {
+			return order, ErrAlreadyPurchased
+		}
+	}
+	return order, nil
+}
+
+// Complete зачисляет успешный платёж: отмечает заказ оплаченным и выдаёт доступ
+// Повторное уведомление о том же платеже возвращает заказ без повторной выдачи доступа
+func (s *PaymentService) Complete(payment Payment) (domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.byPayload(payment.Payload)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.Status == domain.OrderPaid && order.TelegramChargeID == payment.TelegramChargeID {
+		return order, nil
+	}
+	if order.Status != domain.OrderPending {
+		return order, ErrOrderNotPending
+	}
+	if err := s.check(order, payment.UserID, payment.Currency, payment.Amount); err != nil {
+		return order, err
+	}
+
+	// Деньги уже списаны: заказ сохраняется оплаченным до выдачи доступа,
+	// чтобы платёж не потерялся, даже если выдать доступ не получится
+	order.Status = domain.OrderPaid
+	order.TelegramChargeID = payment.TelegramChargeID
+	order.ProviderChargeID = payment.ProviderChargeID
+	order.PaidAt = time.Now()
+	if err := s.orders.Update(order); err != nil {
+		return order, err
+	}
+	log.Printf("Оплачен заказ #%d пользователя %d: %s", order.ID, order.UserID, order.PriceText())
+
+	return order, s.fulfil(order)
+}
+
+// fulfil выдаёт доступ по оплаченному заказу
+func (s *PaymentService) fulfil(order domain.Order) error {
+	switch order.Kind {
+	case domain.OrderCourse:
+		_, err := s.enrollments.Enroll(order.UserID, order.CourseID)
+		if err != nil && !errors.Is(err, ErrAlreadyEnrolled) {
+			return fmt.Errorf("ошибка записи на оплаченный курс по заказу #%d: %w", order.ID, err)
+		}
+	case domain.OrderPlan:
+		if _, err := s.subscriptions.Purchase(order.UserID, order.PlanID, order.Days); err != nil {
+			return fmt.Errorf("ошибка оформления подписки по заказу #%d: %w", order.ID, err)
+		}
+	}
+	return nil
+}
+
+// StartRefund сохраняет от имени actorID, что по оплаченному заказу возвращаются деньги, и закрывает доступ к курсу
+// Вызывается перед refundStarPayment: если процесс прервётся, заказ останется в возврате,
+// и повторный возврат его завершит. Заказ, уже ожидающий возврата, возвращается без изменений
+func (s *PaymentService) StartRefund(actorID int64, id int) (domain.Order, error) {
+	order, err := s.startRefund(actorID, id)
+	s.audit.Record(actorID, domain.AuditOrderRefundStart, int64(id), map[string]string{
+		"user":   strconv.FormatInt(order.UserID, 10),
+		"amount": order.PriceText(),
+	}, err)
+	return order, err
+}
+
+// startRefund начинает возврат без записи в журнал
+func (s *PaymentService) startRefund(actorID int64, id int) (domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.orders.Get(id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.Status == domain.OrderRefunding {
+		return order, nil
+	}
+	if order.Status != domain.OrderPaid {
+		return order, ErrNotRefundable
+	}
+
+	order.Status = domain.OrderRefunding
+	order.RefundedBy = actorID
+	if err := s.orders.Update(order); err != nil {
+		return order, err
+	}
+
+	log.Printf("Заказ #%d пользователя %d ожидает возврата", order.ID, order.UserID)
+	return order, nil
+}
+
+// CancelRefund возвращает заказ из возврата в оплаченные от имени actorID, если Telegram не вернул деньги
+// reason — причина отказа Telegram, она сохраняется в журнале
+func (s *PaymentService) CancelRefund(actorID int64, id int, reason string) (domain.Order, error) {
+	order, err := s.cancelRefund(id)
+	s.audit.Record(actorID, domain.AuditOrderRefundCancel, int64(id), map[string]string{
+		"user":   strconv.FormatInt(order.UserID, 10),
+		"reason": reason,
+	}, err)
+	return order, err
+}
+
+// cancelRefund отменяет возврат без записи в журнал
+func (s *PaymentService) cancelRefund(id int) (domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.orders.Get(id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.Status != domain.OrderRefunding {
+		return order, ErrNotRefunding
+	}
+
+	order.Status = domain.OrderPaid
+	order.RefundedBy = 0
+	if err := s.orders.Update(order); err != nil {
+		return order, err
+	}
+
+	log.Printf("Возврат заказа #%d пользователя %d отменён", order.ID, order.UserID)
+	return order, nil
+}
+
+// Refund отмечает оплаченный или ожидающий возврата заказ возвращённым от имени actorID и отзывает доступ
+// Деньги возвращает вызывающий: для Stars — методом refundStarPayment после StartRefund, для провайдеров — в их кабинете
+func (s *PaymentService) Refund(actorID int64, id int) (domain.Order, error) {
+	order, err := s.refund(actorID, id)
+	s.audit.Record(actorID, domain.AuditOrderRefund, int64(id), map[string]string{
+		"user":   strconv.FormatInt(order.UserID, 10),
+		"amount": order.PriceText(),
+	}, err)
+	return order, err
+}
+
+// refund возвращает заказ без записи в журнал
+func (s *PaymentService) refund(actorID int64, id int) (domain.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, err := s.orders.Get(id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if order.Status != domain.OrderPaid && order.Status != domain.OrderRefunding {
+		return order, ErrNotRefundable
+	}
+
+	order.Status = domain.OrderRefunded
+	order.RefundedAt = time.Now()
+	order.RefundedBy = actorID
+	if err := s.orders.Update(order); err != nil {
+		return order, err
+	}
+
+	// Доступ к курсу пропадает вместе с оплаченным заказом; из подписки забираются только дни,
+	// оплаченные этим заказом — остальные покупки и продления остаются в силе
+	if order.Kind == domain.OrderPlan {
+		days := order.Days
+		if days == 0 {
+			if plan, err := s.subscriptions.Plan(order.PlanID); err == nil {
+				days = plan.Days
+			}
+		}
+		if err := s.subscriptions.shorten(order.UserID, days, order.PaidAt); err != nil {
+			return order, err
+		}
+	}
+
+	log.Printf("Заказ #%d пользователя %d возвращён", order.ID, order.UserID)
+	return order, nil
+}
+
+// byPayload находит заказ по данным счёта
+func (s *PaymentService) byPayload(payload string) (domain.Order, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(payload, "order_"))
+	if err != nil || !strings.HasPrefix(payload, "order_") {
+		return domain.Order{}, repository.ErrOrderNotFound
+	}
+	return s.orders.Get(id)
+}
+
+// check сверяет платёж с заказом: пользователь, валюта и сумма
+func (s *PaymentService) check(order domain.Order, userID int64, currency string, amount int64) error {
+	if order.UserID != userID || order.Currency != currency || order.Amount != amount {
+		return ErrOrderMismatch
+	}
+	return nil
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// paymentFixture — сервисы для тестов платежей на временном хранилище
+type paymentFixture struct {
+	payments      *PaymentService
+	orders        *repository.OrderRepository
+	subscriptions *SubscriptionService
+	enrollments   *EnrollmentService
+	course        domain.Course // Опубликованный курс за 250 XTR
+}
+
+// newPaymentFixture собирает сервисы так же, как main, с тарифами по умолчанию и одним платным курсом
+func newPaymentFixture(t *testing.T) *paymentFixture {
+	t.Helper()
+
+	store, err := repository.NewJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditRepo, err := repository.NewAuditRepository(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriptionRepo, err := repository.NewSubscriptionRepository(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderRepo, err := repository.NewOrderRepository(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enrollmentRepo, err := repository.NewEnrollmentRepository(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audit := NewAuditService(auditRepo)
+	subscriptions := NewSubscriptionService(subscriptionRepo, audit, nil)
+	if err := subscriptions.SeedIfEmpty(repository.DefaultPlans()); err != nil {
+		t.Fatal(err)
+	}
+	courses := NewCourseService(repository.NewMemoryCourseRepository(), audit)
+	enrollments := NewEnrollmentService(enrollmentRepo, courses)
+	payments := NewPaymentService(orderRepo, courses, enrollments, subscriptions, audit)
+	enrollments.SetCourseAccess(payments)
+
+	course := domain.Course{
+		Title:       domain.LocalizedText{"ru": "Go для профи"},
+		Description: domain.LocalizedText{"ru": "Платный курс"},
+		Category:    "backend",
+		Price:       250,
+		Currency:    domain.CurrencyStars,
+	}
+	if err := courses.Create(1, &course); err != nil {
+		t.Fatal(err)
+	}
+	if err := courses.SetPublished(1, course.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	course.Published = true
+
+	return &paymentFixture{
+		payments:      payments,
+		orders:        orderRepo,
+		subscriptions: subscriptions,
+		enrollments:   enrollments,
+		course:        course,
+	}
+}
+
+// paid создаёт заказ курса пользователем userID и зачисляет платёж по нему
+func (f *paymentFixture) paid(t *testing.T, userID int64) domain.Order {
+	t.Helper()
+
+	order, err := f.payments.OrderCourse(userID, f.course.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err = f.payments.Complete(paymentFor(order, "charge_"+order.Payload()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+// paymentFor возвращает платёж, точно соответствующий заказу
+func paymentFor(order domain.Order, chargeID string) Payment {
+	return Payment{
+		UserID:           order.UserID,
+		Payload:          order.Payload(),
+		Currency:         order.Currency,
+		Amount:           order.Amount,
+		TelegramChargeID: chargeID,
+	}
+}
+
+func TestPaymentServiceCheckout(t *testing.T) {
+	const userID = 100
+
+	tests := []struct {
+		name     string
+		prepare  func(t *testing.T, f *paymentFixture, order *domain.Order)
+		userID   int64
+		payload  string
+		currency string
+		amount   int64
+		wantErr  error
+	}{
+		{
+			name:    "valid order",
+			wantErr: nil,
+		},
+		{
+			name:    "other user",
+			userID:  userID + 1,
+			wantErr: ErrOrderMismatch,
+		},
+		{
+			name:     "other currency",
+			currency: "RUB",
+			wantErr:  ErrOrderMismatch,
+		},
+		{
+			name:    "other amount",
+			amount:  1,
+			wantErr: ErrOrderMismatch,
+		},
+		{
+			name:    "unknown payload",
+			payload: "order_999",
+			wantErr: repository.ErrOrderNotFound,
+		},
+		{
+			name:    "malformed payload",
+			payload: "course_1",
+			wantErr: repository.ErrOrderNotFound,
+		},
+		{
+			name: "expired invoice",
+			prepare: func(t *testing.T, f *paymentFixture, order *domain.Order) {
+				order.CreatedAt = time.Now().Add(-orderTTL - time.Minute)
+				if err := f.orders.Update(*order); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: ErrOrderExpired,
+		},
+		{
+			name: "already purchased by another order",
+			prepare: func(t *testing.T, f *paymentFixture, order *domain.Order) {
+				// Второй счёт был выставлен раньше, чем оплачен первый
+				second, err := f.payments.create(*order)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := f.payments.Complete(paymentFor(second, "charge_second")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: ErrAlreadyPurchased,
+		},
+		{
+			name: "course unpublished",
+			prepare: func(t *testing.T, f *paymentFixture, order *domain.Order) {
+				if err := f.payments.courses.SetPublished(1, f.course.ID, false); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: ErrNotForSale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			order, err := f.payments.OrderCourse(userID, f.course.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.prepare != nil {
+				tt.prepare(t, f, &order)
+			}
+
+			from, payload, currency, amount := int64(userID), order.Payload(), order.Currency, order.Amount
+			if tt.userID != 0 {
+				from = tt.userID
+			}
+			if tt.payload != "" {
+				payload = tt.payload
+			}
+			if tt.currency != "" {
+				currency = tt.currency
+			}
+			if tt.amount != 0 {
+				amount = tt.amount
+			}
+
+			_, err = f.payments.Checkout(from, payload, currency, amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Checkout() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceComplete(t *testing.T) {
+	const userID = 100
+
+	tests := []struct {
+		name       string
+		payments   func(order domain.Order) []Payment
+		wantErr    error
+		wantStatus domain.OrderStatus
+		wantCharge string
+	}{
+		{
+			name: "paid once",
+			payments: func(order domain.Order) []Payment {
+				return []Payment{paymentFor(order, "charge_1")}
+			},
+			wantStatus: domain.OrderPaid,
+			wantCharge: "charge_1",
+		},
+		{
+			name: "same payment delivered twice",
+			payments: func(order domain.Order) []Payment {
+				return []Payment{paymentFor(order, "charge_1"), paymentFor(order, "charge_1")}
+			},
+			wantStatus: domain.OrderPaid,
+			wantCharge: "charge_1",
+		},
+		{
+			name: "second charge for paid order",
+			payments: func(order domain.Order) []Payment {
+				return []Payment{paymentFor(order, "charge_1"), paymentFor(order, "charge_2")}
+			},
+			wantErr:    ErrOrderNotPending,
+			wantStatus: domain.OrderPaid,
+			wantCharge: "charge_1",
+		},
+		{
+			name: "amount mismatch",
+			payments: func(order domain.Order) []Payment {
+				payment := paymentFor(order, "charge_1")
+				payment.Amount--
+				return []Payment{payment}
+			},
+			wantErr:    ErrOrderMismatch,
+			wantStatus: domain.OrderPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			order, err := f.payments.OrderCourse(userID, f.course.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, payment := range tt.payments(order) {
+				_, err = f.payments.Complete(payment)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Complete() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got, err := f.payments.Get(order.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus || got.TelegramChargeID != tt.wantCharge {
+				t.Errorf("order = %s/%q, want %s/%q", got.Status, got.TelegramChargeID, tt.wantStatus, tt.wantCharge)
+			}
+			_, enrolled := f.enrollments.Get(userID, f.course.ID)
+			if want := tt.wantStatus == domain.OrderPaid; enrolled != want {
+				t.Errorf("enrolled = %v, want %v", enrolled, want)
+			}
+		})
+	}
+}
+
+func TestPaymentServiceCompletePlanTwice(t *testing.T) {
+	const userID = 100
+
+	f := newPaymentFixture(t)
+	order, err := f.payments.OrderPlan(userID, "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payment := paymentFor(order, "charge_1")
+	if _, err := f.payments.Complete(payment); err != nil {
+		t.Fatal(err)
+	}
+	first, _, _ := f.subscriptions.Get(userID)
+	if _, err := f.payments.Complete(payment); err != nil {
+		t.Fatal(err)
+	}
+	second, _, _ := f.subscriptions.Get(userID)
+
+	// Повторное уведомление не продлевает подписку ещё на 30 дней
+	if !second.ExpiresAt.Equal(first.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v after repeat, want %v", second.ExpiresAt, first.ExpiresAt)
+	}
+}
+
+func TestPaymentServiceRefundTransitions(t *testing.T) {
+	const (
+		userID  = 100
+		adminID = 1
+	)
+
+	// Каждый шаг — вызов сервиса, ожидаемая ошибка и состояние заказа после вызова
+	type step struct {
+		call       string // start, cancel или refund
+		wantErr    error
+		wantStatus domain.OrderStatus
+	}
+
+	tests := []struct {
+		name    string
+		pending bool // Заказ не оплачен
+		steps   []step
+	}{
+		{
+			name: "stars refund",
+			steps: []step{
+				{call: "start", wantStatus: domain.OrderRefunding},
+				{call: "refund", wantStatus: domain.OrderRefunded},
+			},
+		},
+		{
+			name: "repeated start keeps refunding",
+			steps: []step{
+				{call: "start", wantStatus: domain.OrderRefunding},
+				{call: "start", wantStatus: domain.OrderRefunding},
+				{call: "refund", wantStatus: domain.OrderRefunded},
+			},
+		},
+		{
+			name: "telegram refused refund",
+			steps: []step{
+				{call: "start", wantStatus: domain.OrderRefunding},
+				{call: "cancel", wantStatus: domain.OrderPaid},
+				{call: "cancel", wantErr: ErrNotRefunding, wantStatus: domain.OrderPaid},
+			},
+		},
+		{
+			name: "provider refund without start",
+			steps: []step{
+				{call: "refund", wantStatus: domain.OrderRefunded},
+			},
+		},
+		{
+			name: "refunded order is final",
+			steps: []step{
+				{call: "refund", wantStatus: domain.OrderRefunded},
+				{call: "start", wantErr: ErrNotRefundable, wantStatus: domain.OrderRefunded},
+				{call: "cancel", wantErr: ErrNotRefunding, wantStatus: domain.OrderRefunded},
+				{call: "refund", wantErr: ErrNotRefundable, wantStatus: domain.OrderRefunded},
+			},
+		},
+		{
+			name:    "pending order",
+			pending: true,
+			steps: []step{
+				{call: "start", wantErr: ErrNotRefundable, wantStatus: domain.OrderPending},
+				{call: "cancel", wantErr: ErrNotRefunding, wantStatus: domain.OrderPending},
+				{call: "refund", wantErr: ErrNotRefundable, wantStatus: domain.OrderPending},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			var order domain.Order
+			if tt.pending {
+				var err error
+				if order, err = f.payments.OrderCourse(userID, f.course.ID); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				order = f.paid(t, userID)
+			}
+
+			for i, step := range tt.steps {
+				var err error
+				switch step.call {
+				case "start":
+					_, err = f.payments.StartRefund(adminID, order.ID)
+				case "cancel":
+					_, err = f.payments.CancelRefund(adminID, order.ID, "test")
+				case "refund":
+					_, err = f.payments.Refund(adminID, order.ID)
+				}
+				if !errors.Is(err, step.wantErr) {
+					t.Fatalf("step %d (%s): error = %v, want %v", i, step.call, err, step.wantErr)
+				}
+
+				got, err := f.payments.Get(order.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != step.wantStatus {
+					t.Fatalf("step %d (%s): status = %s, want %s", i, step.call, got.Status, step.wantStatus)
+				}
+				// Доступ к курсу есть, пока заказ оплачен; во время возврата он уже закрыт
+				if allowed, want := f.payments.CourseAllowed(userID, f.course), got.Status == domain.OrderPaid; allowed != want {
+					t.Fatalf("step %d (%s): course allowed = %v, want %v", i, step.call, allowed, want)
+				}
+			}
+		})
+	}
+}
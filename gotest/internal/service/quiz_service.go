@@ -0,0 +1,394 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+const (
+	maxQuizQuestions   = 20  // Максимум вопросов в тесте
+	maxQuizQuestion    = 300 // Максимальная длина вопроса (лимит опроса Telegram)
+	maxQuizOption      = 100 // Максимальная длина варианта ответа (лимит опроса Telegram)
+	maxQuizOptions     = 10  // Максимум вариантов ответа (лимит опроса Telegram)
+	maxQuizExplanation = 200 // Максимальная длина пояснения (лимит опроса Telegram)
+	maxQuizAttempts    = 10  // Максимальный лимит попыток
+)
+
+var (
+	// ErrQuizNotFound — у урока нет теста
+	ErrQuizNotFound = errors.New("тест не найден")
+	// ErrQuizInvalid — тест не прошёл проверку (подробности — в тексте ошибки)
+	ErrQuizInvalid = errors.New("некорректный тест")
+	// ErrQuizNoAttempts — попытки пройти тест закончились
+	ErrQuizNoAttempts = errors.New("попытки пройти тест закончились")
+	// ErrQuizNotStarted — ответ пришёл на вопрос теста, который сейчас не идёт
+	ErrQuizNotStarted = errors.New("тест не начат или вопрос уже пройден")
+)
+
+// QuizOutcome — итог ответа на вопрос теста
+type QuizOutcome struct {
+	Question domain.QuizQuestion // Вопрос, на который ответил пользователь
+	Correct  bool                // Ответ правильный
+	Session  domain.QuizSession  // Попытка после ответа
+
+	// Заполняются, когда ответ был на последний вопрос
+	Finished        bool
+	Result          domain.QuizResult     // Результаты пользователя по тесту
+	Progress        domain.CourseProgress // Прогресс по курсу после теста
+	CourseCompleted bool                  // Этим тестом пройден весь курс
+}
+
+// pollRef связывает опрос Telegram с вопросом попытки пользователя
+type pollRef struct {
+	userID   int64
+	question int
+}
+
+// QuizService управляет тестами уроков и попытками их прохождения
+// Идущие попытки хранятся в памяти и сбрасываются при перезапуске бота
+type QuizService struct {
+	quizzes     *repository.QuizRepository
+	lessons     *LessonService
+	enrollments *EnrollmentService
+	audit       *AuditService
+
+	mu       sync.Mutex
+	sessions map[int64]*domain.QuizSession // Ключ - ID пользователя
+	polls    map[string]pollRef            // Ключ - ID опроса Telegram
+}
+
+// NewQuizService создаёт сервис тестов
+func NewQuizService(quizzes *repository.QuizRepository, lessons *LessonService, enrollments *EnrollmentService, audit *AuditService) *QuizService {
+	return &QuizService{
+		quizzes:     quizzes,
+		lessons:     lessons,
+		enrollments: enrollments,
+		audit:       audit,
+		sessions:    make(map[int64]*domain.QuizSession),
+		polls:       make(map[string]pollRef),
+	}
+}
+
+// SeedIfEmpty заполняет пустое хранилище тестами seed (при первом запуске)
+// Тесты уроков, которых нет или которые относятся к другому курсу, пропускаются
+func (s *QuizService) SeedIfEmpty(seed []domain.Quiz) error {
+	if s.quizzes.Count() > 0 {
+		return nil
+	}
+
+	added := 0
+	for i := range seed {
+		lesson, err := s.lessons.Get(seed[i].LessonID)
+		if err != nil || lesson.CourseID != seed[i].CourseID {
+			continue
+		}
+		seed[i].UpdatedAt = time.Now()
+		if err := s.quizzes.Save(&seed[i]); err != nil {
+			return fmt.Errorf("ошибка добавления теста урока %d: %w", seed[i].LessonID, err)
+		}
+		added++
+	}
+	if added > 0 {
+		log.Printf("Тесты заполнены начальными данными: %d тестов", added)
+	}
+	return nil
+}
+
+// ForLesson возвращает тест урока
+// Второе значение false, если у урока нет теста
+func (s *QuizService) ForLesson(lessonID int) (domain.Quiz, bool) {
+	return s.quizzes.ByLesson(lessonID)
+}
+
+// Result возвращает результаты пользователя по тесту
+func (s *QuizService) Result(userID int64, quiz domain.Quiz) domain.QuizResult {
+	enrollment, _ := s.enrollments.Get(userID, quiz.CourseID)
+	return enrollment.Quizzes[quiz.ID]
+}
+
+// Save проверяет и сохраняет тест урока от имени actorID
+// Если у урока уже есть тест, он заменяется; результаты пользователей сохраняются
+func (s *QuizService) Save(actorID int64, quiz *domain.Quiz) error {
+	err := s.save(actorID, quiz)
+	s.audit.Record(actorID, domain.AuditQuizUpdate, int64(quiz.LessonID), map[string]string{
+		"questions": strconv.Itoa(len(quiz.Questions)),
+		"mode":      string(quiz.Mode),
+	}, err)
+	return err
+}
+
+// save сохраняет тест без записи в журнал
+func (s *QuizService) save(actorID int64, quiz *domain.Quiz) error {
+	if err := ValidateQuiz(*quiz); err != nil {
+		return err
+	}
+	lesson, err := s.lessons.Get(quiz.LessonID)
+	if err != nil {
+		return err
+	}
+
+	existing, exists := s.quizzes.ByLesson(quiz.LessonID)
+	quiz.ID = 0
+	if exists {
+		quiz.ID = existing.ID
+	}
+	quiz.CourseID = lesson.CourseID
+	quiz.UpdatedBy = actorID
+	quiz.UpdatedAt = time.Now()
+
+	return s.quizzes.Save(quiz)
+}
+
+// Delete удаляет тест урока от имени actorID
+func (s *QuizService) Delete(actorID int64, lessonID int) error {
+	err := ErrQuizNotFound
+	if quiz, exists := s.quizzes.ByLesson(lessonID); exists {
+		_, err = s.quizzes.Delete(quiz.ID)
+	}
+	s.audit.Record(actorID, domain.AuditQuizDelete, int64(lessonID), nil, err)
+	return err
+}
+
+// ReturnAttempts от имени actorID возвращает пользователю userID n попыток теста урока lessonID (0 — все)
+func (s *QuizService) ReturnAttempts(actorID, userID int64, lessonID, n int) (domain.QuizResult, error) {
+	result, err := s.returnAttempts(userID, lessonID, n)
+	s.audit.Record(actorID, domain.AuditQuizAttempts, userID, map[string]string{
+		"lesson":   strconv.Itoa(lessonID),
+		"returned": strconv.Itoa(n),
+	}, err)
+	return result, err
+}
+
+// returnAttempts возвращает попытки без записи в журнал
+func (s *QuizService) returnAttempts(userID int64, lessonID, n int) (domain.QuizResult, error) {
+	if n < 0 || n > maxQuizAttempts {
+		return domain.QuizResult{}, fmt.Errorf("%w: вернуть можно от 1 до %d попыток (0 — все)", ErrQuizInvalid, maxQuizAttempts)
+	}
+	quiz, exists := s.quizzes.ByLesson(lessonID)
+	if !exists {
+		return domain.QuizResult{}, ErrQuizNotFound
+	}
+
+	result, err := s.enrollments.ReturnQuizAttempts(userID, quiz.CourseID, quiz.ID, n)
+	if err != nil {
+		return domain.QuizResult{}, err
+	}
+
+	log.Printf("Пользователю %d возвращены попытки теста %d, использовано: %d", userID, quiz.ID, result.Attempts)
+	return result, nil
+}
+
+// Start начинает новую попытку теста урока
+// Незавершённая попытка другого теста отменяется
+func (s *QuizService) Start(userID, chatID int64, courseID, lessonID int) (domain.QuizSession, error) {
+	quiz, exists := s.quizzes.ByLesson(lessonID)
+	if !exists || quiz.CourseID != courseID {
+		return domain.QuizSession{}, ErrQuizNotFound
+	}
+
+	// Проверяем запись на курс и что урок уже открыт
+	if _, _, err := s.enrollments.OpenLesson(userID, courseID, lessonID); err != nil {
+		return domain.QuizSession{}, err
+	}
+	// Лимит ограничивает попытки сдать тест: сданный тест можно пройти ещё раз,
+	// например после повторной записи на курс, когда урок нужно засчитать заново
+	if result := s.Result(userID, quiz); !result.Passed && result.AttemptsLeft(quiz) == 0 {
+		return domain.QuizSession{}, ErrQuizNoAttempts
+	}
+
+	session := &domain.QuizSession{
+		Quiz:      quiz,
+		ChatID:    chatID,
+		StartedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[userID] = session
+	return *session, nil
+}
+
+// Cancel прерывает попытку пользователя без сохранения результата
+func (s *QuizService) Cancel(userID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, userID)
+}
+
+// Toggle отмечает или снимает отметку с варианта в вопросе с несколькими ответами
+func (s *QuizService) Toggle(userID int64, question, option int) (domain.QuizSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, err := s.current(userID, question)
+	if err != nil {
+		return domain.QuizSession{}, err
+	}
+	if option < 0 || option >= len(session.Quiz.Questions[question].Options) {
+		return domain.QuizSession{}, ErrQuizNotStarted
+	}
+
+	if i := slices.Index(session.Selected, option); i >= 0 {
+		session.Selected = slices.Delete(session.Selected, i, i+1)
+	} else {
+		session.Selected = append(session.Selected, option)
+	}
+	return *session, nil
+}
+
+// Answer принимает ответ на вопрос question
+// Если options равен nil, используются отмеченные через Toggle варианты
+func (s *QuizService) Answer(userID int64, question int, options []int) (QuizOutcome, error) {
+	s.mu.Lock()
+	session, err := s.current(userID, question)
+	if err != nil {
+		s.mu.Unlock()
+		return QuizOutcome{}, err
+	}
+
+	if options == nil {
+		options = session.Selected
+	}
+	outcome := QuizOutcome{Question: session.Quiz.Questions[question]}
+	outcome.Correct = outcome.Question.Check(options)
+	if outcome.Correct {
+		session.Correct++
+	}
+	session.Question++
+	session.Selected = nil
+	outcome.Session = *session
+
+	outcome.Finished = session.Question >= len(session.Quiz.Questions)
+	if outcome.Finished {
+		delete(s.sessions, userID)
+	}
+	s.mu.Unlock()
+
+	if !outcome.Finished {
+		return outcome, nil
+	}
+	if err := s.finish(userID, &outcome); err != nil {
+		return QuizOutcome{}, err
+	}
+	return outcome, nil
+}
+
+// BindPoll запоминает, на какой вопрос попытки пользователя отправлен опрос
+func (s *QuizService) BindPoll(pollID string, userID int64, question int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.polls[pollID] = pollRef{userID: userID, question: question}
+}
+
+// AnswerPoll принимает ответ пользователя в опросе Telegram
+// Второе значение false, если опрос не относится к тестам
+func (s *QuizService) AnswerPoll(pollID string, userID int64, options []int) (QuizOutcome, bool, error) {
+	s.mu.Lock()
+	ref, exists := s.polls[pollID]
+	if exists {
+		delete(s.polls, pollID)
+	}
+	s.mu.Unlock()
+
+	if !exists || ref.userID != userID {
+		return QuizOutcome{}, false, nil
+	}
+	if options == nil {
+		options = []int{} // Отозванный голос — неверный ответ, а не выбор через Toggle
+	}
+	outcome, err := s.Answer(userID, ref.question, options)
+	return outcome, true, err
+}
+
+// current возвращает попытку пользователя, если она ждёт ответа на вопрос question
+// Вызывается под s.mu
+func (s *QuizService) current(userID int64, question int) (*domain.QuizSession, error) {
+	session, exists := s.sessions[userID]
+	if !exists || session.Question != question {
+		return nil, ErrQuizNotStarted
+	}
+	return session, nil
+}
+
+// finish сохраняет результат попытки
+// Урок засчитывается только при пройденном тесте: без этого курс не считается пройденным
+// и сертификат не выдаётся, даже если попытки закончились
+func (s *QuizService) finish(userID int64, outcome *QuizOutcome) error {
+	quiz := outcome.Session.Quiz
+	courseID := quiz.CourseID
+
+	before, _ := s.enrollments.Get(userID, courseID)
+	result, err := s.enrollments.RecordQuiz(userID, courseID, quiz, outcome.Session.Score())
+	if err != nil {
+		return err
+	}
+	outcome.Result = result
+
+	if result.Passed {
+		outcome.Progress, err = s.enrollments.CompleteLesson(userID, courseID, quiz.LessonID)
+	} else {
+		outcome.Progress, err = s.enrollments.Progress(userID, courseID)
+	}
+	if err != nil {
+		return err
+	}
+
+	outcome.CourseCompleted = !before.Completed() && outcome.Progress.Enrollment.Completed()
+	log.Printf("Пользователь %d завершил тест %d: %d%%", userID, quiz.ID, outcome.Session.Score())
+	return nil
+}
+
+// ValidateQuiz проверяет тест, который заполняет администратор
+func ValidateQuiz(quiz domain.Quiz) error {
+	if quiz.Mode != domain.QuizButtons && quiz.Mode != domain.QuizPoll {
+		return fmt.Errorf("%w: режим может быть buttons или poll", ErrQuizInvalid)
+	}
+	if quiz.PassPercent < 1 || quiz.PassPercent > 100 {
+		return fmt.Errorf("%w: проходной балл — от 1 до 100%%", ErrQuizInvalid)
+	}
+	if quiz.MaxAttempts < 0 || quiz.MaxAttempts > maxQuizAttempts {
+		return fmt.Errorf("%w: лимит попыток — от 0 (без ограничений) до %d", ErrQuizInvalid, maxQuizAttempts)
+	}
+	if len(quiz.Questions) == 0 || len(quiz.Questions) > maxQuizQuestions {
+		return fmt.Errorf("%w: в тесте должно быть от 1 до %d вопросов", ErrQuizInvalid, maxQuizQuestions)
+	}
+
+	for i, question := range quiz.Questions {
+		n := i + 1
+		if strings.TrimSpace(question.Text) == "" || utf8.RuneCountInString(question.Text) > maxQuizQuestion {
+			return fmt.Errorf("%w: вопрос %d должен быть от 1 до %d символов", ErrQuizInvalid, n, maxQuizQuestion)
+		}
+		if len(question.Options) < 2 || len(question.Options) > maxQuizOptions {
+			return fmt.Errorf("%w: в вопросе %d должно быть от 2 до %d вариантов", ErrQuizInvalid, n, maxQuizOptions)
+		}
+		for _, option := range question.Options {
+			if strings.TrimSpace(option) == "" || utf8.RuneCountInString(option) > maxQuizOption {
+				return fmt.Errorf("%w: варианты вопроса %d должны быть от 1 до %d символов", ErrQuizInvalid, n, maxQuizOption)
+			}
+		}
+		if len(question.Correct) == 0 {
+			return fmt.Errorf("%w: в вопросе %d не отмечен правильный ответ", ErrQuizInvalid, n)
+		}
+		for j, correct := range question.Correct {
+			if correct < 0 || correct >= len(question.Options) || slices.Contains(question.Correct[:j], correct) {
+				return fmt.Errorf("%w: неверные правильные ответы в вопросе %d", ErrQuizInvalid, n)
+			}
+		}
+		if utf8.RuneCountInString(question.Explanation) > maxQuizExplanation {
+			return fmt.Errorf("%w: пояснение к вопросу %d длиннее %d символов", ErrQuizInvalid, n, maxQuizExplanation)
+		}
+	}
+	return nil
+}
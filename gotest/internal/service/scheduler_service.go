@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// schedulerTick — как часто планировщик проверяет, не пора ли запустить рассылку
+const schedulerTick = 30 * time.Second
+
+// oneOffLayout — формат времени разовой рассылки
+const oneOffLayout = "2006-01-02 15:04"
+
+// cronFields — число полей выражения cron: минуты, часы, день месяца, месяц, день недели
+const cronFields = 5
+
+var (
+	// ErrScheduleNotFound — запланированная рассылка не найдена
+	ErrScheduleNotFound = errors.New("запланированная рассылка не найдена")
+	// ErrScheduleInPast — время разовой рассылки уже прошло
+	ErrScheduleInPast = errors.New("время рассылки уже прошло")
+)
+
+// SchedulerService запускает запланированные рассылки через BroadcastService
+//
+// Рассылка запускается не больше одного раза: перед запуском время следующего запуска
+// сохраняется в хранилище, а BroadcastService сохраняет позицию до отправки каждому получателю.
+// Если бот упадёт между сохранением расписания и запуском, этот запуск будет пропущен;
+// упавшая посреди рассылка продолжится со следующего получателя без повторных отправок
+type SchedulerService struct {
+	jobs       *repository.ScheduleRepository
+	broadcasts *BroadcastService
+	audit      *AuditService
+	location   *time.Location // Часовой пояс по умолчанию
+	mu         sync.Mutex     // Не даёт изменить задание во время его запуска
+}
+
+// NewSchedulerService создаёт планировщик
+// location - часовой пояс для расписаний, где он не указан явно
+func NewSchedulerService(
+	jobs *repository.ScheduleRepository,
+	broadcasts *BroadcastService,
+	audit *AuditService,
+	location *time.Location,
+) *SchedulerService {
+	return &SchedulerService{
+		jobs:       jobs,
+		broadcasts: broadcasts,
+		audit:      audit,
+		location:   location,
+	}
+}
+
+// Start запускает фоновую проверку расписания
+// Рассылки, время которых наступило, пока бот был выключен, запускаются один раз
+func (s *SchedulerService) Start() {
+	go func() {
+		s.runDue(time.Now())
+		ticker := time.NewTicker(schedulerTick)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			s.runDue(now)
+		}
+	}()
+	log.Println("Планировщик рассылок запущен")
+}
+
+// Schedule разбирает расписание spec и сохраняет задание
+//
+// Поддерживаемые форматы spec:
+//   - "2026-01-31 09:00" или "2026-01-31 09:00 Europe/Moscow" — разовая рассылка
+//   - "0 9 * * 1" или "CRON_TZ=Europe/Moscow 0 9 * * 1" — повторяющаяся по cron
+//     (5 полей: минуты, часы, день месяца, месяц, день недели; @every и @daily не поддерживаются)
+func (s *SchedulerService) Schedule(job *domain.ScheduledBroadcast, spec string) error {
+	err := s.schedule(job, spec)
+	s.audit.Record(job.CreatedBy, domain.AuditScheduleCreate, job.ID, map[string]string{
+		"spec":    spec,
+		"segment": string(job.Segment.Kind),
+	}, err)
+	return err
+}
+
+// schedule разбирает расписание и сохраняет задание без записи в журнал
+func (s *SchedulerService) schedule(job *domain.ScheduledBroadcast, spec string) error {
+	now := time.Now()
+	spec = strings.TrimSpace(spec)
+
+	if runAt, location, ok := s.parseOneOff(spec); ok {
+		if !runAt.After(now) {
+			return ErrScheduleInPast
+		}
+		job.Cron = ""
+		job.TimeZone = location.String()
+		job.NextRunAt = runAt
+	} else {
+		expr, location, err := s.parseCron(spec)
+		if err != nil {
+			return err
+		}
+		schedule, err := cronSchedule(expr, location.String())
+		if err != nil {
+			return err
+		}
+		job.Cron = expr
+		job.TimeZone = location.String()
+		job.NextRunAt = schedule.Next(now)
+	}
+
+	job.Status = domain.ScheduleActive
+	job.CreatedAt = now
+	if err := s.jobs.Create(job); err != nil {
+		return err
+	}
+
+	log.Printf("Рассылка #%d запланирована администратором %d на %s", job.ID, job.CreatedBy, job.NextRunAt.Format(time.RFC3339))
+	return nil
+}
+
+// List возвращает активные и приостановленные рассылки
+func (s *SchedulerService) List() []domain.ScheduledBroadcast {
+	return s.jobs.Pending()
+}
+
+// Pause приостанавливает рассылку id от имени actorID
+func (s *SchedulerService) Pause(actorID, id int64) error {
+	err := s.setStatus(id, domain.SchedulePaused)
+	s.audit.Record(actorID, domain.AuditSchedulePause, id, nil, err)
+	return err
+}
+
+// Resume возобновляет приостановленную рассылку id от имени actorID
+// Для повторяющейся рассылки следующий запуск пересчитывается от текущего момента
+func (s *SchedulerService) Resume(actorID, id int64) error {
+	err := s.resume(id)
+	s.audit.Record(actorID, domain.AuditScheduleResume, id, nil, err)
+	return err
+}
+
+// resume возобновляет рассылку без записи в журнал
+func (s *SchedulerService) resume(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs.Get(id)
+	if !exists || job.Status != domain.SchedulePaused {
+		return ErrScheduleNotFound
+	}
+
+	if job.Recurring() {
+		schedule, err := cronSchedule(job.Cron, job.TimeZone)
+		if err != nil {
+			return err
+		}
+		job.NextRunAt = schedule.Next(time.Now())
+	}
+
+	job.Status = domain.ScheduleActive
+	return s.jobs.Update(job)
+}
+
+// Cancel отменяет рассылку id от имени actorID
+func (s *SchedulerService) Cancel(actorID, id int64) error {
+	err := s.setStatus(id, domain.ScheduleCancelled)
+	s.audit.Record(actorID, domain.AuditScheduleCancel, id, nil, err)
+	return err
+}
+
+// setStatus меняет состояние ожидающей рассылки
+func (s *SchedulerService) setStatus(id int64, status domain.ScheduleStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs.Get(id)
+	if !exists || (job.Status != domain.ScheduleActive && job.Status != domain.SchedulePaused) {
+		return ErrScheduleNotFound
+	}
+
+	job.Status = status
+	return s.jobs.Update(job)
+}
+
+// runDue запускает рассылки, время которых наступило
+func (s *SchedulerService) runDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs.Pending() {
+		if job.Status != domain.ScheduleActive || job.NextRunAt.After(now) {
+			continue
+		}
+
+		// Сначала сохраняем следующий запуск, затем запускаем рассылку:
+		// если бот упадёт между этими шагами, рассылка будет пропущена, но не отправлена дважды
+		job.LastRunAt = now
+		job.Runs++
+		if job.Recurring() {
+			schedule, err := cronSchedule(job.Cron, job.TimeZone)
+			if err != nil {
+				log.Printf("Рассылка #%d: ошибка расписания: %v", job.ID, err)
+				continue
+			}
+			job.NextRunAt = schedule.Next(now)
+		} else {
+			job.Status = domain.ScheduleDone
+		}
+		if err := s.jobs.Update(job); err != nil {
+			log.Printf("Рассылка #%d: ошибка сохранения расписания, запуск пропущен: %v", job.ID, err)
+			continue
+		}
+
+		broadcast := &domain.Broadcast{
+			CreatedBy:    job.CreatedBy,
+			ReportChatID: job.ReportChatID,
+			Message:      job.Message,
+			Segment:      job.Segment,
+		}
+		if err := s.broadcasts.Start(broadcast); err != nil {
+			log.Printf("Рассылка #%d: ошибка запуска: %v", job.ID, err)
+		}
+	}
+}
+
+// parseOneOff пытается разобрать время разовой рассылки с необязательным часовым поясом
+func (s *SchedulerService) parseOneOff(spec string) (time.Time, *time.Location, bool) {
+	fields := strings.Fields(spec)
+	if len(fields) != 2 && len(fields) != 3 {
+		return time.Time{}, nil, false
+	}
+
+	location := s.location
+	if len(fields) == 3 {
+		loc, err := time.LoadLocation(fields[2])
+		if err != nil {
+			return time.Time{}, nil, false
+		}
+		location = loc
+	}
+
+	runAt, err := time.ParseInLocation(oneOffLayout, fields[0]+" "+fields[1], location)
+	if err != nil {
+		return time.Time{}, nil, false
+	}
+	return runAt, location, true
+}
+
+// parseCron отделяет от выражения cron необязательный префикс CRON_TZ=... и проверяет выражение
+func (s *SchedulerService) parseCron(spec string) (string, *time.Location, error) {
+	location := s.location
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		prefix, rest, _ := strings.Cut(spec, " ")
+		_, tz, _ := strings.Cut(prefix, "=")
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return "", nil, fmt.Errorf("неизвестный часовой пояс %q", tz)
+		}
+		location = loc
+		spec = strings.TrimSpace(rest)
+	}
+
+	// ParseStandard принимает и описатели вроде @every 1h, но в подсказке администратору их нет
+	if strings.HasPrefix(spec, "@") || len(strings.Fields(spec)) != cronFields {
+		return "", nil, fmt.Errorf("неверное расписание %q: нужно 5 полей cron (минуты часы день месяц день_недели)", spec)
+	}
+	if _, err := cron.ParseStandard(spec); err != nil {
+		return "", nil, fmt.Errorf("неверное расписание %q: %w", spec, err)
+	}
+	return spec, location, nil
+}
+
+// cronSchedule создаёт расписание cron в указанном часовом поясе
+func cronSchedule(expr, timeZone string) (cron.Schedule, error) {
+	return cron.ParseStandard("CRON_TZ=" + timeZone + " " + expr)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// topLimit — сколько самых популярных команд и callback показывать
+const topLimit = 5
+
+// statsWindowDays — за сколько дней показывается статистика; более старые счётчики удаляются
+const statsWindowDays = 7
+
+// eventsFlushInterval — как часто счётчики событий сохраняются в хранилище
+const eventsFlushInterval = time.Minute
+
+// Counter — имя и количество (для топов команд и callback)
+type Counter struct {
+	Name  string
+	Count int
+}
+
+// Stats — снимок статистики бота для админ-панели
+type Stats struct {
+	TotalUsers      int            // Всего пользователей
+	NewToday        int            // Новых пользователей за сутки
+	NewWeek         int            // Новых пользователей за неделю
+	ActiveToday     int            // Активных пользователей за сутки
+	ActiveWeek      int            // Активных пользователей за неделю
+	Languages       map[string]int // Распределение по языку интерфейса
+	NotificationsOn int            // Пользователей с включёнными уведомлениями
+	MessagesWeek    int            // Текстовых сообщений за неделю
+	TopCommands     []Counter      // Самые популярные команды за неделю
+	TopCallbacks    []Counter      // Самые популярные callback-маршруты за неделю
+	ErrorsToday     int            // Ошибок за сегодня
+	ErrorsWeek      int            // Ошибок за неделю
+}
+
+// StatsService собирает события и считает статистику
+type StatsService struct {
+	users    *repository.UserRepository
+	settings *repository.SettingsRepository
+	events   *repository.EventRepository
+}
+
+// NewStatsService создаёт сервис статистики
+func NewStatsService(
+	users *repository.UserRepository,
+	settings *repository.SettingsRepository,
+	events *repository.EventRepository,
+) *StatsService {
+	return &StatsService{
+		users:    users,
+		settings: settings,
+		events:   events,
+	}
+}
+
+// TrackUser сохраняет пользователя и время его активности
+func (s *StatsService) TrackUser(from *tgbotapi.User) {
+	if from == nil {
+		return
+	}
+	if _, err := s.users.Touch(from, time.Now()); err != nil {
+		log.Printf("Ошибка сохранения пользователя %d: %v", from.ID, err)
+	}
+}
+
+// Track учитывает событие в статистике
+// name - команда или данные callback (для сообщений и ошибок — пустая строка)
+func (s *StatsService) Track(eventType domain.EventType, name string) {
+	if eventType == domain.EventCallback {
+		name = CallbackRoute(name)
+	}
+	s.events.Add(eventType, name, time.Now())
+}
+
+// StartFlush запускает периодическое сохранение счётчиков событий
+func (s *StatsService) StartFlush() {
+	go func() {
+		ticker := time.NewTicker(eventsFlushInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			s.flush(now)
+		}
+	}()
+}
+
+// Flush сохраняет несохранённые счётчики событий (например, перед остановкой бота)
+func (s *StatsService) Flush() {
+	s.flush(time.Now())
+}
+
+// flush удаляет счётчики за пределами окна статистики и сохраняет остальные
+func (s *StatsService) flush(now time.Time) {
+	if err := s.events.Flush(now, statsWindowDays); err != nil {
+		log.Printf("Ошибка сохранения счётчиков событий: %v", err)
+	}
+}
+
+// Collect считает статистику на момент now
+func (s *StatsService) Collect(now time.Time) Stats {
+	stats := Stats{Languages: make(map[string]int)}
+
+	dayAgo := now.Add(-24 * time.Hour)
+	weekAgo := now.AddDate(0, 0, -7)
+
+	for _, user := range s.users.All() {
+		stats.TotalUsers++
+		if user.CreatedAt.After(dayAgo) {
+			stats.NewToday++
+		}
+		if user.CreatedAt.After(weekAgo) {
+			stats.NewWeek++
+		}
+		if user.LastSeenAt.After(dayAgo) {
+			stats.ActiveToday++
+		}
+		if user.LastSeenAt.After(weekAgo) {
+			stats.ActiveWeek++
+		}
+
+		settings := s.settings.Get(user.ID)
+		stats.Languages[settings.Language]++
+		if settings.NotificationsEnabled {
+			stats.NotificationsOn++
+		}
+	}
+
+	today := s.events.Since(now, 1)
+	week := s.events.Since(now, statsWindowDays)
+
+	stats.MessagesWeek = week.Messages
+	stats.TopCommands = topCounters(week.Commands, topLimit)
+	stats.TopCallbacks = topCounters(week.Callbacks, topLimit)
+	stats.ErrorsToday = today.Errors
+	stats.ErrorsWeek = week.Errors
+
+	return stats
+}
+
+// CallbackRoute убирает из данных callback числовые параметры
+// Например: "courses_page_2" -> "courses_page", "course_7" -> "course"
+func CallbackRoute(data string) string {
+	parts := strings.Split(data, "_")
+	for len(parts) > 1 && isNumber(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "_")
+}
+
+// isNumber проверяет, что строка состоит только из цифр (допускается минус)
+func isNumber(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// topCounters возвращает limit самых больших счётчиков по убыванию
+func topCounters(counts map[string]int, limit int) []Counter {
+	counters := make([]Counter, 0, len(counts))
+	for name, count := range counts {
+		counters = append(counters, Counter{Name: name, Count: count})
+	}
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].Count != counters[j].Count {
+			return counters[i].Count > counters[j].Count
+		}
+		return counters[i].Name < counters[j].Name
+	})
+	if len(counters) > limit {
+		counters = counters[:limit]
+	}
+	return counters
+}
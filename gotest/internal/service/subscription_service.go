@@ -0,0 +1,333 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// maxSubscriptionDays — максимальный срок, на который можно выдать или продлить подписку за раз
+const maxSubscriptionDays = 3650
+
+var (
+	// ErrNoSubscription — у пользователя нет подписки
+	ErrNoSubscription = errors.New("у пользователя нет подписки")
+	// ErrInvalidDays — некорректный срок подписки
+	ErrInvalidDays = fmt.Errorf("срок должен быть от 1 до %d дней", maxSubscriptionDays)
+)
+
+// SubscriptionReminder — уведомление пользователю о скором или наступившем окончании подписки
+type SubscriptionReminder struct {
+	Subscription domain.Subscription
+	Plan         domain.Plan
+	DaysLeft     int // 0 — подписка закончилась
+}
+
+// SubscriptionService управляет тарифными планами и подписками пользователей
+type SubscriptionService struct {
+	subscriptions *repository.SubscriptionRepository
+	audit         *AuditService
+	remindDays    []int      // За сколько дней до окончания напоминать, по убыванию
+	mu            sync.Mutex // Последовательные изменения подписок
+}
+
+// NewSubscriptionService создаёт сервис подписок
+// remindDays - за сколько дней до окончания подписки отправлять напоминания
+func NewSubscriptionService(subscriptions *repository.SubscriptionRepository, audit *AuditService, remindDays []int) *SubscriptionService {
+	days := slices.Clone(remindDays)
+	slices.Sort(days)
+	slices.Reverse(days)
+
+	return &SubscriptionService{
+		subscriptions: subscriptions,
+		audit:         audit,
+		remindDays:    days,
+	}
+}
+
+// SeedIfEmpty заполняет пустое хранилище тарифными планами seed (при первом запуске)
+func (s *SubscriptionService) SeedIfEmpty(seed []domain.Plan) error {
+	if s.subscriptions.PlanCount() > 0 {
+		return nil
+	}
+
+	for _, plan := range seed {
+		if err := s.subscriptions.SavePlan(plan); err != nil {
+			return fmt.Errorf("ошибка добавления плана %s: %w", plan.ID, err)
+		}
+	}
+	if len(seed) > 0 {
+		log.Printf("Тарифные планы заполнены начальными данными: %d планов", len(seed))
+	}
+	return nil
+}
+
+// Plans возвращает тарифные планы в порядке отображения
+// activeOnly - только планы, доступные для оформления
+func (s *SubscriptionService) Plans(activeOnly bool) []domain.Plan {
+	plans := s.subscriptions.Plans()
+	if activeOnly {
+		plans = slices.DeleteFunc(plans, func(plan domain.Plan) bool { return !plan.Active })
+	}
+	return plans
+}
+
+// Plan возвращает тарифный план по ID
+func (s *SubscriptionService) Plan(id string) (domain.Plan, error) {
+	return s.subscriptions.Plan(id)
+}
+
+// Get возвращает подписку пользователя (в том числе закончившуюся) и её план
+// Второе значение false, если пользователь никогда не был подписан
+func (s *SubscriptionService) Get(userID int64) (domain.Subscription, domain.Plan, bool) {
+	subscription, exists := s.subscriptions.Get(userID)
+	if !exists {
+		return domain.Subscription{}, domain.Plan{}, false
+	}
+	return subscription, s.planOf(subscription), true
+}
+
+// planOf возвращает план подписки
+// Если план удалён из хранилища, возвращается план с ID вместо названия и без возможностей
+func (s *SubscriptionService) planOf(subscription domain.Subscription) domain.Plan {
+	plan, err := s.subscriptions.Plan(subscription.PlanID)
+	if err != nil {
+		return domain.Plan{ID: subscription.PlanID, Title: domain.LocalizedText{domain.DefaultLanguage: subscription.PlanID}}
+	}
+	return plan
+}
+
+// Has возвращает true, если у пользователя действующая подписка с возможностью feature
+func (s *SubscriptionService) Has(userID int64, feature string) bool {
+	subscription, plan, exists := s.Get(userID)
+	return exists && subscription.Active(time.Now()) && plan.Has(feature)
+}
+
+// CourseAllowed возвращает true, если пользователю доступен курс: бесплатный или по подписке
+func (s *SubscriptionService) CourseAllowed(userID int64, course domain.Course) bool {
+	return course.Free() || s.Has(userID, domain.PlanPaidCourses)
+}
+
+// Grant выдаёт пользователю подписку на план planID на days дней, начиная с текущего момента
+// Если days равно 0, используется срок плана. Прежняя подписка заменяется
+func (s *SubscriptionService) Grant(actorID, userID int64, planID string, days int) (domain.Subscription, error) {
+	subscription, err := s.grant(actorID, userID, planID, days)
+	s.audit.Record(actorID, domain.AuditSubscriptionGrant, userID, map[string]string{
+		"plan": planID,
+		"days": strconv.Itoa(days),
+	}, err)
+	return subscription, err
+}
+
+// grant выдаёт подписку без записи в журнал
+func (s *SubscriptionService) grant(actorID, userID int64, planID string, days int) (domain.Subscription, error) {
+	plan, err := s.subscriptions.Plan(planID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if days == 0 {
+		days = plan.Days
+	}
+	if days < 1 || days > maxSubscriptionDays {
+		return domain.Subscription{}, ErrInvalidDays
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	subscription := domain.Subscription{
+		UserID:    userID,
+		PlanID:    plan.ID,
+		StartedAt: now,
+		ExpiresAt: now.AddDate(0, 0, days),
+		GrantedBy: actorID,
+		UpdatedAt: now,
+	}
+	if err := s.subscriptions.Save(subscription); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	log.Printf("Пользователю %d выдана подписка %s до %s", userID, plan.ID, subscription.ExpiresAt.Format("2006-01-02"))
+	return subscription, nil
+}
+
+// Extend продлевает подписку пользователя на days дней
+// Действующая подписка продлевается с даты окончания, закончившаяся — с текущего момента
+func (s *SubscriptionService) Extend(actorID, userID int64, days int) (domain.Subscription, error) {
+	subscription, err := s.extend(userID, days)
+	s.audit.Record(actorID, domain.AuditSubscriptionExtend, userID, map[string]string{
+		"days": strconv.Itoa(days),
+	}, err)
+	return subscription, err
+}
+
+// extend продлевает подписку без записи в журнал
+func (s *SubscriptionService) extend(userID int64, days int) (domain.Subscription, error) {
+	if days < 1 || days > maxSubscriptionDays {
+		return domain.Subscription{}, ErrInvalidDays
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, exists := s.subscriptions.Get(userID)
+	if !exists {
+		return domain.Subscription{}, ErrNoSubscription
+	}
+
+	now := time.Now()
+	if !subscription.Active(now) {
+		subscription.StartedAt = now
+		subscription.ExpiresAt = now
+	}
+	subscription.ExpiresAt = subscription.ExpiresAt.AddDate(0, 0, days)
+	subscription.Reminded = 0
+	subscription.Expired = false
+	subscription.UpdatedAt = now
+	if err := s.subscriptions.Save(subscription); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	log.Printf("Подписка пользователя %d продлена до %s", userID, subscription.ExpiresAt.Format("2006-01-02"))
+	return subscription, nil
+}
+
+// Purchase оформляет оплаченную пользователем подписку на план planID на days дней (0 — срок плана)
+// Срок добавляется к оставшимся дням действующей подписки, даже если план меняется:
+// оплаченное время не теряется, а возможности нового плана действуют сразу
+func (s *SubscriptionService) Purchase(userID int64, planID string, days int) (domain.Subscription, error) {
+	plan, err := s.subscriptions.Plan(planID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if days == 0 {
+		days = plan.Days
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	subscription, exists := s.subscriptions.Get(userID)
+	if !exists || !subscription.Active(now) {
+		subscription = domain.Subscription{UserID: userID, StartedAt: now, ExpiresAt: now}
+	}
+	if subscription.PlanID != plan.ID {
+		subscription.PlanID = plan.ID
+		subscription.GrantedBy = 0
+	}
+	subscription.ExpiresAt = subscription.ExpiresAt.AddDate(0, 0, days)
+	subscription.Reminded = 0
+	subscription.Expired = false
+	subscription.UpdatedAt = now
+	if err := s.subscriptions.Save(subscription); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	log.Printf("Пользователь %d оплатил подписку %s до %s", userID, plan.ID, subscription.ExpiresAt.Format("2006-01-02"))
+	return subscription, nil
+}
+
+// Revoke досрочно завершает подписку пользователя без уведомления об окончании
+func (s *SubscriptionService) Revoke(actorID, userID int64) error {
+	err := s.revoke(userID)
+	s.audit.Record(actorID, domain.AuditSubscriptionRevoke, userID, nil, err)
+	return err
+}
+
+// revoke завершает подписку без записи в журнал
+func (s *SubscriptionService) revoke(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, exists := s.subscriptions.Get(userID)
+	now := time.Now()
+	if !exists || !subscription.Active(now) {
+		return ErrNoSubscription
+	}
+
+	subscription.ExpiresAt = now
+	subscription.Expired = true
+	subscription.UpdatedAt = now
+	if err := s.subscriptions.Save(subscription); err != nil {
+		return err
+	}
+
+	log.Printf("Подписка пользователя %d отозвана", userID)
+	return nil
+}
+
+// shorten забирает days оплаченных дней подписки после возврата заказа, оплаченного в момент paidAt
+// Если дней не остаётся, подписка завершается. Подписку, которую сотрудник выдал заново
+// после оплаты, возврат не затрагивает: оплаченные дни в ней уже не учтены
+func (s *SubscriptionService) shorten(userID int64, days int, paidAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, exists := s.subscriptions.Get(userID)
+	now := time.Now()
+	if !exists || !subscription.Active(now) {
+		return nil
+	}
+	if subscription.GrantedBy != 0 && subscription.StartedAt.After(paidAt) {
+		return nil
+	}
+
+	subscription.ExpiresAt = subscription.ExpiresAt.AddDate(0, 0, -days)
+	if !subscription.Active(now) {
+		subscription.ExpiresAt = now
+		subscription.Expired = true
+	}
+	subscription.UpdatedAt = now
+	if err := s.subscriptions.Save(subscription); err != nil {
+		return err
+	}
+
+	log.Printf("Подписка пользователя %d сокращена на %d дн. до %s", userID, days, subscription.ExpiresAt.Format("2006-01-02"))
+	return nil
+}
+
+// Remind возвращает напоминания, которые пора отправить в момент now, и отмечает их отправленными:
+// о скором окончании подписки (за remindDays дней) и об окончании
+func (s *SubscriptionService) Remind(now time.Time) []SubscriptionReminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reminders []SubscriptionReminder
+	for _, subscription := range s.subscriptions.All() {
+		daysLeft := subscription.DaysLeft(now)
+		switch {
+		case daysLeft == 0 && subscription.Expired:
+			continue
+		case daysLeft == 0:
+			subscription.Expired = true
+		default:
+			// Ближайший порог: при 1 дне до окончания напоминание «за 1 день», а не «за 3 дня»
+			threshold := 0
+			for _, days := range s.remindDays {
+				if daysLeft <= days {
+					threshold = days
+				}
+			}
+			if threshold == 0 || (subscription.Reminded != 0 && subscription.Reminded <= threshold) {
+				continue
+			}
+			subscription.Reminded = threshold
+		}
+
+		if err := s.subscriptions.Save(subscription); err != nil {
+			log.Printf("Ошибка сохранения напоминания о подписке пользователя %d: %v", subscription.UserID, err)
+			continue
+		}
+		reminders = append(reminders, SubscriptionReminder{Subscription: subscription, Plan: s.planOf(subscription), DaysLeft: daysLeft})
+	}
+	return reminders
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+func TestSubscriptionServiceShorten(t *testing.T) {
+	const userID = 100
+
+	now := time.Now()
+	day := 24 * time.Hour
+	paidAt := now.Add(-2 * day)
+
+	tests := []struct {
+		name        string
+		existing    *domain.Subscription
+		days        int
+		wantExpires time.Time // Нулевое — подписки нет
+		wantExpired bool
+	}{
+		{
+			name: "no subscription",
+			days: 30,
+		},
+		{
+			name: "paid days removed",
+			existing: &domain.Subscription{
+				PlanID: "month", StartedAt: paidAt, ExpiresAt: now.Add(58 * day),
+			},
+			days:        30,
+			wantExpires: now.Add(28 * day),
+		},
+		{
+			name: "no days left",
+			existing: &domain.Subscription{
+				PlanID: "month", StartedAt: paidAt, ExpiresAt: now.Add(28 * day),
+			},
+			days:        30,
+			wantExpires: now,
+			wantExpired: true,
+		},
+		{
+			name: "already expired",
+			existing: &domain.Subscription{
+				PlanID: "month", StartedAt: now.Add(-40 * day), ExpiresAt: now.Add(-10 * day), Expired: true,
+			},
+			days:        30,
+			wantExpires: now.Add(-10 * day),
+			wantExpired: true,
+		},
+		{
+			name: "granted by staff after payment",
+			existing: &domain.Subscription{
+				PlanID: "year", StartedAt: now.Add(-day), ExpiresAt: now.Add(364 * day), GrantedBy: 1,
+			},
+			days:        30,
+			wantExpires: now.Add(364 * day),
+		},
+		{
+			name: "granted by staff before payment",
+			existing: &domain.Subscription{
+				PlanID: "month", StartedAt: now.Add(-3 * day), ExpiresAt: now.Add(57 * day), GrantedBy: 1,
+			},
+			days:        30,
+			wantExpires: now.Add(27 * day),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := repository.NewJSONStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			auditRepo, err := repository.NewAuditRepository(store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subscriptionRepo, err := repository.NewSubscriptionRepository(store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			subscriptions := NewSubscriptionService(subscriptionRepo, NewAuditService(auditRepo), nil)
+
+			if tt.existing != nil {
+				existing := *tt.existing
+				existing.UserID = userID
+				if err := subscriptionRepo.Save(existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := subscriptions.shorten(userID, tt.days, paidAt); err != nil {
+				t.Fatalf("shorten() error = %v", err)
+			}
+
+			got, exists := subscriptionRepo.Get(userID)
+			if tt.wantExpires.IsZero() {
+				if exists {
+					t.Fatalf("subscription created: %+v", got)
+				}
+				return
+			}
+			// now в сервисе чуть позже now в тесте
+			if diff := got.ExpiresAt.Sub(tt.wantExpires); diff < 0 || diff > time.Minute {
+				t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, tt.wantExpires)
+			}
+			if got.Expired != tt.wantExpired {
+				t.Errorf("Expired = %v, want %v", got.Expired, tt.wantExpired)
+			}
+		})
+	}
+}
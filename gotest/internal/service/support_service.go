@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"telegram-bot/internal/domain"
+	"telegram-bot/internal/repository"
+)
+
+// ErrTicketClosed — обращение уже закрыто
+var ErrTicketClosed = errors.New("обращение закрыто")
+
+// SupportService ведёт обращения пользователей в поддержку: статусы, ответственных и историю
+type SupportService struct {
+	tickets *repository.TicketRepository
+	audit   *AuditService
+}
+
+// NewSupportService создаёт сервис поддержки
+func NewSupportService(tickets *repository.TicketRepository, audit *AuditService) *SupportService {
+	return &SupportService{
+		tickets: tickets,
+		audit:   audit,
+	}
+}
+
+// Get возвращает обращение по ID
+func (s *SupportService) Get(id int) (domain.Ticket, error) {
+	return s.tickets.Get(id)
+}
+
+// List возвращает обращения со статусом status (пустой — все), новые первыми
+func (s *SupportService) List(status domain.TicketStatus) []domain.Ticket {
+	return s.tickets.List(status)
+}
+
+// Active возвращает незакрытое обращение пользователя
+func (s *SupportService) Active(userID int64) (domain.Ticket, bool) {
+	return s.tickets.ActiveByUser(userID)
+}
+
+// Open создаёт обращение пользователя с первым сообщением
+// Если у пользователя уже есть незакрытое обращение, сообщение добавляется в него
+// Второе значение true, если обращение создано
+func (s *SupportService) Open(userID, chatID int64, userName, text string) (domain.Ticket, bool, error) {
+	if ticket, exists := s.tickets.ActiveByUser(userID); exists {
+		ticket, err := s.UserMessage(ticket.ID, text)
+		return ticket, false, err
+	}
+
+	now := time.Now()
+	ticket := domain.Ticket{
+		UserID:    userID,
+		ChatID:    chatID,
+		UserName:  userName,
+		Status:    domain.TicketOpen,
+		Messages:  []domain.TicketMessage{{AuthorID: userID, Text: text, At: now}},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.tickets.Create(&ticket); err != nil {
+		return domain.Ticket{}, false, fmt.Errorf("ошибка создания обращения: %w", err)
+	}
+
+	log.Printf("Пользователь %d открыл обращение #%d", userID, ticket.ID)
+	return ticket, true, nil
+}
+
+// UserMessage добавляет сообщение пользователя в обращение
+// Обращение, ждавшее пользователя, снова ждёт ответа поддержки
+func (s *SupportService) UserMessage(id int, text string) (domain.Ticket, error) {
+	ticket, err := s.tickets.Get(id)
+	if err != nil {
+		return domain.Ticket{}, err
+	}
+	if !ticket.Active() {
+		return ticket, ErrTicketClosed
+	}
+
+	now := time.Now()
+	ticket.Messages = append(ticket.Messages, domain.TicketMessage{AuthorID: ticket.UserID, Text: text, At: now})
+	ticket.Status = domain.TicketOpen
+	ticket.UpdatedAt = now
+	return ticket, s.tickets.Update(ticket)
+}
+
+// Reply добавляет ответ сотрудника supportID в обращение
+// Первый ответивший сотрудник становится ответственным, если его ещё нет
+func (s *SupportService) Reply(supportID int64, id int, text string) (domain.Ticket, error) {
+	ticket, err := s.tickets.Get(id)
+	if err != nil {
+		return domain.Ticket{}, err
+	}
+	if !ticket.Active() {
+		return ticket, ErrTicketClosed
+	}
+
+	now := time.Now()
+	ticket.Messages = append(ticket.Messages, domain.TicketMessage{FromSupport: true, AuthorID: supportID, Text: text, At: now})
+	ticket.Status = domain.TicketPending
+	ticket.UpdatedAt = now
+	assigned := ticket.AssigneeID == 0
+	if assigned {
+		ticket.AssigneeID = supportID
+	}
+	err = s.tickets.Update(ticket)
+
+	if assigned {
+		s.audit.Record(supportID, domain.AuditTicketAssign, int64(id), map[string]string{"assignee": fmt.Sprint(supportID)}, err)
+	}
+	return ticket, err
+}
+
+// Assign назначает ответственным за обращение сотрудника assigneeID
+func (s *SupportService) Assign(actorID int64, id int, assigneeID int64) (domain.Ticket, error) {
+	ticket, err := s.tickets.Get(id)
+	if err == nil {
+		ticket.AssigneeID = assigneeID
+		ticket.UpdatedAt = time.Now()
+		err = s.tickets.Update(ticket)
+	}
+
+	s.audit.Record(actorID, domain.AuditTicketAssign, int64(id), map[string]string{"assignee": fmt.Sprint(assigneeID)}, err)
+	return ticket, err
+}
+
+// Close закрывает обращение от имени actorID (сотрудника или самого пользователя)
+func (s *SupportService) Close(actorID int64, id int) (domain.Ticket, error) {
+	ticket, err := s.tickets.Get(id)
+	if err == nil && !ticket.Active() {
+		err = ErrTicketClosed
+	}
+	if err == nil {
+		now := time.Now()
+		ticket.Status = domain.TicketClosed
+		ticket.UpdatedAt = now
+		ticket.ClosedAt = now
+		err = s.tickets.Update(ticket)
+	}
+
+	s.audit.Record(actorID, domain.AuditTicketClose, int64(id), nil, err)
+	return ticket, err
+}
+
+// Reopen снова открывает закрытое обращение
+func (s *SupportService) Reopen(actorID int64, id int) (domain.Ticket, error) {
+	ticket, err := s.tickets.Get(id)
+	if err == nil {
+		ticket.Status = domain.TicketOpen
+		ticket.UpdatedAt = time.Now()
+		ticket.ClosedAt = time.Time{}
+		err = s.tickets.Update(ticket)
+	}
+
+	s.audit.Record(actorID, domain.AuditTicketReopen, int64(id), nil, err)
+	return ticket, err
+}
+
+// Link связывает сообщение в чате с обращением, чтобы ответ на него попал в обращение
+func (s *SupportService) Link(chatID int64, messageID, ticketID int) error {
+	return s.tickets.Link(domain.TicketLink{ChatID: chatID, MessageID: messageID, TicketID: ticketID})
+}
+
+// ByMessage возвращает обращение, с которым связано сообщение
+// Второе значение false, если сообщение не связано ни с одним обращением
+func (s *SupportService) ByMessage(chatID int64, messageID int) (domain.Ticket, bool) {
+	id, exists := s.tickets.ByMessage(chatID, messageID)
+	if !exists {
+		return domain.Ticket{}, false
+	}
+	ticket, err := s.tickets.Get(id)
+	if err != nil {
+		return domain.Ticket{}, false
+	}
+	return ticket, true
+}